@@ -4,11 +4,19 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
+	"time"
 
 	"lms-backend/internal/config"
 	"lms-backend/internal/db"
+	"lms-backend/internal/notify"
+	"lms-backend/internal/ratelimit"
+	"lms-backend/internal/realtime"
 	"lms-backend/internal/repository"
 	"lms-backend/internal/service"
+	"lms-backend/internal/storage"
+	graphqlapi "lms-backend/internal/transport/graphql"
+	grpcapi "lms-backend/internal/transport/grpc"
 	httpapi "lms-backend/internal/transport/http"
 	"lms-backend/internal/transport/http/handlers"
 )
@@ -42,21 +50,109 @@ func main() {
 	courseRepo := repository.NewCourseRepo(pool)
 	enrollRepo := repository.NewEnrollmentRepo(pool)
 	attRepo := repository.NewAttendanceRepo(pool)
+	corrRepo := repository.NewAttendanceCorrectionRepo(pool)
+	statusRepo := repository.NewAttendanceStatusRepo(pool)
+	excuseRepo := repository.NewAttendanceExcuseRepo(pool)
+	checkinRepo := repository.NewCheckinRepo(pool)
+	policyRepo := repository.NewCheckinPolicyRepo(pool)
+	deviceRepo := repository.NewDeviceRepo(pool)
+	riskRepo := repository.NewRiskRepo(pool)
+	importRepo := repository.NewImportRepo(pool)
+	profileRepo := repository.NewProfileRepo(pool)
+	groupRepo := repository.NewGroupRepo(pool)
+	enrollReqRepo := repository.NewEnrollmentRequestRepo(pool)
+	reqRepo := repository.NewRequirementRepo(pool)
+	certRepo := repository.NewCertificateRepo(pool)
+	materialRepo := repository.NewMaterialRepo(pool)
+	searchRepo := repository.NewSearchRepo(pool)
+	idemRepo := repository.NewIdempotencyRepo(pool)
+	ticketRepo := repository.NewStreamTicketRepo(pool)
 
-	authSvc := service.NewAuthService(userRepo, roleRepo, cfg.JWT.Secret, cfg.JWT.AccessTTLMinutes)
-	userSvc := service.NewUserService(userRepo, roleRepo, authSvc)
-	courseSvc := service.NewCourseService(courseRepo, enrollRepo)
-	attSvc := service.NewAttendanceService(attRepo)
+	files, err := storage.NewLocalStore(cfg.Uploads.Dir)
+	if err != nil {
+		log.Fatal("uploads dir error: ", err)
+	}
+	mailer := notify.NewMailer(notify.SMTPOptions{
+		Host:     cfg.SMTP.Host,
+		Port:     cfg.SMTP.Port,
+		Username: cfg.SMTP.Username,
+		Password: cfg.SMTP.Password,
+		From:     cfg.SMTP.From,
+	})
+
+	authSvc := service.NewAuthService(userRepo, roleRepo, ticketRepo, cfg.JWT.Secret, cfg.JWT.AccessTTLMinutes)
+	userSvc := service.NewUserService(userRepo, roleRepo, authSvc, files)
+	courseSvc := service.NewCourseService(courseRepo, enrollRepo, reqRepo, attRepo)
+	attSvc := service.NewAttendanceService(attRepo, corrRepo, statusRepo, excuseRepo, enrollRepo)
+	excuseSvc := service.NewExcuseService(excuseRepo, enrollRepo, files, cfg.Uploads.MaxMB)
+	checkinSvc := service.NewCheckinService(checkinRepo, policyRepo, deviceRepo, enrollRepo)
+	analyticsSvc := service.NewAnalyticsService(attRepo, riskRepo, userRepo, roleRepo, mailer)
+	reportSvc := service.NewReportService(attRepo, courseRepo, statusRepo)
+	profileSvc := service.NewProfileService(profileRepo, groupRepo, userRepo, roleRepo, reqRepo, files, cfg.Uploads.MaxMB)
+	enrollSvc := service.NewEnrollmentService(enrollReqRepo, courseRepo, reqRepo)
+	reqSvc := service.NewRequirementService(reqRepo, courseRepo)
+	certSvc := service.NewCertificateService(certRepo, attRepo, cfg.Certificates.Secret, cfg.Certificates.Issuer, cfg.Certificates.VerifyURL)
+	materialSvc := service.NewMaterialService(materialRepo, courseRepo, enrollRepo)
+	searchSvc := service.NewSearchService(searchRepo)
+	groupSvc := service.NewGroupService(groupRepo, courseRepo, enrollRepo, reqRepo)
+	idemSvc := service.NewIdempotencyService(idemRepo, time.Duration(cfg.Idempotency.TTLHours)*time.Hour, cfg.Uploads.MaxMB)
+	var limitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimit.Store == "postgres" {
+		pgLimits := ratelimit.NewPostgresStore(pool)
+		go pgLimits.RunPurge(context.Background(), 5*time.Minute)
+		limitStore = pgLimits
+	}
+	limiter := ratelimit.New(limitStore, cfg.RateLimit.Policies)
+	hub := realtime.NewHub()
+	eventSvc := service.NewEventService(hub, courseRepo, enrollRepo)
+	importSvc := service.NewImportService(importRepo, userRepo, roleRepo, courseRepo, reqRepo, authSvc, mailer, cfg.Uploads.MaxMB)
 
 	authH := handlers.NewAuthHandler(authSvc)
 	userH := handlers.NewUserHandler(userSvc)
 	courseH := handlers.NewCourseHandler(courseSvc)
 	attH := handlers.NewAttendanceHandler(attSvc)
+	excuseH := handlers.NewExcuseHandler(excuseSvc)
+	checkinH := handlers.NewCheckinHandler(checkinSvc)
+	analyticsH := handlers.NewAnalyticsHandler(analyticsSvc)
+	reportH := handlers.NewReportHandler(reportSvc)
+	importH := handlers.NewImportHandler(importSvc)
+	profileH := handlers.NewProfileHandler(profileSvc)
+	groupH := handlers.NewGroupHandler(groupSvc)
+	enrollH := handlers.NewEnrollmentHandler(enrollSvc)
+	reqH := handlers.NewRequirementHandler(reqSvc)
+	certH := handlers.NewCertificateHandler(certSvc)
+	materialH := handlers.NewMaterialHandler(materialSvc)
+	searchH := handlers.NewSearchHandler(searchSvc)
+	eventH := handlers.NewEventHandler(eventSvc)
+	graphqlH := handlers.NewGraphQLHandler(graphqlapi.NewHandler(userSvc, courseSvc, attSvc, graphqlapi.Limits{
+		MaxDepth:      cfg.GraphQL.MaxDepth,
+		MaxComplexity: cfg.GraphQL.MaxComplexity,
+	}))
 
 	InitDB(context.Background(), pool) // Initialize database tables and default roles
 	InitDefaultUsers(context.Background(), pool) // Initialize default users before starting the server
-	
-	r := httpapi.NewRouter(authSvc, authH, userH, courseH, attH)
+
+	go checkinSvc.RunAutoClose(context.Background(), time.Minute) // closes expired check-in windows
+	go analyticsSvc.RunDigest(context.Background(), time.Duration(cfg.Analytics.DigestEveryHours)*time.Hour)
+	go idemSvc.RunPurge(context.Background(), time.Hour)
+	go hub.Listen(context.Background(), pool) // forwards course events from Postgres to /events and /ws
+
+	r := httpapi.NewRouter(authSvc, idemSvc, limiter, authH, userH, courseH, attH, excuseH, checkinH, analyticsH, reportH, importH, profileH, groupH, enrollH, reqH, certH, materialH, searchH, graphqlH, eventH)
+
+	// without trusted proxies ClientIP is the peer address, X-Forwarded-For is ignored
+	if err := r.SetTrustedProxies(cfg.App.TrustedProxies); err != nil {
+		log.Fatal("app.trusted_proxies: ", err)
+	}
+
+	if cfg.GRPC.Port != 0 {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.Port))
+		if err != nil {
+			log.Fatal("grpc listen error: ", err)
+		}
+		gs := grpcapi.NewServer(authSvc, userSvc, courseSvc, attSvc)
+		log.Println("gRPC listening on", lis.Addr())
+		go func() { log.Fatal(gs.Serve(lis)) }()
+	}
 
 	addr := fmt.Sprintf(":%d", cfg.App.Port)
 	log.Println("API listening on", addr)
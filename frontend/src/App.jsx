@@ -14,6 +14,10 @@ const useAuth = () => {
 
 const API_BASE = 'http://localhost:8080/api/v1';
 
+// ifMatch sends the version a change is based on; the API answers 412 when
+// someone else changed the record in the meantime.
+const ifMatch = version => (version === undefined ? {} : { 'If-Match': `"${version}"` });
+
 const api = {
     async request(endpoint, options = {}) {
         const token = localStorage.getItem('token');
@@ -37,12 +41,14 @@ const api = {
     getMyCourses:         ()               => api.request('/my/courses').then(d => d.items ?? []),
     createCourse:         (data)           => api.request('/courses', { method: 'POST', body: JSON.stringify(data) }),
     enrollStudent:        (courseId, sid)  => api.request(`/courses/${courseId}/enroll`, { method: 'POST', body: JSON.stringify({ student_id: sid }) }),
-    markAttendance:       (courseId, data) => api.request(`/courses/${courseId}/attendance`, { method: 'POST', body: JSON.stringify(data) }),
+    // version: of the mark being changed (If-Match); leave undefined to create one
+    markAttendance:       (courseId, data, version) => api.request(`/courses/${courseId}/attendance`, { method: 'POST', headers: ifMatch(version), body: JSON.stringify(data) }),
+    findMark:             (courseId, sid, date) => api.request(`/courses/${courseId}/attendance?student_id=${sid}&from=${date}&to=${date}`).then(d => (d.items ?? [])[0]),
     getCourseAttendance:  (courseId)       => api.request(`/courses/${courseId}/attendance`).then(d => d.items ?? []),
     getMyAttendance:      (courseId)       => api.request(`/my/attendance?course_id=${courseId}`).then(d => d.items ?? []),
     getUsers:             ()               => api.request('/users').then(d => d.items ?? []),
     createUser:           (data)           => api.request('/users', { method: 'POST', body: JSON.stringify(data) }),
-    updateUserRole:       (userId, role)   => api.request(`/users/${userId}/role`, { method: 'PATCH', body: JSON.stringify({ role }) }),
+    updateUserRole:       (userId, role, version) => api.request(`/users/${userId}/role`, { method: 'PATCH', headers: ifMatch(version), body: JSON.stringify({ role }) }),
     getRoles:             ()               => api.request('/roles').then(d => d.items ?? []),
     getStudentsInCourse:   (courseId)       => api.request(`/courses/${courseId}/students`).then(d => d.items ?? []),
     getAvailableStudentsForCourse: (courseId)       => api.request(`/courses/${courseId}/available-students`).then(d => d.items ?? []),
@@ -324,7 +330,7 @@ function TeacherCourses({ user }) {
     const loadStudentsAvailableForEnrollment = async (courseId) => {
         if (!courseId) return;
         try {            
-            const res = await api.request(`/courses/${courseId}/available-students`);
+            const res = await api.request(`/courses/${courseId}/available-students?limit=200`);
             const allStudents = res.items ?? [];  // теперь это точно массив
             setUsersForEnrollment(allStudents);
         } catch (err) {
@@ -521,9 +527,9 @@ function AdminUsers() {
         finally { setLoading(false); }
     };
 
-    const handleRoleChange = async (userId, newRole) => {
+    const handleRoleChange = async (userId, newRole, version) => {
         try {
-            await api.updateUserRole(userId, newRole);
+            await api.updateUserRole(userId, newRole, version);
             setChangingRole(null);
             load();
         } catch (err) { alert(err.message); }
@@ -564,7 +570,7 @@ function AdminUsers() {
                                                     { value: 'student', label: 'Student' },
                                                 ]}
                                             />
-                                            <button className="btn-primary btn-sm" onClick={() => handleRoleChange(u.id, changingRole.newRole)}>Save</button>
+                                            <button className="btn-primary btn-sm" onClick={() => handleRoleChange(u.id, changingRole.newRole, u.version)}>Save</button>
                                             <button className="btn-secondary btn-sm" onClick={() => setChangingRole(null)}>Cancel</button>
                                         </div>
                                     ) : (
@@ -786,12 +792,14 @@ function MarkAttendanceModal({ course, students, onClose }) {
     setMsg('');
     // const isoDate = new Date(form.lesson_date + 'T00:00:00Z').toISOString();
     try {
+      // changing an existing mark needs its version
+      const existing = await api.findMark(course.id, Number(form.studentId), form.lesson_date);
       await api.markAttendance(course.id, {
         student_id: Number(form.studentId), // convert to number for API
         lesson_date: new Date(form.lesson_date + 'T00:00:00Z').toISOString(),
         status: form.status,
         note: form.note,
-      });
+      }, existing?.version);
       setMsg('Attendance marked!');
       setForm(f => ({ ...f, studentId: '' })); // reset selection
     } catch (err) {
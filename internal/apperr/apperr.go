@@ -0,0 +1,67 @@
+// Package apperr defines the errors services return to their callers. An
+// *Error carries a Kind, which the transport turns into a status code, a
+// stable machine-readable Code and a message that is safe to show to the
+// client. Any other error is internal: clients only learn that something
+// went wrong.
+package apperr
+
+import "errors"
+
+type Kind uint8
+
+const (
+	KindInternal Kind = iota
+	KindInvalid
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindUnprocessable        // well-formed but breaks a business rule, e.g. a rejected batch
+	KindPrecondition         // a conditional write saw another version, e.g. a stale If-Match
+	KindPreconditionRequired // a conditional write was sent without its condition
+	KindRateLimited          // the client sent too many requests
+	KindTooLarge             // the request body is over the size limit
+)
+
+// Error is a client-facing error. Fields maps input fields (JSON names) to
+// what is wrong with them.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  map[string]string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func New(kind Kind, code, msg string) *Error {
+	return &Error{Kind: kind, Code: code, Message: msg}
+}
+
+// Invalid is a malformed request that does not concern one field.
+func Invalid(msg string) *Error { return New(KindInvalid, "invalid_argument", msg) }
+
+// Field is an invalid value of one input field, e.g. Field("year", "must be 1..10").
+func Field(field, msg string) *Error {
+	return &Error{Kind: KindInvalid, Code: "validation_failed", Message: field + " " + msg, Fields: map[string]string{field: msg}}
+}
+
+func NotFound(code, msg string) *Error             { return New(KindNotFound, code, msg) }
+func Conflict(code, msg string) *Error             { return New(KindConflict, code, msg) }
+func Forbidden(code, msg string) *Error            { return New(KindForbidden, code, msg) }
+func Unauthorized(code, msg string) *Error         { return New(KindUnauthorized, code, msg) }
+func Unprocessable(code, msg string) *Error        { return New(KindUnprocessable, code, msg) }
+func PreconditionFailed(code, msg string) *Error   { return New(KindPrecondition, code, msg) }
+func PreconditionRequired(code, msg string) *Error { return New(KindPreconditionRequired, code, msg) }
+func RateLimited(code, msg string) *Error          { return New(KindRateLimited, code, msg) }
+func TooLarge(code, msg string) *Error             { return New(KindTooLarge, code, msg) }
+
+// ErrForbidden is the generic answer to a caller without access.
+var ErrForbidden = Forbidden("forbidden", "forbidden")
+
+// As returns the *Error in err's chain, if any.
+func As(err error) (*Error, bool) {
+	var e *Error
+	ok := errors.As(err, &e)
+	return e, ok
+}
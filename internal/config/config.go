@@ -1,15 +1,23 @@
 package config
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"os"
+	"time"
+
+	"lms-backend/internal/ratelimit"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
 	App struct {
-		Port int `yaml:"port"`
+		Port           int      `yaml:"port"`
+		TrustedProxies []string `yaml:"trusted_proxies"` // whose X-Forwarded-For is believed; empty trusts none
 	} `yaml:"app"`
 
 	DB struct {
@@ -28,6 +36,47 @@ type Config struct {
 		Dir    string `yaml:"dir"`
 		AutoUp bool   `yaml:"auto_up"`
 	} `yaml:"migrations"`
+
+	Uploads struct {
+		Dir   string `yaml:"dir"`
+		MaxMB int    `yaml:"max_mb"`
+	} `yaml:"uploads"`
+
+	SMTP struct {
+		Host     string `yaml:"host"`
+		Port     int    `yaml:"port"`
+		Username string `yaml:"username"`
+		Password string `yaml:"password"`
+		From     string `yaml:"from"`
+	} `yaml:"smtp"`
+
+	Analytics struct {
+		DigestEveryHours int `yaml:"digest_every_hours"`
+	} `yaml:"analytics"`
+
+	GRPC struct {
+		Port int `yaml:"port"` // 0 disables the gRPC server
+	} `yaml:"grpc"`
+
+	GraphQL struct {
+		MaxDepth      int `yaml:"max_depth"`
+		MaxComplexity int `yaml:"max_complexity"`
+	} `yaml:"graphql"`
+
+	Idempotency struct {
+		TTLHours int `yaml:"ttl_hours"` // how long responses to Idempotency-Key requests are kept
+	} `yaml:"idempotency"`
+
+	RateLimit struct {
+		Store    string                      `yaml:"store"`    // memory or postgres (shared by all instances)
+		Policies map[string]ratelimit.Policy `yaml:"policies"` // by route group: auth, public, api
+	} `yaml:"rate_limit"`
+
+	Certificates struct {
+		Secret    string `yaml:"secret"`     // HMAC key; defaults to one derived from jwt.secret
+		Issuer    string `yaml:"issuer"`     // printed at the top of certificates
+		VerifyURL string `yaml:"verify_url"` // public base URL, the code is appended
+	} `yaml:"certificates"`
 }
 
 func Load(path string) (Config, error) {
@@ -47,6 +96,9 @@ func Load(path string) (Config, error) {
 	if cfg.App.Port == 0 {
 		return Config{}, errors.New("config: app.port is required")
 	}
+	if cfg.GRPC.Port != 0 && cfg.GRPC.Port == cfg.App.Port {
+		return Config{}, errors.New("config: grpc.port must differ from app.port")
+	}
 	if cfg.DB.DSN == "" {
 		return Config{}, errors.New("config: db.dsn is required")
 	}
@@ -60,6 +112,67 @@ func Load(path string) (Config, error) {
 		cfg.Migrations.Dir = "migrations"
 	}
 
+	if cfg.Uploads.Dir == "" {
+		cfg.Uploads.Dir = "uploads"
+	}
+	if cfg.Uploads.MaxMB == 0 {
+		cfg.Uploads.MaxMB = 10
+	}
+
+	if cfg.SMTP.From == "" {
+		cfg.SMTP.From = "lms@localhost"
+	}
+	if cfg.Analytics.DigestEveryHours == 0 {
+		cfg.Analytics.DigestEveryHours = 24
+	}
+	if cfg.Analytics.DigestEveryHours < 0 {
+		return Config{}, errors.New("config: analytics.digest_every_hours must be positive")
+	}
+
+	if cfg.GraphQL.MaxDepth == 0 {
+		cfg.GraphQL.MaxDepth = 10
+	}
+	if cfg.GraphQL.MaxComplexity == 0 {
+		cfg.GraphQL.MaxComplexity = 10000
+	}
+
+	if cfg.Idempotency.TTLHours == 0 {
+		cfg.Idempotency.TTLHours = 24
+	}
+
+	switch cfg.RateLimit.Store {
+	case "":
+		cfg.RateLimit.Store = "memory"
+	case "memory", "postgres":
+	default:
+		return Config{}, errors.New("config: rate_limit.store must be memory or postgres")
+	}
+	if cfg.RateLimit.Policies == nil {
+		cfg.RateLimit.Policies = map[string]ratelimit.Policy{
+			"auth":   {Limit: 10, Period: time.Minute, Burst: 5},
+			"public": {Limit: 60, Period: time.Minute},
+			"api":    {Limit: 600, Period: time.Minute, Burst: 100},
+		}
+	}
+	for group, p := range cfg.RateLimit.Policies {
+		if p.Limit <= 0 || p.Period <= 0 || p.Burst < 0 {
+			return Config{}, fmt.Errorf("config: rate_limit.policies.%s needs a positive limit and period", group)
+		}
+	}
+
+	if cfg.Certificates.Secret == "" {
+		cfg.Certificates.Secret = deriveKey(cfg.JWT.Secret, "lms certificates")
+	}
+	if cfg.Certificates.Secret == cfg.JWT.Secret {
+		return Config{}, errors.New("config: certificates.secret must differ from jwt.secret")
+	}
+	if cfg.Certificates.Issuer == "" {
+		cfg.Certificates.Issuer = "LMS"
+	}
+	if cfg.Certificates.VerifyURL == "" {
+		cfg.Certificates.VerifyURL = fmt.Sprintf("http://localhost:%d/api/v1/verify/", cfg.App.Port)
+	}
+
 	if cfg.DB.MaxConns == 0 {
 		cfg.DB.MaxConns = 10
 	}
@@ -72,3 +185,11 @@ func Load(path string) (Config, error) {
 
 	return cfg, nil
 }
+
+// deriveKey returns a key for label from secret, so one configured secret can
+// key several uses without them sharing a key.
+func deriveKey(secret, label string) string {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write([]byte(label))
+	return hex.EncodeToString(m.Sum(nil))
+}
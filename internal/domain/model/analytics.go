@@ -0,0 +1,73 @@
+package model
+
+import "time"
+
+// AttendanceMark is one mark reduced to what analytics needs.
+type AttendanceMark struct {
+	StudentID  int
+	LessonDate time.Time
+	Status     string
+	CountsAs   string
+}
+
+type StudentAttendanceStats struct {
+	AttendanceSummary
+	CurrentAbsenceStreak int // absences in a row up to the latest lesson, excused ones skipped
+	LongestAbsenceStreak int
+}
+
+type LessonStats struct {
+	LessonDate time.Time
+	AttendanceSummary
+}
+
+// TrendPoint aggregates one week, WeekStart being its Monday.
+type TrendPoint struct {
+	WeekStart time.Time
+	AttendanceSummary
+}
+
+type CourseAnalytics struct {
+	CourseID int
+	Overall  AttendanceSummary
+	Students []StudentAttendanceStats
+	Lessons  []LessonStats
+	Trend    []TrendPoint
+}
+
+type RiskRule struct {
+	ID         int
+	Name       string
+	Kind       string // absence_streak/rate_below
+	Threshold  float64
+	MinLessons int
+	CourseID   int // 0 = every course
+	Active     bool
+	CreatedAt  time.Time
+}
+
+type RiskAlert struct {
+	ID             int
+	RuleID         int
+	RuleName       string
+	CourseID       int
+	StudentID      int
+	Value          float64
+	Detail         string
+	CreatedAt      time.Time
+	NotifiedAt     *time.Time
+	AcknowledgedBy int
+	AcknowledgedAt *time.Time
+	ResolvedAt     *time.Time
+}
+
+// DigestEntry is one alert addressed to one recipient (course teacher or advisor).
+type DigestEntry struct {
+	AlertID        int
+	RecipientID    int
+	RecipientEmail string
+	CourseTitle    string
+	StudentName    string
+	RuleName       string
+	Detail         string
+}
@@ -7,6 +7,105 @@ type Attendance struct {
 	CourseID   int
 	StudentID  int
 	LessonDate time.Time // YYYY-MM-DD
-	Status     string // present/absent/late
+	Status     string // code from attendance_statuses (present/absent/late/excused/...)
 	Note       string
+	Version    int // bumped on every change
+}
+
+// AttendanceMarkResult is the outcome of one row of a bulk roll call.
+// Changed is false when the stored mark already had that status and note.
+// Version is the mark's version after the roll call, or the current one when
+// the roll call was refused.
+type AttendanceMarkResult struct {
+	StudentID int
+	Status    string
+	Changed   bool
+	Version   int // of the stored mark, 0 when there is none
+	Error     string
+	Code      string // of Error, for version conflicts
+}
+
+// AttendanceChange is one version of an attendance mark.
+// OldStatus/OldNote are empty for the first mark of a lesson.
+type AttendanceChange struct {
+	ID           int
+	AttendanceID int
+	LessonDate   time.Time
+	OldStatus    string
+	OldNote      string
+	NewStatus    string
+	NewNote      string
+	ChangedBy    int // 0 if the user was deleted
+	ChangedAt    time.Time
+}
+
+type AttendanceCorrection struct {
+	ID              int
+	CourseID        int
+	StudentID       int
+	LessonDate      time.Time
+	RequestedStatus string
+	Reason          string
+	Status          string // pending/approved/rejected
+	ReviewedBy      int
+	ReviewComment   string
+	CreatedAt       time.Time
+	ReviewedAt      *time.Time
+}
+
+// AttendanceStatus is a configurable mark. CountsAs is one of
+// present/absent/excused and decides how reports treat it.
+type AttendanceStatus struct {
+	Code     string
+	Label    string
+	CountsAs string
+	Builtin  bool
+}
+
+// AttendanceExcuse covers a date range for one course, or all courses when CourseID == 0.
+type AttendanceExcuse struct {
+	ID            int
+	StudentID     int
+	CourseID      int
+	DateFrom      time.Time
+	DateTo        time.Time
+	Reason        string
+	DocumentPath  string // relative to the upload dir, empty if none attached
+	DocumentName  string
+	DocumentType  string
+	Status        string // pending/approved/rejected
+	ReviewedBy    int
+	ReviewComment string
+	CreatedAt     time.Time
+	ReviewedAt    *time.Time
+}
+
+// AttendanceTally is a raw count of marks with one status.
+type AttendanceTally struct {
+	CourseID  int
+	StudentID int
+	Status    string
+	CountsAs  string
+	Count     int
+}
+
+// AttendanceSummary aggregates tallies for one student in one course (or
+// across courses when CourseID == 0). Excused marks are kept out of the rate.
+type AttendanceSummary struct {
+	CourseID  int
+	StudentID int
+	Total     int
+	Attended  int
+	Absent    int
+	Excused   int
+	ByStatus  map[string]int
+}
+
+// Rate is attended / (total - excused). ok is false when nothing counts yet.
+func (s AttendanceSummary) Rate() (rate float64, ok bool) {
+	n := s.Total - s.Excused
+	if n <= 0 {
+		return 0, false
+	}
+	return float64(s.Attended) / float64(n), true
 }
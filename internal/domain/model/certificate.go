@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Certificate is a signed record that a student completed a course. The
+// descriptive fields are a snapshot taken at issue time.
+type Certificate struct {
+	ID             int
+	Code           string
+	EnrollmentID   int
+	StudentID      int
+	StudentName    string
+	CourseID       int
+	CourseTitle    string
+	TeacherName    string
+	Term           string
+	Credits        float64
+	Grade          float64
+	AttendanceRate *float64 // nil when no lessons were marked
+	CompletedAt    time.Time
+	IssuedAt       time.Time
+	Signature      string
+	RevokedAt      *time.Time
+	RevokeReason   string
+}
@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+// CheckinSession is a self check-in window a teacher opens for one lesson.
+// The code shown to students is derived from Secret and rotates over time.
+type CheckinSession struct {
+	ID         int
+	CourseID   int
+	LessonDate time.Time
+	Secret     []byte
+	OpenedBy   int
+	OpenedAt   time.Time
+	LateAfter  time.Time
+	ClosesAt   time.Time
+	ClosedAt   *time.Time
+}
+
+type Checkin struct {
+	SessionID   int
+	StudentID   int
+	CodeStep    int64
+	Status      string // present/late
+	DeviceHash  string // sha256 of the client fingerprint, empty if not sent
+	IP          string
+	Lat, Lon    *float64
+	CheckedInAt time.Time
+}
+
+// Room is a classroom with a geofence around it.
+type Room struct {
+	ID      int
+	Name    string
+	Lat     float64
+	Lon     float64
+	RadiusM int
+}
+
+// CheckinPolicy holds a course's anti-fraud rules. The zero value allows any check-in.
+type CheckinPolicy struct {
+	CourseID        int
+	RoomID          int // 0 = no room
+	RequireLocation bool
+	RequireDevice   bool
+	AllowedNetworks []string // CIDRs; empty = any network
+	Room            *Room
+}
+
+type StudentDevice struct {
+	StudentID       int
+	FingerprintHash string
+	Label           string
+	RegisteredAt    time.Time
+}
+
+// SharedDevice is one device that checked in more than one student in a window.
+type SharedDevice struct {
+	SessionID  int
+	LessonDate time.Time
+	DeviceHash string
+	StudentIDs []int
+}
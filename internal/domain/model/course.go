@@ -6,5 +6,7 @@ type Course struct {
 	ID        int
 	Title     string
 	TeacherID int
+	Term      string // e.g. "Fall 2026"
+	Credits   float64
 	CreatedAt time.Time
 }
@@ -0,0 +1,80 @@
+package model
+
+import "time"
+
+// EnrollmentPolicy says whether and how students can join a course themselves.
+type EnrollmentPolicy struct {
+	CourseID int
+	Policy   string // closed, open, approval, key
+	KeyHash  string
+	Capacity int // 0 = unlimited
+	OpensAt  *time.Time
+	ClosesAt *time.Time
+
+	// filled in on read
+	Enrolled   int
+	Waitlisted int
+}
+
+// Full reports whether no seat is left.
+func (p EnrollmentPolicy) Full() bool {
+	return p.Capacity > 0 && p.Enrolled >= p.Capacity
+}
+
+type EnrollmentRequest struct {
+	ID          int
+	CourseID    int
+	StudentID   int
+	StudentName string
+	Status      string // pending, waitlisted, enrolled, rejected, cancelled
+	Comment     string
+	CreatedAt   time.Time
+	QueuedAt    *time.Time
+	DecidedBy   *int
+	DecidedAt   *time.Time
+	Position    int // place in the waitlist, 0 if not waitlisted
+}
+
+// Enrollment is a student's place in a course.
+type Enrollment struct {
+	CourseID   int
+	StudentID  int
+	Status     string // active, dropped, withdrawn, completed, failed
+	Grade      *float64
+	EnrolledAt time.Time
+}
+
+// EnrollmentChange moves an enrollment to another status.
+type EnrollmentChange struct {
+	Status    string // active, dropped, withdrawn, completed, failed
+	Reason    string
+	Grade     *float64 // completed/failed only
+	ChangedBy int
+}
+
+// EnrollmentEvent is one entry of an enrollment's status history.
+type EnrollmentEvent struct {
+	OldStatus     string // empty for the first enrollment
+	Status        string
+	Reason        string
+	ChangedBy     int
+	ChangedByName string
+	ChangedAt     time.Time
+}
+
+// TranscriptEntry is one course on a student's transcript.
+type TranscriptEntry struct {
+	CourseID        int
+	CourseTitle     string
+	TeacherName     string
+	Term            string
+	Credits         float64
+	Status          string
+	Grade           *float64
+	AttendanceRate  *float64 // attended / (lessons - excused); nil if nothing marked
+	EnrolledAt      time.Time
+	StatusChangedAt time.Time
+	Reason          string
+	GroupName       string // set when the enrollment came from a group
+	History         []EnrollmentEvent
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// StudentGroup is an academic group/cohort such as "SE-2203".
+type StudentGroup struct {
+	ID        int
+	Name      string
+	Faculty   string
+	Year      int
+	CreatedAt time.Time
+	Members   int
+}
+
+// GroupSync says how rosters changed after a group operation.
+type GroupSync struct {
+	Enrolled   int // enrollments added
+	Unenrolled int // enrollments removed
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// IdempotentResponse is the stored outcome of a request sent with an
+// Idempotency-Key. Status is 0 while the first request is still running.
+type IdempotentResponse struct {
+	UserID      int
+	Key         string
+	RequestHash string
+	Status      int
+	ContentType string
+	Body        []byte
+	ExpiresAt   time.Time
+}
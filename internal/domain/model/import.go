@@ -0,0 +1,44 @@
+package model
+
+// ImportRow is one validated line of a user/enrollment roster. RoleID 0 on
+// an existing user keeps their role; an empty PasswordHash keeps their
+// password.
+type ImportRow struct {
+	Line         int
+	Email        string
+	FullName     string
+	RoleID       int
+	PasswordHash string
+	CourseIDs    []int
+
+	Exists            bool   // the email is already registered
+	GeneratedPassword string // set for new users without a password column
+}
+
+// ImportError points at a bad cell of the uploaded file.
+type ImportError struct {
+	Line    int
+	Field   string
+	Message string
+}
+
+// ImportResult says what applying a row did (or would do, in a dry run).
+type ImportResult struct {
+	Line     int
+	Email    string
+	UserID   int
+	Action   string // created, updated, unchanged
+	Enrolled []int  // course ids the user was newly enrolled in
+}
+
+type ImportReport struct {
+	DryRun      bool
+	Rows        int
+	Created     int
+	Updated     int
+	Unchanged   int
+	Enrollments int
+	Invited     int
+	Errors      []ImportError
+	Results     []ImportResult
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// CourseMaterial is a text published in a course, e.g. lecture notes.
+type CourseMaterial struct {
+	ID        int
+	CourseID  int
+	Title     string
+	Body      string
+	CreatedBy *int
+	CreatedAt time.Time
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// UserProfile is the registrar data kept next to a user. Empty strings and a
+// zero Year mean "not set".
+type UserProfile struct {
+	UserID int
+
+	// students
+	StudentNumber string
+	Group         string
+	Year          int
+	Faculty       string
+
+	// everyone
+	Phone    string
+	Language string
+
+	// staff
+	Department string
+	Title      string
+
+	AvatarPath string
+	UpdatedAt  time.Time
+}
+
+// ProfilePatch holds the fields of a profile edit; nil fields are left
+// unchanged and empty values clear a field.
+type ProfilePatch struct {
+	StudentNumber *string
+	Group         *string
+	Year          *int
+	Faculty       *string
+	Phone         *string
+	Language      *string
+	Department    *string
+	Title         *string
+}
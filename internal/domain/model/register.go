@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// RegisterRow is one student's line of an attendance register:
+// marks keyed by lesson date (YYYY-MM-DD) plus totals.
+type RegisterRow struct {
+	StudentID int
+	FullName  string
+	Email     string
+	Marks     map[string]string
+	Summary   AttendanceSummary
+}
+
+// RegisterMeta describes a register before its rows are streamed.
+type RegisterMeta struct {
+	CourseID    int
+	CourseTitle string
+	From, To    time.Time
+	Dates       []time.Time
+	Statuses    []AttendanceStatus
+	GeneratedAt time.Time
+}
@@ -0,0 +1,72 @@
+package model
+
+// CourseRequirement is a rule a student must satisfy to be enrolled.
+type CourseRequirement struct {
+	ID               int
+	CourseID         int
+	Kind             string // prerequisite, corequisite, year, group
+	RequiredCourseID int    // prerequisite/corequisite
+	RequiredCourse   string
+	MinGrade         *float64 // prerequisite, optional
+	MinYear          int      // year
+	GroupID          int      // group
+	GroupName        string
+}
+
+// ScheduleSlot is a weekly lesson time of a course.
+type ScheduleSlot struct {
+	CourseID int
+	Weekday  int    // 1 = Monday ... 7 = Sunday
+	StartsAt string // HH:MM
+	EndsAt   string // HH:MM
+}
+
+// CourseStanding is a student's relation to one course.
+type CourseStanding struct {
+	Enrolled  bool
+	Completed bool
+	Grade     *float64
+}
+
+// StudentStanding is everything requirement checks look at.
+type StudentStanding struct {
+	Year    int // 0 = unknown
+	GroupID int // 0 = no group
+	Courses map[int]CourseStanding
+}
+
+// ScheduleConflict is an overlap between a slot of the target course and one
+// of a course the student already attends.
+type ScheduleConflict struct {
+	CourseID    int
+	CourseTitle string
+	Weekday     int
+	StartsAt    string
+	EndsAt      string
+}
+
+// UnmetRule explains why a student may not be enrolled. Rule is one of
+// prerequisite, min_grade, corequisite, year, group, schedule_conflict.
+type UnmetRule struct {
+	Rule          string
+	RequirementID int
+	CourseID      int
+	CourseTitle   string
+	MinGrade      *float64
+	Grade         *float64
+	MinYear       int
+	Year          int
+	GroupID       int
+	GroupName     string
+	Weekday       int
+	StartsAt      string
+	EndsAt        string
+	Message       string
+}
+
+// StudentUnmet is one student a bulk enrollment could not put into a course.
+type StudentUnmet struct {
+	StudentID int
+	CourseID  int
+	Unmet     []UnmetRule
+}
@@ -0,0 +1,21 @@
+package model
+
+// SearchHit is one search result. Detail is the email of a user, the term
+// of a course or the course title of a material; Snippet is the matching
+// part of a material with hits wrapped in <b></b>.
+type SearchHit struct {
+	ID       int
+	Title    string
+	Detail   string
+	Role     string // users only
+	CourseID int    // materials only
+	Snippet  string
+	Rank     float64
+}
+
+// SearchResults groups hits by kind; kinds that were not searched are nil.
+type SearchResults struct {
+	Users     []SearchHit
+	Courses   []SearchHit
+	Materials []SearchHit
+}
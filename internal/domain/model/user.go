@@ -1,9 +1,24 @@
 package model
 
+import "time"
+
 type User struct {
 	ID           int
 	Email        string
 	PasswordHash string
 	FullName     string
 	RoleID       int
+	Active       bool
+	DeletedAt    *time.Time
+	CreatedAt    time.Time // filled by list queries
+	Version      int       // bumped on every change
+}
+
+// UserPatch holds the fields of an update; nil fields are left unchanged.
+type UserPatch struct {
+	Email        *string
+	FullName     *string
+	RoleID       *int
+	Active       *bool
+	PasswordHash *string
 }
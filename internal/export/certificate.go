@@ -0,0 +1,153 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"lms-backend/internal/domain/model"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	certQRSize = 96.0
+	certTextW  = pdfPageW - 2*72
+)
+
+// WriteCertificate renders a one-page landscape completion certificate with
+// the verification code, the verification URL and a QR code pointing to it.
+func WriteCertificate(w io.Writer, c model.Certificate, issuer, verifyURL string) error {
+	qr, err := qrcode.New(verifyURL, qrcode.Medium)
+	if err != nil {
+		return err
+	}
+	qr.DisableBorder = true
+
+	var page bytes.Buffer
+	frame := func(inset, width float64) {
+		fmt.Fprintf(&page, "%.1f w %.2f %.2f %.2f %.2f re S\n", width, inset, inset, pdfPageW-2*inset, pdfPageH-2*inset)
+	}
+	centered := func(y, size float64, font, s string) {
+		for size > 8 && textWidth(s, size, font == "F2") > certTextW {
+			size--
+		}
+		x := (pdfPageW - textWidth(s, size, font == "F2")) / 2
+		fmt.Fprintf(&page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfEscape(s))
+	}
+	left := func(x, y, size float64, s string) {
+		fmt.Fprintf(&page, "BT /F1 %.1f Tf %.2f %.2f Td (%s) Tj ET\n", size, x, y, pdfEscape(s))
+	}
+
+	frame(22, 2)
+	frame(30, 0.6)
+	centered(520, 14, "F1", issuer)
+	centered(460, 30, "F2", "CERTIFICATE OF COMPLETION")
+	centered(412, 14, "F1", "This is to certify that")
+	centered(370, 26, "F2", c.StudentName)
+	fmt.Fprintf(&page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfPageW/2-200, 360.0, pdfPageW/2+200, 360.0)
+	centered(328, 14, "F1", "has successfully completed the course")
+	centered(292, 20, "F2", c.CourseTitle)
+
+	details := make([]string, 0, 4)
+	if c.Term != "" {
+		details = append(details, "Term: "+c.Term)
+	}
+	if c.Credits > 0 {
+		details = append(details, fmt.Sprintf("Credits: %g", c.Credits))
+	}
+	details = append(details, fmt.Sprintf("Final grade: %.2f", c.Grade))
+	if c.AttendanceRate != nil {
+		details = append(details, fmt.Sprintf("Attendance: %.0f%%", *c.AttendanceRate*100))
+	}
+	centered(252, 12, "F1", strings.Join(details, "     "))
+	if c.TeacherName != "" {
+		centered(232, 12, "F1", "Instructor: "+c.TeacherName)
+	}
+	centered(212, 12, "F1", "Completed on "+c.CompletedAt.Format("2 January 2006"))
+
+	left(60, 110, 11, "Certificate code: "+c.Code)
+	left(60, 94, 9, "Verify at: "+verifyURL)
+	left(60, 80, 9, "Issued "+c.IssuedAt.UTC().Format("2006-01-02 15:04 MST"))
+	left(60, 66, 6, "Signature (HMAC-SHA256): "+c.Signature)
+
+	bm := qr.Bitmap()
+	cell := certQRSize / float64(len(bm))
+	x0, y0 := pdfPageW-60-certQRSize, 60.0
+	for row, line := range bm {
+		for col, dark := range line {
+			if dark {
+				fmt.Fprintf(&page, "%.3f %.3f %.3f %.3f re\n", x0+float64(col)*cell, y0+certQRSize-float64(row+1)*cell, cell, cell)
+			}
+		}
+	}
+	page.WriteString("f\n")
+
+	// fixed object ids: catalog, pages, two fonts, page, content, info
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	offsets := make([]int64, 7)
+	object := func(id int, body string) {
+		offsets[id-1] = cw.n
+		fmt.Fprintf(cw, "%d 0 obj\n%s\nendobj\n", id, body)
+	}
+	io.WriteString(cw, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	object(2, "<< /Type /Pages /Kids [5 0 R] /Count 1 >>")
+	object(3, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object(4, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(5, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents 6 0 R >>",
+		pdfPageW, pdfPageH))
+	object(6, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", page.Len(), page.Bytes()))
+	object(7, fmt.Sprintf("<< /Title (%s) /Subject (%s) /Producer (LMS) >>",
+		pdfEscape("Certificate of completion: "+c.CourseTitle), pdfEscape(c.Code)))
+
+	xref := cw.n
+	fmt.Fprintf(cw, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(cw, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(cw, "trailer\n<< /Size %d /Root 1 0 R /Info 7 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	if cw.err != nil {
+		return cw.err
+	}
+	return cw.w.Flush()
+}
+
+// textWidth estimates the width of s in Helvetica at size points; bold is
+// about 6% wider on average.
+func textWidth(s string, size float64, bold bool) float64 {
+	units := 0
+	for _, r := range s {
+		if t, ok := translit[r]; ok {
+			for _, tr := range t {
+				units += glyphWidth(tr)
+			}
+			continue
+		}
+		units += glyphWidth(r)
+	}
+	w := float64(units) * size / 1000
+	if bold {
+		w *= 1.06
+	}
+	return w
+}
+
+// helveticaWidths are the AFM advance widths of ASCII 32..126.
+var helveticaWidths = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+func glyphWidth(r rune) int {
+	if r >= 32 && r <= 126 {
+		return helveticaWidths[r-32]
+	}
+	return 556
+}
@@ -0,0 +1,49 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"lms-backend/internal/domain/model"
+)
+
+// CSV writes one header line and one line per student; cells hold the full
+// status code.
+type CSV struct {
+	w     *csv.Writer
+	dates []string
+}
+
+func NewCSV(w io.Writer) *CSV { return &CSV{w: csv.NewWriter(w)} }
+
+func (x *CSV) Begin(meta model.RegisterMeta) error {
+	header := []string{"Student ID", "Full name", "Email"}
+	for _, d := range meta.Dates {
+		k := d.Format("2006-01-02")
+		x.dates = append(x.dates, k)
+		header = append(header, k)
+	}
+	header = append(header, totalsHeader...)
+	return x.w.Write(header)
+}
+
+func (x *CSV) Row(row model.RegisterRow) error {
+	rec := make([]string, 0, 3+len(x.dates)+len(totalsHeader))
+	rec = append(rec, strconv.Itoa(row.StudentID), row.FullName, row.Email)
+	for _, d := range x.dates {
+		rec = append(rec, row.Marks[d])
+	}
+	s := row.Summary
+	rec = append(rec, strconv.Itoa(s.Attended), strconv.Itoa(s.Absent), strconv.Itoa(s.Excused), strconv.Itoa(s.Total), rateText(s))
+	if err := x.w.Write(rec); err != nil {
+		return err
+	}
+	x.w.Flush()
+	return x.w.Error()
+}
+
+func (x *CSV) End() error {
+	x.w.Flush()
+	return x.w.Error()
+}
@@ -0,0 +1,52 @@
+// Package export renders attendance registers (students × lesson dates) as
+// CSV, XLSX or PDF. Writers receive rows one at a time and write them out
+// immediately, so the whole register is never held in memory. It also
+// renders completion certificates as PDF.
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"lms-backend/internal/domain/model"
+)
+
+// RegisterWriter is implemented by every output format.
+type RegisterWriter interface {
+	Begin(meta model.RegisterMeta) error
+	Row(row model.RegisterRow) error
+	End() error
+}
+
+// Format describes an output format for HTTP responses.
+type Format struct {
+	ContentType string
+	Ext         string
+	New         func(w io.Writer) RegisterWriter
+}
+
+var Formats = map[string]Format{
+	"csv":  {ContentType: "text/csv; charset=utf-8", Ext: ".csv", New: func(w io.Writer) RegisterWriter { return NewCSV(w) }},
+	"xlsx": {ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", Ext: ".xlsx", New: func(w io.Writer) RegisterWriter { return NewXLSX(w) }},
+	"pdf":  {ContentType: "application/pdf", Ext: ".pdf", New: func(w io.Writer) RegisterWriter { return NewPDF(w) }},
+}
+
+// totalsHeader are the per-student total columns after the dates.
+var totalsHeader = []string{"Attended", "Absent", "Excused", "Total", "Rate"}
+
+func rateText(s model.AttendanceSummary) string {
+	r, ok := s.Rate()
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%.0f%%", r*100)
+}
+
+// abbrev is the short cell label of a status: its first letter, upper case.
+func abbrev(status string) string {
+	if status == "" {
+		return ""
+	}
+	return strings.ToUpper(status[:1])
+}
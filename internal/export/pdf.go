@@ -0,0 +1,279 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"lms-backend/internal/domain/model"
+)
+
+// PDFMaxDates is the number of lesson columns that still fit on a landscape
+// A4 page; longer ranges have to be split or exported as CSV/XLSX.
+const PDFMaxDates = 60
+
+const (
+	pdfPageW   = 842.0
+	pdfPageH   = 595.0
+	pdfMargin  = 28.0
+	pdfNameW   = 150.0
+	pdfTotalW  = 34.0
+	pdfRowH    = 10.0
+	pdfFont    = 7.0
+	pdfHeaderH = 38.0
+)
+
+// PDF writes a printable register with the built-in Helvetica font. Each page
+// is emitted as soon as it is full; only the object offsets are kept until
+// the cross-reference table is written at the end.
+type PDF struct {
+	w       *countingWriter
+	offsets []int64 // offsets[i] is object i+1
+	pages   []int
+
+	meta  model.RegisterMeta
+	dates []string
+	dateW float64
+
+	page *bytes.Buffer
+	y    float64
+}
+
+func NewPDF(w io.Writer) *PDF {
+	return &PDF{w: &countingWriter{w: bufio.NewWriter(w)}}
+}
+
+// object ids fixed up front: catalog, page tree, font
+const (
+	pdfCatalog = 1
+	pdfPages   = 2
+	pdfFontObj = 3
+)
+
+func (p *PDF) Begin(meta model.RegisterMeta) error {
+	if len(meta.Dates) > PDFMaxDates {
+		return fmt.Errorf("too many lesson dates for pdf: %d > %d", len(meta.Dates), PDFMaxDates)
+	}
+	p.meta = meta
+	for _, d := range meta.Dates {
+		p.dates = append(p.dates, d.Format("2006-01-02"))
+	}
+	p.dateW = 16
+	if n := len(p.dates); n > 0 {
+		avail := pdfPageW - 2*pdfMargin - pdfNameW - float64(len(totalsHeader))*pdfTotalW
+		p.dateW = min(p.dateW, avail/float64(n))
+	}
+
+	p.offsets = make([]int64, 3)
+	if _, err := io.WriteString(p.w, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n"); err != nil {
+		return err
+	}
+	if err := p.object(pdfFontObj, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>"); err != nil {
+		return err
+	}
+	if err := p.object(pdfCatalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pdfPages)); err != nil {
+		return err
+	}
+	p.newPage()
+	return nil
+}
+
+func (p *PDF) Row(row model.RegisterRow) error {
+	if p.y-pdfRowH < pdfMargin+pdfRowH {
+		if err := p.flushPage(); err != nil {
+			return err
+		}
+		p.newPage()
+	}
+	p.y -= pdfRowH
+	x := pdfMargin
+	p.text(x, p.y, pdfFont, truncate(row.FullName, 40))
+	x += pdfNameW
+	for _, d := range p.dates {
+		p.text(x+p.dateW/2-2, p.y, pdfFont, abbrev(row.Marks[d]))
+		x += p.dateW
+	}
+	s := row.Summary
+	for _, v := range []string{strconv.Itoa(s.Attended), strconv.Itoa(s.Absent), strconv.Itoa(s.Excused), strconv.Itoa(s.Total), rateText(s)} {
+		p.text(x+2, p.y, pdfFont, v)
+		x += pdfTotalW
+	}
+	p.line(pdfMargin, p.y-2.5, x, p.y-2.5)
+	return nil
+}
+
+func (p *PDF) End() error {
+	if p.page == nil {
+		// Begin failed before the header was written; nothing to finish.
+		return nil
+	}
+	if err := p.flushPage(); err != nil {
+		return err
+	}
+
+	kids := make([]string, len(p.pages))
+	for i, id := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", id)
+	}
+	if err := p.object(pdfPages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages))); err != nil {
+		return err
+	}
+
+	xref := p.w.n
+	fmt.Fprintf(p.w, "xref\n0 %d\n0000000000 65535 f \n", len(p.offsets)+1)
+	for _, off := range p.offsets {
+		fmt.Fprintf(p.w, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(p.w, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(p.offsets)+1, pdfCatalog, xref)
+	if p.w.err != nil {
+		return p.w.err
+	}
+	return p.w.w.Flush()
+}
+
+// newPage starts a page buffer with the title block, legend and column header.
+func (p *PDF) newPage() {
+	p.page = &bytes.Buffer{}
+	y := pdfPageH - pdfMargin - 10
+	p.text(pdfMargin, y, 12, "Attendance register: "+p.meta.CourseTitle)
+	y -= 12
+	info := fmt.Sprintf("Course #%d  %s - %s  generated %s  page %d",
+		p.meta.CourseID, p.meta.From.Format("2006-01-02"), p.meta.To.Format("2006-01-02"),
+		p.meta.GeneratedAt.Format("2006-01-02 15:04"), len(p.pages)+1)
+	p.text(pdfMargin, y, pdfFont, info)
+	y -= 9
+	legend := make([]string, 0, len(p.meta.Statuses))
+	for _, s := range p.meta.Statuses {
+		legend = append(legend, abbrev(s.Code)+" = "+s.Code)
+	}
+	p.text(pdfMargin, y, pdfFont, strings.Join(legend, ", "))
+
+	// dates are printed vertically so narrow columns still fit them
+	y -= pdfHeaderH
+	x := pdfMargin
+	p.text(x, y+2, pdfFont, "Student")
+	x += pdfNameW
+	for _, d := range p.dates {
+		fmt.Fprintf(p.page, "BT /F1 %.1f Tf 0 1 -1 0 %.2f %.2f Tm (%s) Tj ET\n", pdfFont-1, x+p.dateW/2+2, y+2, pdfEscape(d))
+		x += p.dateW
+	}
+	for _, h := range totalsHeader {
+		p.text(x+2, y+2, pdfFont, h)
+		x += pdfTotalW
+	}
+	p.line(pdfMargin, y-1, x, y-1)
+	p.y = y
+}
+
+func (p *PDF) flushPage() error {
+	content := p.page.Bytes()
+	contentID := p.reserve()
+	pageID := p.reserve()
+	stream := fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content)
+	if err := p.object(contentID, stream); err != nil {
+		return err
+	}
+	page := fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+		pdfPages, pdfPageW, pdfPageH, pdfFontObj, contentID)
+	if err := p.object(pageID, page); err != nil {
+		return err
+	}
+	p.pages = append(p.pages, pageID)
+	return nil
+}
+
+func (p *PDF) reserve() int {
+	p.offsets = append(p.offsets, 0)
+	return len(p.offsets)
+}
+
+func (p *PDF) object(id int, body string) error {
+	p.offsets[id-1] = p.w.n
+	fmt.Fprintf(p.w, "%d 0 obj\n%s\nendobj\n", id, body)
+	return p.w.err
+}
+
+func (p *PDF) text(x, y, size float64, s string) {
+	fmt.Fprintf(p.page, "BT /F1 %.1f Tf %.2f %.2f Td (%s) Tj ET\n", size, x, y, pdfEscape(s))
+}
+
+func (p *PDF) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(p.page, "0.3 w %.2f %.2f m %.2f %.2f l S\n", x1, y1, x2, y2)
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "."
+}
+
+// pdfEscape converts s to WinAnsi bytes for a literal string. Cyrillic is
+// transliterated, anything else outside Latin-1 becomes '?'.
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if t, ok := translit[r]; ok {
+			b.WriteString(t)
+			continue
+		}
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20:
+			b.WriteByte(' ')
+		case r < 0x80:
+			b.WriteRune(r)
+		case r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+var translit = func() map[rune]string {
+	lower := map[rune]string{
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+		'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+		'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+		'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+		'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ә': "a", 'ғ': "g", 'қ': "q",
+		'ң': "n", 'ө': "o", 'ұ': "u", 'ү': "u", 'һ': "h",
+	}
+	m := make(map[rune]string, 2*len(lower))
+	for r, t := range lower {
+		m[r] = t
+		if up := []rune(strings.ToUpper(string(r)))[0]; up != r {
+			if t != "" {
+				t = strings.ToUpper(t[:1]) + t[1:]
+			}
+			m[up] = t
+		}
+	}
+	return m
+}()
+
+// countingWriter tracks the byte offset needed for the xref table and keeps
+// the first write error.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
@@ -0,0 +1,151 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+
+	"lms-backend/internal/domain/model"
+)
+
+// XLSX writes a minimal Office Open XML workbook by hand. The zip entries are
+// written sequentially, so the sheet is streamed row by row.
+type XLSX struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	dates []string
+	row   int
+}
+
+func NewXLSX(w io.Writer) *XLSX { return &XLSX{zw: zip.NewWriter(w)} }
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/></Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Attendance" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`
+	// cell styles: 0 = normal, 1 = bold, 2 = percent
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts><fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills><borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/><xf numFmtId="9" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs></styleSheet>`
+	// first row and the ID/name columns stay visible while scrolling
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetViews><sheetView workbookViewId="0"><pane xSplit="2" ySplit="1" topLeftCell="C2" activePane="bottomRight" state="frozen"/></sheetView></sheetViews><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+func (x *XLSX) Begin(meta model.RegisterMeta) error {
+	for _, f := range []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	} {
+		w, err := x.zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, f.body); err != nil {
+			return err
+		}
+	}
+
+	sheet, err := x.zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	x.sheet = sheet
+	if _, err := io.WriteString(sheet, xlsxSheetStart); err != nil {
+		return err
+	}
+
+	cells := []xlsxCell{{s: "Student ID", style: 1}, {s: "Full name", style: 1}, {s: "Email", style: 1}}
+	for _, d := range meta.Dates {
+		k := d.Format("2006-01-02")
+		x.dates = append(x.dates, k)
+		cells = append(cells, xlsxCell{s: k, style: 1})
+	}
+	for _, h := range totalsHeader {
+		cells = append(cells, xlsxCell{s: h, style: 1})
+	}
+	return x.writeRow(cells)
+}
+
+func (x *XLSX) Row(row model.RegisterRow) error {
+	cells := make([]xlsxCell, 0, 3+len(x.dates)+len(totalsHeader))
+	cells = append(cells, xlsxCell{n: strconv.Itoa(row.StudentID)}, xlsxCell{s: row.FullName}, xlsxCell{s: row.Email})
+	for _, d := range x.dates {
+		cells = append(cells, xlsxCell{s: row.Marks[d]})
+	}
+	s := row.Summary
+	cells = append(cells,
+		xlsxCell{n: strconv.Itoa(s.Attended)},
+		xlsxCell{n: strconv.Itoa(s.Absent)},
+		xlsxCell{n: strconv.Itoa(s.Excused)},
+		xlsxCell{n: strconv.Itoa(s.Total)},
+	)
+	if r, ok := s.Rate(); ok {
+		cells = append(cells, xlsxCell{n: strconv.FormatFloat(r, 'f', 4, 64), style: 2})
+	} else {
+		cells = append(cells, xlsxCell{})
+	}
+	return x.writeRow(cells)
+}
+
+func (x *XLSX) End() error {
+	if x.sheet != nil {
+		if _, err := io.WriteString(x.sheet, xlsxSheetEnd); err != nil {
+			return err
+		}
+	}
+	return x.zw.Close()
+}
+
+// xlsxCell is either a string (s) or a number (n); empty cells are skipped.
+type xlsxCell struct {
+	s, n  string
+	style int
+}
+
+func (x *XLSX) writeRow(cells []xlsxCell) error {
+	x.row++
+	r := strconv.Itoa(x.row)
+	var b bytes.Buffer
+	b.WriteString(`<row r="` + r + `">`)
+	for i, c := range cells {
+		if c.s == "" && c.n == "" {
+			continue
+		}
+		b.WriteString(`<c r="` + xlsxColumn(i) + r + `"`)
+		if c.style != 0 {
+			b.WriteString(` s="` + strconv.Itoa(c.style) + `"`)
+		}
+		if c.n != "" {
+			b.WriteString(`><v>` + c.n + `</v></c>`)
+			continue
+		}
+		b.WriteString(` t="inlineStr"><is><t>`)
+		if err := xml.EscapeText(&b, []byte(c.s)); err != nil {
+			return err
+		}
+		b.WriteString(`</t></is></c>`)
+	}
+	b.WriteString(`</row>`)
+	_, err := x.sheet.Write(b.Bytes())
+	return err
+}
+
+// xlsxColumn turns a 0-based index into a column name: 0 -> A, 26 -> AA.
+func xlsxColumn(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
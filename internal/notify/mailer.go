@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/smtp"
+	"strings"
+)
+
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// Mailer sends plain-text emails over SMTP. Without a host it only logs the
+// messages, which is what local development uses.
+type Mailer struct {
+	opts SMTPOptions
+}
+
+func NewMailer(opts SMTPOptions) *Mailer {
+	if opts.Port == 0 {
+		opts.Port = 587
+	}
+	return &Mailer{opts: opts}
+}
+
+func (m *Mailer) Send(ctx context.Context, to []string, subject, body string) error {
+	if len(to) == 0 {
+		return nil
+	}
+	if m.opts.Host == "" {
+		log.Printf("mail (not sent, smtp.host empty) to=%s subject=%q\n%s", strings.Join(to, ","), subject, body)
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.opts.Username != "" {
+		auth = smtp.PlainAuth("", m.opts.Username, m.opts.Password, m.opts.Host)
+	}
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
+		m.opts.From, strings.Join(to, ", "), subject, strings.ReplaceAll(body, "\n", "\r\n"))
+	addr := fmt.Sprintf("%s:%d", m.opts.Host, m.opts.Port)
+	return smtp.SendMail(addr, auth, m.opts.From, to, []byte(msg))
+}
@@ -0,0 +1,312 @@
+// Package pagination implements keyset ("cursor") pagination with
+// whitelisted sorting and filtering for list endpoints. Handlers turn the
+// query string into a Request; repositories check it against their Spec and
+// get SQL fragments back. Cursors are opaque to clients and tied to the sort
+// and filters they were issued for.
+package pagination
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"lms-backend/internal/apperr"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+// ErrInvalid wraps every problem with limit, sort, filter or cursor values,
+// i.e. client errors.
+var ErrInvalid = apperr.New(apperr.KindInvalid, "invalid_list_params", "invalid list parameters")
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalid, fmt.Sprintf(format, args...))
+}
+
+// Request is a list request as read from the query string.
+type Request struct {
+	Limit   int // 0 = DefaultLimit
+	Cursor  string
+	Sort    string // "name" ascending, "-name" descending, "" = spec default
+	Filters url.Values
+}
+
+// FromQuery reads ?limit=&cursor=&sort= and keeps every other parameter as
+// a filter candidate; only those whitelisted by the Spec are applied.
+func FromQuery(q url.Values) (Request, error) {
+	r := Request{Cursor: q.Get("cursor"), Sort: q.Get("sort"), Filters: url.Values{}}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > MaxLimit {
+			return r, invalid("limit must be 1..%d", MaxLimit)
+		}
+		r.Limit = n
+	}
+	for k, v := range q {
+		switch k {
+		case "limit", "cursor", "sort":
+		default:
+			r.Filters[k] = v
+		}
+	}
+	return r, nil
+}
+
+// Kind says how a filter value is parsed.
+type Kind int
+
+const (
+	Text Kind = iota
+	Int
+	Bool
+	Time // RFC 3339 or YYYY-MM-DD (midnight UTC)
+	Date // YYYY-MM-DD
+)
+
+// Field is a sortable column. Column must never be NULL (use COALESCE) and
+// Type is its Postgres type, used to cast cursor values back.
+type Field struct {
+	Column string
+	Type   string
+}
+
+// Filter turns a query parameter into a condition; every $? in Cond is
+// replaced by the parameter's placeholder.
+type Filter struct {
+	Cond string
+	Kind Kind
+	Like bool // wrap the value in % for ILIKE, escaping wildcards
+}
+
+// Spec is what a list query allows.
+type Spec struct {
+	ID      string // unique column that breaks ties, e.g. "u.id"
+	Sorts   map[string]Field
+	Default string // e.g. "-id"
+	Filters map[string]Filter
+}
+
+// Query is a validated Request for one Spec.
+type Query struct {
+	spec  *Spec
+	limit int
+	sort  string
+	field Field
+	desc  bool
+	after *cursor
+	conds []string
+	vals  []any
+	fp    string
+}
+
+type cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    int    `json:"id"`
+	FP    string `json:"f"`
+}
+
+// Key is the position of one row: the sort column as text and its id.
+type Key struct {
+	Value string
+	ID    int
+}
+
+// Query validates r against the spec.
+func (s *Spec) Query(r Request) (*Query, error) {
+	q := &Query{spec: s, limit: r.Limit, sort: r.Sort}
+	if q.limit == 0 {
+		q.limit = DefaultLimit
+	}
+	if q.sort == "" {
+		q.sort = s.Default
+	}
+	name := strings.TrimPrefix(q.sort, "-")
+	f, ok := s.Sorts[name]
+	if !ok {
+		return nil, invalid("sort must be one of %s", strings.Join(s.sortNames(), ", "))
+	}
+	q.field, q.desc = f, strings.HasPrefix(q.sort, "-")
+
+	names := make([]string, 0, len(r.Filters))
+	for k := range r.Filters {
+		if _, ok := s.Filters[k]; ok {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n", q.sort)
+	for _, k := range names {
+		raw := r.Filters.Get(k)
+		if raw == "" {
+			continue
+		}
+		flt := s.Filters[k]
+		v, err := parseValue(flt.Kind, raw)
+		if err != nil {
+			return nil, invalid("%s: %v", k, err)
+		}
+		if flt.Like {
+			v = "%" + likeEscaper.Replace(raw) + "%"
+		}
+		q.conds = append(q.conds, flt.Cond)
+		q.vals = append(q.vals, v)
+		fmt.Fprintf(h, "%s=%s\n", k, raw)
+	}
+	q.fp = hex.EncodeToString(h.Sum(nil)[:8])
+
+	if r.Cursor != "" {
+		b, err := base64.RawURLEncoding.DecodeString(r.Cursor)
+		var c cursor
+		if err == nil {
+			err = json.Unmarshal(b, &c)
+		}
+		if err != nil {
+			return nil, invalid("malformed cursor")
+		}
+		if c.Sort != q.sort || c.FP != q.fp {
+			return nil, invalid("cursor belongs to a different sort or filter")
+		}
+		if !validKey(q.field.Type, c.Value) || c.ID <= 0 {
+			return nil, invalid("malformed cursor")
+		}
+		q.after = &c
+	}
+	return q, nil
+}
+
+func (s *Spec) sortNames() []string {
+	out := make([]string, 0, len(s.Sorts))
+	for k := range s.Sorts {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func parseValue(kind Kind, raw string) (any, error) {
+	switch kind {
+	case Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, errors.New("must be an integer")
+		}
+		return n, nil
+	case Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("must be true or false")
+		}
+		return b, nil
+	case Time:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, nil
+		}
+		t, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return nil, errors.New("must be RFC 3339 or YYYY-MM-DD")
+		}
+		return t, nil
+	case Date:
+		t, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return nil, errors.New("must be YYYY-MM-DD")
+		}
+		return t, nil
+	default:
+		return raw, nil
+	}
+}
+
+// validKey reports whether v, a sort value taken from a client's cursor, is
+// text Postgres would have produced for typ, so the cast in Where cannot fail.
+func validKey(typ, v string) bool {
+	switch typ {
+	case "int", "integer", "bigint", "smallint":
+		_, err := strconv.ParseInt(v, 10, 64)
+		return err == nil
+	case "numeric", "real", "double precision":
+		_, err := strconv.ParseFloat(v, 64)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", v)
+		return err == nil
+	case "timestamp":
+		_, err := time.Parse("2006-01-02 15:04:05.999999999", v)
+		return err == nil
+	case "timestamptz":
+		for _, layout := range []string{"-07", "-07:00", "-07:00:00"} {
+			if _, err := time.Parse("2006-01-02 15:04:05.999999999"+layout, v); err == nil {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// Where returns " AND ..." conditions for the filters and the cursor
+// position, numbering placeholders after args.
+func (q *Query) Where(args []any) (string, []any) {
+	var sb strings.Builder
+	next := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+	for i, cond := range q.conds {
+		sb.WriteString(" AND ")
+		sb.WriteString(strings.ReplaceAll(cond, "$?", next(q.vals[i])))
+	}
+	if q.after != nil {
+		op := ">"
+		if q.desc {
+			op = "<"
+		}
+		v := next(q.after.Value)
+		id := next(q.after.ID)
+		fmt.Fprintf(&sb, " AND (%s, %s) %s (%s::%s, %s)", q.field.Column, q.spec.ID, op, v, q.field.Type, id)
+	}
+	return sb.String(), args
+}
+
+// KeyColumn is appended to the select list; scan it into Key.Value.
+func (q *Query) KeyColumn() string {
+	return ", (" + q.field.Column + ")::text"
+}
+
+// OrderLimit orders by the sort column and id and fetches one row more
+// than the page size, so Trim can tell whether there is a next page.
+func (q *Query) OrderLimit() string {
+	dir := "ASC"
+	if q.desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, %s %s LIMIT %d", q.field.Column, dir, q.spec.ID, dir, q.limit+1)
+}
+
+// Trim drops the extra row and returns the cursor of the next page, "" on
+// the last page. keys[i] is the position of items[i].
+func Trim[T any](q *Query, items []T, keys []Key) ([]T, string) {
+	if len(items) <= q.limit {
+		return items, ""
+	}
+	items = items[:q.limit]
+	last := keys[q.limit-1]
+	b, _ := json.Marshal(cursor{Sort: q.sort, Value: last.Value, ID: last.ID, FP: q.fp})
+	return items, base64.RawURLEncoding.EncodeToString(b)
+}
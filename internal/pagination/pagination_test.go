@@ -0,0 +1,176 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testSpec = &Spec{
+	ID: "u.id",
+	Sorts: map[string]Field{
+		"id":         {Column: "u.id", Type: "int"},
+		"name":       {Column: "u.name", Type: "text"},
+		"created_at": {Column: "u.created_at", Type: "timestamptz"},
+	},
+	Default: "-id",
+	Filters: map[string]Filter{
+		"role":   {Cond: `r.name = $?`},
+		"active": {Cond: `u.active = $?`, Kind: Bool},
+		"q":      {Cond: `u.name ILIKE $?`, Like: true},
+		"from":   {Cond: `u.created_at >= $?`, Kind: Date},
+	},
+}
+
+func TestFromQuery(t *testing.T) {
+	r, err := FromQuery(url.Values{"limit": {"20"}, "cursor": {"abc"}, "sort": {"-name"}, "role": {"student"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Limit != 20 || r.Cursor != "abc" || r.Sort != "-name" {
+		t.Fatalf("got %+v", r)
+	}
+	if want := (url.Values{"role": {"student"}}); !reflect.DeepEqual(r.Filters, want) {
+		t.Fatalf("filters %v, want %v", r.Filters, want)
+	}
+
+	for _, limit := range []string{"0", "-1", "201", "ten"} {
+		if _, err := FromQuery(url.Values{"limit": {limit}}); !errors.Is(err, ErrInvalid) {
+			t.Errorf("limit=%s: err %v, want ErrInvalid", limit, err)
+		}
+	}
+}
+
+func TestQueryValidatesSortAndFilters(t *testing.T) {
+	q, err := testSpec.Query(Request{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.sort != "-id" || !q.desc || q.limit != DefaultLimit {
+		t.Fatalf("defaults: sort %q desc %v limit %d", q.sort, q.desc, q.limit)
+	}
+
+	bad := []Request{
+		{Sort: "email"},
+		{Filters: url.Values{"active": {"maybe"}}},
+		{Filters: url.Values{"from": {"yesterday"}}},
+	}
+	for _, r := range bad {
+		if _, err := testSpec.Query(r); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%+v: err %v, want ErrInvalid", r, err)
+		}
+	}
+
+	// filters outside the spec are ignored
+	if _, err := testSpec.Query(Request{Filters: url.Values{"password": {"x"}}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWhere(t *testing.T) {
+	q, err := testSpec.Query(Request{Filters: url.Values{"q": {"50%_off"}, "active": {"true"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	where, args := q.Where([]any{7})
+	if want := ` AND u.active = $2 AND u.name ILIKE $3`; where != want {
+		t.Fatalf("where %q, want %q", where, want)
+	}
+	if want := []any{7, true, `%50\%\_off%`}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("args %v, want %v", args, want)
+	}
+}
+
+func TestTrim(t *testing.T) {
+	r := Request{Limit: 2, Sort: "name", Filters: url.Values{"role": {"student"}}}
+	q, err := testSpec.Query(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, next := Trim(q, []string{"a", "b"}, []Key{{"a", 1}, {"b", 2}})
+	if len(items) != 2 || next != "" {
+		t.Fatalf("last page: %v, cursor %q", items, next)
+	}
+
+	items, next = Trim(q, []string{"a", "b", "c"}, []Key{{"a", 1}, {"b", 2}, {"c", 3}})
+	if len(items) != 2 || next == "" {
+		t.Fatalf("full page: %v, cursor %q", items, next)
+	}
+
+	r.Cursor = next
+	q, err = testSpec.Query(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	where, args := q.Where(nil)
+	if want := ` AND r.name = $1 AND (u.name, u.id) > ($2::text, $3)`; where != want {
+		t.Fatalf("where %q, want %q", where, want)
+	}
+	if want := []any{"student", "b", 2}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("args %v, want %v", args, want)
+	}
+
+	// the cursor is only good for the sort and filters it was issued for
+	for _, other := range []Request{
+		{Limit: 2, Sort: "-name", Filters: r.Filters, Cursor: next},
+		{Limit: 2, Sort: "name", Filters: url.Values{"role": {"teacher"}}, Cursor: next},
+	} {
+		if _, err := testSpec.Query(other); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%+v: err %v, want ErrInvalid", other, err)
+		}
+	}
+}
+
+func TestQueryRejectsForgedCursor(t *testing.T) {
+	q, err := testSpec.Query(Request{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	forge := func(value string, id int) string {
+		b, _ := json.Marshal(cursor{Sort: q.sort, Value: value, ID: id, FP: q.fp})
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	for _, c := range []string{"not base64!", base64.RawURLEncoding.EncodeToString([]byte("{")), forge("1 OR 1=1", 1), forge("12", 0)} {
+		if _, err := testSpec.Query(Request{Cursor: c}); !errors.Is(err, ErrInvalid) {
+			t.Errorf("cursor %q: err %v, want ErrInvalid", c, err)
+		}
+	}
+	if _, err := testSpec.Query(Request{Cursor: forge("12", 12)}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidKey(t *testing.T) {
+	cases := []struct {
+		typ, v string
+		ok     bool
+	}{
+		{"int", "42", true},
+		{"int", "4.2", false},
+		{"date", "2025-09-01", true},
+		{"date", "2025-09-01'", false},
+		{"timestamp", "2025-09-01 08:30:00.123456", true},
+		{"timestamp", "2025-09-01 08:30:00", true},
+		{"timestamp", "tomorrow", false},
+		{"timestamptz", "2025-09-01 08:30:00.123456+00", true},
+		{"timestamptz", "2025-09-01 08:30:00+05:30", true},
+		{"timestamptz", "2025-09-01", false},
+		{"text", "anything ' goes", true},
+	}
+	for _, c := range cases {
+		if got := validKey(c.typ, c.v); got != c.ok {
+			t.Errorf("validKey(%q, %q) = %v, want %v", c.typ, c.v, got, c.ok)
+		}
+	}
+	// what Postgres prints for timestamptz must round-trip
+	ts := time.Date(2025, 9, 1, 8, 30, 0, 0, time.UTC).Format("2006-01-02 15:04:05-07")
+	if !validKey("timestamptz", ts) {
+		t.Errorf("validKey(timestamptz, %q) = false", ts)
+	}
+}
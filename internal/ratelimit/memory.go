@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const sweepEvery = time.Minute
+
+// MemoryStore keeps the buckets of this instance.
+type MemoryStore struct {
+	mu        sync.Mutex
+	tat       map[string]time.Time
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tat: map[string]time.Time{}, lastSweep: time.Now()}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, p Policy) (Result, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.lastSweep) > sweepEvery {
+		s.sweep(now)
+	}
+
+	tat := s.tat[key]
+	if tat.Before(now) {
+		tat = now
+	}
+	next := tat.Add(p.emission())
+	if next.Sub(now) > p.tolerance() {
+		return deny(tat, now, p), nil
+	}
+	s.tat[key] = next
+	return allow(next, now, p), nil
+}
+
+// sweep forgets full buckets; they are the same as no bucket.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, tat := range s.tat {
+		if !tat.After(now) {
+			delete(s.tat, key)
+		}
+	}
+	s.lastSweep = now
+}
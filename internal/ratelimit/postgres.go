@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresStore keeps the buckets in the rate_limits table, so all instances
+// count against the same limits. The clock is the database's.
+type PostgresStore struct{ db *pgxpool.Pool }
+
+func NewPostgresStore(db *pgxpool.Pool) *PostgresStore { return &PostgresStore{db: db} }
+
+// takeSQL moves the bucket's arrival time by one emission ($2) unless that
+// would put it further ahead than the tolerance ($3); then no row comes back.
+const takeSQL = `
+	INSERT INTO rate_limits AS r (key, tat) VALUES ($1, now() + $2::interval)
+	ON CONFLICT (key) DO UPDATE SET tat = GREATEST(r.tat, now()) + $2::interval
+	WHERE GREATEST(r.tat, now()) + $2::interval <= now() + $3::interval
+	RETURNING tat, now()`
+
+func (s *PostgresStore) Take(ctx context.Context, key string, p Policy) (Result, error) {
+	var tat, now time.Time
+	err := s.db.QueryRow(ctx, takeSQL, key, p.emission(), p.tolerance()).Scan(&tat, &now)
+	if err == nil {
+		return allow(tat, now, p), nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return Result{}, err
+	}
+	err = s.db.QueryRow(ctx, `SELECT tat, now() FROM rate_limits WHERE key = $1`, key).Scan(&tat, &now)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// purged in between: the bucket just became full
+		return s.Take(ctx, key, p)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	return deny(tat, now, p), nil
+}
+
+// RunPurge deletes full buckets every interval until ctx is done.
+func (s *PostgresStore) RunPurge(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if _, err := s.db.Exec(ctx, `DELETE FROM rate_limits WHERE tat <= now()`); err != nil {
+				log.Println("ratelimit: purge error:", err)
+			}
+		}
+	}
+}
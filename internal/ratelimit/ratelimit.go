@@ -0,0 +1,84 @@
+// Package ratelimit throttles clients with token buckets. A bucket holds
+// Burst tokens and gets Limit tokens back per Period; every request takes
+// one. Buckets are kept in a Store, in memory for a single instance or in
+// Postgres when several instances share the limits.
+//
+// The stores keep a bucket as its theoretical arrival time (GCRA): the time
+// at which it will be full again. That is one timestamp per client and can
+// be updated in a single statement.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Policy is a token bucket.
+type Policy struct {
+	Limit  int           `yaml:"limit"`  // tokens refilled per Period
+	Period time.Duration `yaml:"period"` // e.g. 1m
+	Burst  int           `yaml:"burst"`  // bucket size; Limit when 0
+}
+
+func (p Policy) burst() int {
+	if p.Burst > 0 {
+		return p.Burst
+	}
+	return p.Limit
+}
+
+// emission is the time one token takes to come back.
+func (p Policy) emission() time.Duration { return p.Period / time.Duration(p.Limit) }
+
+// tolerance is the time a full bucket takes to refill from empty.
+func (p Policy) tolerance() time.Duration { return time.Duration(p.burst()) * p.emission() }
+
+// Result is the state of a bucket after a request.
+type Result struct {
+	Allowed    bool
+	Limit      int           // bucket size
+	Remaining  int           // tokens left
+	RetryAfter time.Duration // when denied: until the next token
+	Reset      time.Duration // until the bucket is full
+}
+
+// Store takes a token from the bucket of key.
+type Store interface {
+	Take(ctx context.Context, key string, p Policy) (Result, error)
+}
+
+// allow is the result of a request that moved the bucket's arrival time to tat.
+func allow(tat, now time.Time, p Policy) Result {
+	full := tat.Sub(now)
+	return Result{Allowed: true, Limit: p.burst(), Remaining: int((p.tolerance() - full) / p.emission()), Reset: full}
+}
+
+// deny is the result of a request refused while the bucket's arrival time
+// is tat.
+func deny(tat, now time.Time, p Policy) Result {
+	if tat.Before(now) {
+		tat = now
+	}
+	return Result{Limit: p.burst(), RetryAfter: tat.Add(p.emission()).Sub(now) - p.tolerance(), Reset: tat.Sub(now)}
+}
+
+// Limiter applies the policy of a route group to its clients.
+type Limiter struct {
+	store    Store
+	policies map[string]Policy
+}
+
+func New(store Store, policies map[string]Policy) *Limiter {
+	return &Limiter{store: store, policies: policies}
+}
+
+// Take counts a request of client against the policy of group. ok is false
+// when group has no policy and is not limited.
+func (l *Limiter) Take(ctx context.Context, group, client string) (res Result, p Policy, ok bool, err error) {
+	p, ok = l.policies[group]
+	if !ok {
+		return res, p, false, nil
+	}
+	res, err = l.store.Take(ctx, group+":"+client, p)
+	return res, p, true, err
+}
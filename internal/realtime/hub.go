@@ -0,0 +1,138 @@
+// Package realtime fans out attendance and enrollment changes to the open
+// SSE and WebSocket connections of this instance. The changes come from
+// Postgres: triggers NOTIFY them on Channel and Listen forwards them to the
+// Hub, so every instance sees the writes of all.
+package realtime
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// Channel is the NOTIFY channel of the triggers in migration 00016.
+const Channel = "lms_events"
+
+// buffered events per subscription; a client that falls further behind is
+// disconnected and has to reconnect and refetch.
+const subscriptionBuffer = 64
+
+// Event is one change in a course.
+type Event struct {
+	Type      string          `json:"type"` // attendance, enrollment
+	CourseID  int             `json:"course_id"`
+	StudentID int             `json:"student_id"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Hub keeps the subscriptions of each course topic.
+type Hub struct {
+	mu     sync.RWMutex
+	topics map[int]map[*Subscription]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{topics: map[int]map[*Subscription]struct{}{}}
+}
+
+// Subscription receives the events of the courses it joined on C. C is
+// closed by Close and when the subscriber is too slow.
+type Subscription struct {
+	C <-chan Event
+
+	hub       *Hub
+	ch        chan Event
+	studentID int
+	courses   map[int]bool // guarded by hub.mu
+	closed    bool
+}
+
+// Subscribe starts a subscription without courses. With studentID > 0 it
+// only receives events about that student.
+func (h *Hub) Subscribe(studentID int) *Subscription {
+	ch := make(chan Event, subscriptionBuffer)
+	return &Subscription{C: ch, hub: h, ch: ch, studentID: studentID, courses: map[int]bool{}}
+}
+
+// Join adds a course topic.
+func (s *Subscription) Join(courseID int) {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.courses[courseID] = true
+	if s.hub.topics[courseID] == nil {
+		s.hub.topics[courseID] = map[*Subscription]struct{}{}
+	}
+	s.hub.topics[courseID][s] = struct{}{}
+}
+
+// Leave removes a course topic.
+func (s *Subscription) Leave(courseID int) {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.leave(s, courseID)
+}
+
+// Courses returns the joined courses.
+func (s *Subscription) Courses() []int {
+	s.hub.mu.RLock()
+	defer s.hub.mu.RUnlock()
+	out := make([]int, 0, len(s.courses))
+	for id := range s.courses {
+		out = append(out, id)
+	}
+	return out
+}
+
+// Close leaves all topics and closes C. It may be called more than once.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.close(s)
+}
+
+// Publish delivers e to the subscriptions of its course without blocking.
+func (h *Hub) Publish(e Event) {
+	h.mu.RLock()
+	var slow []*Subscription
+	for s := range h.topics[e.CourseID] {
+		if s.studentID > 0 && s.studentID != e.StudentID {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			slow = append(slow, s)
+		}
+	}
+	h.mu.RUnlock()
+
+	if len(slow) > 0 {
+		h.mu.Lock()
+		for _, s := range slow {
+			h.close(s)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// leave and close expect h.mu to be held.
+func (h *Hub) leave(s *Subscription, courseID int) {
+	delete(s.courses, courseID)
+	delete(h.topics[courseID], s)
+	if len(h.topics[courseID]) == 0 {
+		delete(h.topics, courseID)
+	}
+}
+
+func (h *Hub) close(s *Subscription) {
+	if s.closed {
+		return
+	}
+	for id := range s.courses {
+		h.leave(s, id)
+	}
+	s.closed = true
+	close(s.ch)
+}
@@ -0,0 +1,56 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const retryListen = 5 * time.Second
+
+// Listen LISTENs on Channel with a connection of its own and publishes every
+// notification until ctx ends. A lost connection is reopened; events sent in
+// between are missed, so clients refetch after a reconnect of their own.
+func (h *Hub) Listen(ctx context.Context, pool *pgxpool.Pool) {
+	for {
+		err := h.listen(ctx, pool)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("realtime: listen on %s: %v; retrying in %s", Channel, err, retryListen)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryListen):
+		}
+	}
+}
+
+func (h *Hub) listen(ctx context.Context, pool *pgxpool.Pool) error {
+	pc, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// the connection stays in LISTEN mode, so it must not go back to the pool
+	conn := pc.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, `LISTEN `+Channel); err != nil {
+		return err
+	}
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var e Event
+		if err := json.Unmarshal([]byte(n.Payload), &e); err != nil {
+			log.Printf("realtime: bad payload %q: %v", n.Payload, err)
+			continue
+		}
+		h.Publish(e)
+	}
+}
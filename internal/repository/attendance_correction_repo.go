@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AttendanceCorrectionRepo struct{ db *pgxpool.Pool }
+
+func NewAttendanceCorrectionRepo(db *pgxpool.Pool) *AttendanceCorrectionRepo {
+	return &AttendanceCorrectionRepo{db: db}
+}
+
+const correctionColumns = `id, course_id, student_id, lesson_date, requested_status, reason, status,
+	COALESCE(reviewed_by,0), COALESCE(review_comment,''), created_at, reviewed_at`
+
+func scanCorrection(row pgx.Row) (model.AttendanceCorrection, error) {
+	var c model.AttendanceCorrection
+	err := row.Scan(&c.ID, &c.CourseID, &c.StudentID, &c.LessonDate, &c.RequestedStatus, &c.Reason,
+		&c.Status, &c.ReviewedBy, &c.ReviewComment, &c.CreatedAt, &c.ReviewedAt)
+	return c, err
+}
+
+func (r *AttendanceCorrectionRepo) Create(ctx context.Context, c model.AttendanceCorrection) (int, error) {
+	var id int
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO attendance_corrections(course_id, student_id, lesson_date, requested_status, reason)
+		 VALUES ($1,$2,$3,$4,$5) RETURNING id`,
+		c.CourseID, c.StudentID, c.LessonDate, c.RequestedStatus, c.Reason,
+	).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return 0, apperr.Conflict("correction_pending", "a correction for this lesson is already pending")
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// ListByCourse lists correction requests of a course. Empty status means any.
+func (r *AttendanceCorrectionRepo) ListByCourse(ctx context.Context, courseID int, status string) ([]model.AttendanceCorrection, error) {
+	q := `SELECT ` + correctionColumns + ` FROM attendance_corrections WHERE course_id = $1`
+	args := []any{courseID}
+	if status != "" {
+		q += ` AND status = $2`
+		args = append(args, status)
+	}
+	q += ` ORDER BY created_at ASC LIMIT 200`
+	return r.list(ctx, q, args...)
+}
+
+// ListByStudent lists a student's own requests. If courseID == 0, lists across all courses.
+func (r *AttendanceCorrectionRepo) ListByStudent(ctx context.Context, studentID int, courseID int) ([]model.AttendanceCorrection, error) {
+	q := `SELECT ` + correctionColumns + ` FROM attendance_corrections WHERE student_id = $1`
+	args := []any{studentID}
+	if courseID > 0 {
+		q += ` AND course_id = $2`
+		args = append(args, courseID)
+	}
+	q += ` ORDER BY created_at DESC LIMIT 200`
+	return r.list(ctx, q, args...)
+}
+
+func (r *AttendanceCorrectionRepo) list(ctx context.Context, q string, args ...any) ([]model.AttendanceCorrection, error) {
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.AttendanceCorrection, 0)
+	for rows.Next() {
+		c, err := scanCorrection(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// Approve closes a pending request and applies the requested status to the
+// attendance record in the same transaction, so the change shows up in history.
+func (r *AttendanceCorrectionRepo) Approve(ctx context.Context, courseID, id, reviewerID int, comment string) (model.AttendanceCorrection, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.AttendanceCorrection{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	c, err := reviewCorrection(ctx, tx, courseID, id, reviewerID, comment, "approved")
+	if err != nil {
+		return model.AttendanceCorrection{}, err
+	}
+
+	note := fmt.Sprintf("correction #%d: %s", c.ID, c.Reason)
+	if _, err := tx.Exec(ctx, upsertAttendanceSQL,
+		c.CourseID, c.StudentID, c.LessonDate, c.RequestedStatus, note, reviewerID, AnyVersion,
+	); err != nil {
+		return model.AttendanceCorrection{}, err
+	}
+	return c, tx.Commit(ctx)
+}
+
+func (r *AttendanceCorrectionRepo) Reject(ctx context.Context, courseID, id, reviewerID int, comment string) (model.AttendanceCorrection, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.AttendanceCorrection{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	c, err := reviewCorrection(ctx, tx, courseID, id, reviewerID, comment, "rejected")
+	if err != nil {
+		return model.AttendanceCorrection{}, err
+	}
+	return c, tx.Commit(ctx)
+}
+
+func reviewCorrection(ctx context.Context, tx pgx.Tx, courseID, id, reviewerID int, comment, status string) (model.AttendanceCorrection, error) {
+	c, err := scanCorrection(tx.QueryRow(ctx,
+		`UPDATE attendance_corrections
+		 SET status = $4, reviewed_by = $3, review_comment = NULLIF($5,''), reviewed_at = now()
+		 WHERE id = $1 AND course_id = $2 AND status = 'pending'
+		 RETURNING `+correctionColumns,
+		id, courseID, reviewerID, status, comment,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.AttendanceCorrection{}, apperr.Conflict("correction_not_pending", "correction not found or already reviewed")
+	}
+	return c, err
+}
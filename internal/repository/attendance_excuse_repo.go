@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AttendanceExcuseRepo struct{ db *pgxpool.Pool }
+
+func NewAttendanceExcuseRepo(db *pgxpool.Pool) *AttendanceExcuseRepo {
+	return &AttendanceExcuseRepo{db: db}
+}
+
+const excuseColumns = `id, student_id, COALESCE(course_id,0), date_from, date_to, reason,
+	COALESCE(document_path,''), COALESCE(document_name,''), COALESCE(document_type,''), status,
+	COALESCE(reviewed_by,0), COALESCE(review_comment,''), created_at, reviewed_at`
+
+func scanExcuse(row pgx.Row) (model.AttendanceExcuse, error) {
+	var e model.AttendanceExcuse
+	err := row.Scan(&e.ID, &e.StudentID, &e.CourseID, &e.DateFrom, &e.DateTo, &e.Reason,
+		&e.DocumentPath, &e.DocumentName, &e.DocumentType, &e.Status,
+		&e.ReviewedBy, &e.ReviewComment, &e.CreatedAt, &e.ReviewedAt)
+	return e, err
+}
+
+func (r *AttendanceExcuseRepo) Create(ctx context.Context, e model.AttendanceExcuse) (int, error) {
+	var id int
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO attendance_excuses(student_id, course_id, date_from, date_to, reason,
+		                                document_path, document_name, document_type)
+		 VALUES ($1, NULLIF($2,0), $3, $4, $5, NULLIF($6,''), NULLIF($7,''), NULLIF($8,''))
+		 RETURNING id`,
+		e.StudentID, e.CourseID, e.DateFrom, e.DateTo, e.Reason,
+		e.DocumentPath, e.DocumentName, e.DocumentType,
+	).Scan(&id)
+	return id, err
+}
+
+func (r *AttendanceExcuseRepo) GetByID(ctx context.Context, id int) (model.AttendanceExcuse, error) {
+	e, err := scanExcuse(r.db.QueryRow(ctx, `SELECT `+excuseColumns+` FROM attendance_excuses WHERE id=$1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return e, apperr.NotFound("excuse_not_found", "excuse not found")
+	}
+	return e, err
+}
+
+func (r *AttendanceExcuseRepo) ListByStudent(ctx context.Context, studentID int) ([]model.AttendanceExcuse, error) {
+	return r.list(ctx,
+		`SELECT `+excuseColumns+` FROM attendance_excuses WHERE student_id=$1 ORDER BY date_from DESC LIMIT 200`,
+		studentID)
+}
+
+// List filters by status ("" = any) and course (0 = any). Excuses that cover
+// all courses are included when filtering by course.
+func (r *AttendanceExcuseRepo) List(ctx context.Context, status string, courseID int) ([]model.AttendanceExcuse, error) {
+	return r.list(ctx,
+		`SELECT `+excuseColumns+` FROM attendance_excuses
+		 WHERE ($1 = '' OR status = $1)
+		   AND ($2 = 0 OR course_id IS NULL OR course_id = $2)
+		 ORDER BY created_at ASC LIMIT 200`,
+		status, courseID)
+}
+
+func (r *AttendanceExcuseRepo) list(ctx context.Context, q string, args ...any) ([]model.AttendanceExcuse, error) {
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.AttendanceExcuse, 0)
+	for rows.Next() {
+		e, err := scanExcuse(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// Covers reports whether an approved excuse covers the student on that date.
+func (r *AttendanceExcuseRepo) Covers(ctx context.Context, studentID, courseID int, date time.Time) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx,
+		`SELECT EXISTS(
+		   SELECT 1 FROM attendance_excuses
+		   WHERE student_id = $1 AND status = 'approved'
+		     AND (course_id IS NULL OR course_id = $2)
+		     AND $3::date BETWEEN date_from AND date_to)`,
+		studentID, courseID, date,
+	).Scan(&exists)
+	return exists, err
+}
+
+// CoveredStudents returns the students of a course that have an approved
+// excuse covering the date.
+func (r *AttendanceExcuseRepo) CoveredStudents(ctx context.Context, courseID int, date time.Time) (map[int]bool, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT DISTINCT student_id FROM attendance_excuses
+		 WHERE status = 'approved'
+		   AND (course_id IS NULL OR course_id = $1)
+		   AND $2::date BETWEEN date_from AND date_to`,
+		courseID, date,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := map[int]bool{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out[id] = true
+	}
+	return out, rows.Err()
+}
+
+// Approve closes a pending excuse and turns every absence it covers into
+// "excused", writing attendance history for each converted record.
+// It returns the excuse and the number of converted records.
+func (r *AttendanceExcuseRepo) Approve(ctx context.Context, id, reviewerID int, comment string) (model.AttendanceExcuse, int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.AttendanceExcuse{}, 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	e, err := reviewExcuse(ctx, tx, id, reviewerID, comment, "approved")
+	if err != nil {
+		return model.AttendanceExcuse{}, 0, err
+	}
+
+	tag, err := tx.Exec(ctx,
+		`WITH old AS (
+			SELECT a.id, a.status, a.note
+			FROM attendance a
+			JOIN attendance_statuses s ON s.code = a.status
+			WHERE a.student_id = $1
+			  AND a.lesson_date BETWEEN $2 AND $3
+			  AND ($4 = 0 OR a.course_id = $4)
+			  AND s.counts_as = 'absent'
+			FOR UPDATE OF a
+		), up AS (
+			UPDATE attendance a SET status = 'excused', note = $5
+			FROM old WHERE a.id = old.id
+			RETURNING a.id, a.status, a.note
+		)
+		INSERT INTO attendance_history(attendance_id, old_status, old_note, new_status, new_note, changed_by)
+		SELECT up.id, old.status, old.note, up.status, up.note, NULLIF($6, 0)
+		FROM up JOIN old ON old.id = up.id`,
+		e.StudentID, e.DateFrom, e.DateTo, e.CourseID, fmt.Sprintf("excuse #%d", e.ID), reviewerID,
+	)
+	if err != nil {
+		return model.AttendanceExcuse{}, 0, err
+	}
+	return e, int(tag.RowsAffected()), tx.Commit(ctx)
+}
+
+func (r *AttendanceExcuseRepo) Reject(ctx context.Context, id, reviewerID int, comment string) (model.AttendanceExcuse, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.AttendanceExcuse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	e, err := reviewExcuse(ctx, tx, id, reviewerID, comment, "rejected")
+	if err != nil {
+		return model.AttendanceExcuse{}, err
+	}
+	return e, tx.Commit(ctx)
+}
+
+func reviewExcuse(ctx context.Context, tx pgx.Tx, id, reviewerID int, comment, status string) (model.AttendanceExcuse, error) {
+	e, err := scanExcuse(tx.QueryRow(ctx,
+		`UPDATE attendance_excuses
+		 SET status = $3, reviewed_by = $2, review_comment = NULLIF($4,''), reviewed_at = now()
+		 WHERE id = $1 AND status = 'pending'
+		 RETURNING `+excuseColumns,
+		id, reviewerID, status, comment,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.AttendanceExcuse{}, apperr.Conflict("excuse_not_pending", "excuse not found or already reviewed")
+	}
+	return e, err
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"log"
 	"strconv"
+	"time"
 
 	"lms-backend/internal/domain/model"
+	"lms-backend/internal/pagination"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -14,28 +17,204 @@ type AttendanceRepo struct{ db *pgxpool.Pool }
 
 func NewAttendanceRepo(db *pgxpool.Pool) *AttendanceRepo { return &AttendanceRepo{db: db} }
 
-func (r *AttendanceRepo) Upsert(ctx context.Context, a model.Attendance) error {
-	_, err := r.db.Exec(ctx,
-		`INSERT INTO attendance(course_id, student_id, lesson_date, status, note)
-		 VALUES ($1,$2,$3,$4,$5)
-		 ON CONFLICT (course_id, student_id, lesson_date)
-		 DO UPDATE SET status = EXCLUDED.status, note = EXCLUDED.note`,
-		a.CourseID, a.StudentID, a.LessonDate, a.Status, a.Note,
+// upsertAttendanceSQL writes the mark and, when status or note actually
+// changed, appends a row to attendance_history in the same statement.
+// The "old" CTE sees the row as it was before the upsert. An existing mark is
+// only overwritten when $7 is AnyVersion or its version; the statement
+// returns the new version (NULL when nothing was written), whether the mark
+// changed and the version the mark had before.
+const upsertAttendanceSQL = `
+	WITH old AS (
+		SELECT id, status, note, version FROM attendance
+		WHERE course_id = $1 AND student_id = $2 AND lesson_date = $3
+		FOR UPDATE
+	), up AS (
+		INSERT INTO attendance(course_id, student_id, lesson_date, status, note)
+		VALUES ($1,$2,$3,$4,$5)
+		ON CONFLICT (course_id, student_id, lesson_date)
+		DO UPDATE SET status = EXCLUDED.status, note = EXCLUDED.note
+		WHERE $7 = 0 OR attendance.version = $7
+		RETURNING id, status, note, version
+	), hist AS (
+		INSERT INTO attendance_history(attendance_id, old_status, old_note, new_status, new_note, changed_by)
+		SELECT up.id, old.status, old.note, up.status, up.note, NULLIF($6, 0)
+		FROM up LEFT JOIN old ON true
+		WHERE old.id IS NULL
+		   OR old.status IS DISTINCT FROM up.status
+		   OR COALESCE(old.note,'') IS DISTINCT FROM COALESCE(up.note,'')
+		RETURNING 1
 	)
-	return err
+	SELECT (SELECT version FROM up), EXISTS (SELECT 1 FROM hist), (SELECT version FROM old)`
+
+// Upsert saves a mark and records the change made by changedBy in the
+// history. An existing mark must have the given version, unless version is
+// AnyVersion; NewOnly only creates. It returns the version of the saved mark.
+func (r *AttendanceRepo) Upsert(ctx context.Context, a model.Attendance, changedBy int, version int) (int, error) {
+	var (
+		saved, prev *int
+		changed     bool
+	)
+	err := r.db.QueryRow(ctx, upsertAttendanceSQL,
+		a.CourseID, a.StudentID, a.LessonDate, a.Status, a.Note, changedBy, version,
+	).Scan(&saved, &changed, &prev)
+	switch {
+	case err != nil:
+		return 0, err
+	case saved != nil:
+		return *saved, nil
+	case version == NewOnly:
+		return 0, ErrVersionRequired
+	default:
+		return 0, ErrVersionMismatch
+	}
+}
+
+// UpsertResult is what UpsertMany did with one row.
+type UpsertResult struct {
+	Version int   // of the stored mark, 0 when there is none
+	Changed bool  // the row created or modified the mark
+	Err     error // ErrVersionRequired or ErrVersionMismatch when the row was refused
+}
+
+// UpsertMany saves a whole roll call in one transaction, sending all rows in
+// a single batch. Each row's Version is its precondition, as in Upsert, or
+// KeepExisting. When any row is refused nothing is saved; the results tell
+// which rows and the versions they would have to name.
+func (r *AttendanceRepo) UpsertMany(ctx context.Context, items []model.Attendance, changedBy int) ([]UpsertResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	b := &pgx.Batch{}
+	for _, a := range items {
+		b.Queue(upsertAttendanceSQL, a.CourseID, a.StudentID, a.LessonDate, a.Status, a.Note, changedBy, a.Version)
+	}
+	br := tx.SendBatch(ctx, b)
+	out := make([]UpsertResult, len(items))
+	before := make([]int, len(items)) // what a refused roll call leaves in place
+	refused := false
+	for i, a := range items {
+		var saved, prev *int
+		if err := br.QueryRow().Scan(&saved, &out[i].Changed, &prev); err != nil {
+			br.Close()
+			return nil, err
+		}
+		if prev != nil {
+			before[i] = *prev
+		}
+		if saved != nil {
+			out[i].Version = *saved
+			continue
+		}
+		out[i].Version = before[i]
+		switch a.Version {
+		case KeepExisting:
+		case NewOnly:
+			out[i].Err, refused = ErrVersionRequired, true
+		default:
+			out[i].Err, refused = ErrVersionMismatch, true
+		}
+	}
+	if err := br.Close(); err != nil {
+		return nil, err
+	}
+	if refused {
+		for i := range out {
+			out[i].Version, out[i].Changed = before[i], false
+		}
+		return out, nil
+	}
+	return out, tx.Commit(ctx)
+}
+
+// AttendanceListSpec is what attendance lists accept.
+var AttendanceListSpec = &pagination.Spec{
+	ID: "a.id",
+	Sorts: map[string]pagination.Field{
+		"lesson_date": {Column: "a.lesson_date", Type: "date"},
+		"id":          {Column: "a.id", Type: "int"},
+	},
+	Default: "-lesson_date",
+	Filters: map[string]pagination.Filter{
+		"status":     {Cond: `a.status = $?`},
+		"student_id": {Cond: `a.student_id = $?`, Kind: pagination.Int},
+		"course_id":  {Cond: `a.course_id = $?`, Kind: pagination.Int},
+		"from":       {Cond: `a.lesson_date >= $?`, Kind: pagination.Date},
+		"to":         {Cond: `a.lesson_date <= $?`, Kind: pagination.Date},
+	},
 }
 
-func (r *AttendanceRepo) ListByCourse(ctx context.Context, courseID int) ([]model.Attendance, error) {
+// ListByCourse returns one page of the course's marks and the cursor of the
+// next page.
+func (r *AttendanceRepo) ListByCourse(ctx context.Context, courseID int, req pagination.Request) ([]model.Attendance, string, error) {
+	out, next, err := r.list(ctx, req, `a.course_id = $1`, courseID)
+	if err != nil {
+		log.Println("Error listing attendance by course:", err)
+	}
+	return out, next, err
+}
+
+// ListByStudent returns one page of a student's marks across all courses;
+// ?course_id= narrows it to one.
+func (r *AttendanceRepo) ListByStudent(ctx context.Context, studentID int, req pagination.Request) ([]model.Attendance, string, error) {
+	return r.list(ctx, req, `a.student_id = $1`, studentID)
+}
+
+func (r *AttendanceRepo) list(ctx context.Context, req pagination.Request, where string, args ...any) ([]model.Attendance, string, error) {
+	q, err := AttendanceListSpec.Query(req)
+	if err != nil {
+		return nil, "", err
+	}
+	cond, args := q.Where(args)
 	rows, err := r.db.Query(ctx,
-		`SELECT id, course_id, student_id, lesson_date, status, COALESCE(note,'')
-		 FROM attendance
-		 WHERE course_id = $1
-		 ORDER BY lesson_date DESC, student_id ASC
-		 LIMIT 200`,
-		courseID,
+		`SELECT a.id, a.course_id, a.student_id, a.lesson_date, a.status, COALESCE(a.note,''), a.version`+q.KeyColumn()+`
+		 FROM attendance a
+		 WHERE `+where+cond+q.OrderLimit(),
+		args...,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	out := make([]model.Attendance, 0)
+	keys := make([]pagination.Key, 0)
+	for rows.Next() {
+		var (
+			a model.Attendance
+			k pagination.Key
+		)
+		if err := rows.Scan(&a.ID, &a.CourseID, &a.StudentID, &a.LessonDate, &a.Status, &a.Note, &a.Version, &k.Value); err != nil {
+			return nil, "", err
+		}
+		k.ID = a.ID
+		out = append(out, a)
+		keys = append(keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+	out, next := pagination.Trim(q, out, keys)
+	return out, next, nil
+}
+
+// ListByCourses returns the marks of all courses in courseIDs between from
+// and to (zero = unbounded), newest lesson first; studentID > 0 narrows them
+// to one student.
+func (r *AttendanceRepo) ListByCourses(ctx context.Context, courseIDs []int, studentID int, from, to time.Time) ([]model.Attendance, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT a.id, a.course_id, a.student_id, a.lesson_date, a.status, COALESCE(a.note,''), a.version
+		 FROM attendance a
+		 WHERE a.course_id = ANY($1)
+		   AND ($2 = 0 OR a.student_id = $2)
+		   AND ($3::date IS NULL OR a.lesson_date >= $3)
+		   AND ($4::date IS NULL OR a.lesson_date <= $4)
+		 ORDER BY a.lesson_date DESC, a.id DESC`,
+		courseIDs, studentID, nullDate(from), nullDate(to),
 	)
 	if err != nil {
-		log.Println("Error listing attendance by course:", err)
 		return nil, err
 	}
 	defer rows.Close()
@@ -43,7 +222,7 @@ func (r *AttendanceRepo) ListByCourse(ctx context.Context, courseID int) ([]mode
 	out := make([]model.Attendance, 0)
 	for rows.Next() {
 		var a model.Attendance
-		if err := rows.Scan(&a.ID, &a.CourseID, &a.StudentID, &a.LessonDate, &a.Status, &a.Note); err != nil {
+		if err := rows.Scan(&a.ID, &a.CourseID, &a.StudentID, &a.LessonDate, &a.Status, &a.Note, &a.Version); err != nil {
 			return nil, err
 		}
 		out = append(out, a)
@@ -51,34 +230,210 @@ func (r *AttendanceRepo) ListByCourse(ctx context.Context, courseID int) ([]mode
 	return out, rows.Err()
 }
 
-// ListByStudent lists attendance for a student. If courseID == 0, lists across all courses.
-func (r *AttendanceRepo) ListByStudent(ctx context.Context, studentID int, courseID int) ([]model.Attendance, error) {
-	q := `SELECT id, course_id, student_id, lesson_date, status, COALESCE(note,'')
-	      FROM attendance
-	      WHERE student_id = $1`
-	args := []any{studentID}
-	if courseID > 0 {
-		q += ` AND course_id = $2`
-		args = append(args, courseID)
+func nullDate(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
 	}
-	q += ` ORDER BY lesson_date DESC, course_id DESC LIMIT 500`
+	return &t
+}
 
-	rows, err := r.db.Query(ctx, q, args...)
+// ListHistory returns every version of a student's marks in a course,
+// newest lesson first and changes in the order they happened.
+func (r *AttendanceRepo) ListHistory(ctx context.Context, courseID int, studentID int) ([]model.AttendanceChange, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT h.id, h.attendance_id, a.lesson_date,
+		        COALESCE(h.old_status,''), COALESCE(h.old_note,''),
+		        h.new_status, COALESCE(h.new_note,''),
+		        COALESCE(h.changed_by,0), h.changed_at
+		 FROM attendance_history h
+		 JOIN attendance a ON a.id = h.attendance_id
+		 WHERE a.course_id = $1 AND a.student_id = $2
+		 ORDER BY a.lesson_date DESC, h.changed_at ASC, h.id ASC`,
+		courseID, studentID,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	out := make([]model.Attendance, 0)
+	out := make([]model.AttendanceChange, 0)
 	for rows.Next() {
-		var a model.Attendance
-		if err := rows.Scan(&a.ID, &a.CourseID, &a.StudentID, &a.LessonDate, &a.Status, &a.Note); err != nil {
+		var h model.AttendanceChange
+		if err := rows.Scan(&h.ID, &h.AttendanceID, &h.LessonDate, &h.OldStatus, &h.OldNote,
+			&h.NewStatus, &h.NewNote, &h.ChangedBy, &h.ChangedAt); err != nil {
 			return nil, err
 		}
-		out = append(out, a)
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+// Tally counts marks per course, student and status. Filters are optional:
+// pass 0 to include every course or every student.
+func (r *AttendanceRepo) Tally(ctx context.Context, courseID int, studentID int) ([]model.AttendanceTally, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT a.course_id, a.student_id, a.status, s.counts_as, COUNT(*)
+		 FROM attendance a
+		 JOIN attendance_statuses s ON s.code = a.status
+		 WHERE ($1 = 0 OR a.course_id = $1)
+		   AND ($2 = 0 OR a.student_id = $2)
+		 GROUP BY a.course_id, a.student_id, a.status, s.counts_as
+		 ORDER BY a.course_id, a.student_id`,
+		courseID, studentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.AttendanceTally, 0)
+	for rows.Next() {
+		var t model.AttendanceTally
+		if err := rows.Scan(&t.CourseID, &t.StudentID, &t.Status, &t.CountsAs, &t.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// ListMarks returns every mark of a course with how its status counts,
+// ordered by student and date. Unlike ListByCourse it is not capped.
+func (r *AttendanceRepo) ListMarks(ctx context.Context, courseID int) ([]model.AttendanceMark, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT a.student_id, a.lesson_date, a.status, s.counts_as
+		 FROM attendance a
+		 JOIN attendance_statuses s ON s.code = a.status
+		 WHERE a.course_id = $1
+		 ORDER BY a.student_id ASC, a.lesson_date ASC`,
+		courseID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.AttendanceMark, 0)
+	for rows.Next() {
+		var m model.AttendanceMark
+		if err := rows.Scan(&m.StudentID, &m.LessonDate, &m.Status, &m.CountsAs); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
 	}
 	return out, rows.Err()
 }
 
+// CoursesWithMarks returns the IDs of courses that have any attendance.
+func (r *AttendanceRepo) CoursesWithMarks(ctx context.Context) ([]int, error) {
+	rows, err := r.db.Query(ctx, `SELECT DISTINCT course_id FROM attendance ORDER BY course_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+// LessonDates returns the distinct lesson dates of a course within [from, to].
+func (r *AttendanceRepo) LessonDates(ctx context.Context, courseID int, from, to time.Time) ([]time.Time, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT DISTINCT lesson_date FROM attendance
+		 WHERE course_id = $1 AND lesson_date BETWEEN $2 AND $3
+		 ORDER BY lesson_date ASC`,
+		courseID, from, to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]time.Time, 0)
+	for rows.Next() {
+		var d time.Time
+		if err := rows.Scan(&d); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// StreamRegister walks the course register within [from, to] one student at
+// a time, so large courses never sit in memory. Active students without
+// marks are included.
+func (r *AttendanceRepo) StreamRegister(ctx context.Context, courseID int, from, to time.Time, fn func(model.RegisterRow) error) error {
+	rows, err := r.db.Query(ctx,
+		`SELECT u.id, u.full_name, u.email, a.lesson_date, COALESCE(a.status,''), COALESCE(s.counts_as,'')
+		 FROM (
+		   SELECT student_id FROM enrollments WHERE course_id = $1 AND status = 'active'
+		   UNION
+		   SELECT student_id FROM attendance WHERE course_id = $1 AND lesson_date BETWEEN $2 AND $3
+		 ) st
+		 JOIN users u ON u.id = st.student_id
+		 LEFT JOIN attendance a ON a.course_id = $1 AND a.student_id = u.id AND a.lesson_date BETWEEN $2 AND $3
+		 LEFT JOIN attendance_statuses s ON s.code = a.status
+		 ORDER BY u.full_name ASC, u.id ASC, a.lesson_date ASC`,
+		courseID, from, to,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var cur *model.RegisterRow
+	for rows.Next() {
+		var (
+			id             int
+			name, email    string
+			date           *time.Time
+			status, counts string
+		)
+		if err := rows.Scan(&id, &name, &email, &date, &status, &counts); err != nil {
+			return err
+		}
+		if cur == nil || cur.StudentID != id {
+			if cur != nil {
+				if err := fn(*cur); err != nil {
+					return err
+				}
+			}
+			cur = &model.RegisterRow{
+				StudentID: id, FullName: name, Email: email, Marks: map[string]string{},
+				Summary: model.AttendanceSummary{CourseID: courseID, StudentID: id, ByStatus: map[string]int{}},
+			}
+		}
+		if date == nil {
+			continue
+		}
+		cur.Marks[date.Format("2006-01-02")] = status
+		cur.Summary.Total++
+		cur.Summary.ByStatus[status]++
+		switch counts {
+		case "present":
+			cur.Summary.Attended++
+		case "absent":
+			cur.Summary.Absent++
+		case "excused":
+			cur.Summary.Excused++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if cur != nil {
+		return fn(*cur)
+	}
+	return nil
+}
+
 // helper for debugging
 var _ = strconv.Itoa
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AttendanceStatusRepo struct{ db *pgxpool.Pool }
+
+func NewAttendanceStatusRepo(db *pgxpool.Pool) *AttendanceStatusRepo {
+	return &AttendanceStatusRepo{db: db}
+}
+
+func (r *AttendanceStatusRepo) List(ctx context.Context) ([]model.AttendanceStatus, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT code, label, counts_as, builtin FROM attendance_statuses ORDER BY builtin DESC, code ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.AttendanceStatus, 0)
+	for rows.Next() {
+		var s model.AttendanceStatus
+		if err := rows.Scan(&s.Code, &s.Label, &s.CountsAs, &s.Builtin); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (r *AttendanceStatusRepo) Get(ctx context.Context, code string) (model.AttendanceStatus, error) {
+	var s model.AttendanceStatus
+	err := r.db.QueryRow(ctx,
+		`SELECT code, label, counts_as, builtin FROM attendance_statuses WHERE code=$1`,
+		code,
+	).Scan(&s.Code, &s.Label, &s.CountsAs, &s.Builtin)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return s, apperr.New(apperr.KindInvalid, "unknown_status", "unknown attendance status: "+code)
+	}
+	return s, err
+}
+
+func (r *AttendanceStatusRepo) Create(ctx context.Context, s model.AttendanceStatus) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO attendance_statuses(code, label, counts_as) VALUES ($1,$2,$3)`,
+		s.Code, s.Label, s.CountsAs,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return apperr.Conflict("status_exists", "status already exists")
+	}
+	return err
+}
+
+// Delete removes a custom status. Built-in ones and statuses already used by
+// attendance records cannot be deleted.
+func (r *AttendanceStatusRepo) Delete(ctx context.Context, code string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM attendance_statuses WHERE code=$1 AND NOT builtin`, code)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return apperr.Conflict("status_in_use", "status is used by attendance records")
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound("status_not_found", "status not found or built-in")
+	}
+	return nil
+}
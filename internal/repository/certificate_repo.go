@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CertificateRepo struct{ db *pgxpool.Pool }
+
+func NewCertificateRepo(db *pgxpool.Pool) *CertificateRepo { return &CertificateRepo{db: db} }
+
+// ErrCertificateExists means a valid certificate was issued concurrently.
+var ErrCertificateExists = apperr.Conflict("certificate_exists", "certificate already issued")
+
+const certificateSelect = `
+SELECT id, code, enrollment_id, student_id, student_name, course_id, course_title, teacher_name,
+       term, credits::float8, grade::float8, attendance_rate::float8, completed_at, issued_at,
+       signature, revoked_at, revoke_reason
+FROM certificates`
+
+func scanCertificate(row pgx.Row) (model.Certificate, error) {
+	var c model.Certificate
+	err := row.Scan(&c.ID, &c.Code, &c.EnrollmentID, &c.StudentID, &c.StudentName, &c.CourseID, &c.CourseTitle,
+		&c.TeacherName, &c.Term, &c.Credits, &c.Grade, &c.AttendanceRate, &c.CompletedAt, &c.IssuedAt,
+		&c.Signature, &c.RevokedAt, &c.RevokeReason)
+	return c, err
+}
+
+func (r *CertificateRepo) GetByCode(ctx context.Context, code string) (model.Certificate, error) {
+	return scanCertificate(r.db.QueryRow(ctx, certificateSelect+` WHERE code = $1`, code))
+}
+
+// Valid returns the certificate of the student's course that has not been
+// revoked, as long as the enrollment is still completed.
+func (r *CertificateRepo) Valid(ctx context.Context, courseID, studentID int) (model.Certificate, error) {
+	return scanCertificate(r.db.QueryRow(ctx,
+		certificateSelect+` WHERE course_id = $1 AND student_id = $2 AND revoked_at IS NULL
+		   AND EXISTS (SELECT 1 FROM enrollments e WHERE e.id = certificates.enrollment_id AND e.status = 'completed')`,
+		courseID, studentID,
+	))
+}
+
+func (r *CertificateRepo) ListByStudent(ctx context.Context, studentID int) ([]model.Certificate, error) {
+	rows, err := r.db.Query(ctx, certificateSelect+` WHERE student_id = $1 ORDER BY issued_at DESC, id DESC`, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.Certificate, 0)
+	for rows.Next() {
+		c, err := scanCertificate(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// Completion loads what goes on a certificate for a completed enrollment.
+// AttendanceRate is left for the caller.
+func (r *CertificateRepo) Completion(ctx context.Context, courseID, studentID int) (model.Certificate, error) {
+	c := model.Certificate{CourseID: courseID, StudentID: studentID}
+	err := r.db.QueryRow(ctx,
+		`SELECT e.id, u.full_name, co.title, COALESCE(t.full_name,''), co.term, co.credits::float8,
+		        e.grade::float8, e.status_changed_at
+		 FROM enrollments e
+		 JOIN users u ON u.id = e.student_id
+		 JOIN courses co ON co.id = e.course_id
+		 LEFT JOIN users t ON t.id = co.teacher_id
+		 WHERE e.course_id = $1 AND e.student_id = $2 AND e.status = 'completed' AND e.grade IS NOT NULL`,
+		courseID, studentID,
+	).Scan(&c.EnrollmentID, &c.StudentName, &c.CourseTitle, &c.TeacherName, &c.Term, &c.Credits,
+		&c.Grade, &c.CompletedAt)
+	return c, err
+}
+
+func (r *CertificateRepo) Create(ctx context.Context, c model.Certificate) (int, error) {
+	var id int
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO certificates(code, enrollment_id, student_id, course_id, student_name, course_title, teacher_name,
+		                          term, credits, grade, attendance_rate, completed_at, issued_at, signature)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14)
+		 RETURNING id`,
+		c.Code, c.EnrollmentID, c.StudentID, c.CourseID, c.StudentName, c.CourseTitle, c.TeacherName,
+		c.Term, c.Credits, c.Grade, c.AttendanceRate, c.CompletedAt, c.IssuedAt, c.Signature,
+	).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "uq_certificates_valid" {
+			return 0, ErrCertificateExists
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *CertificateRepo) Revoke(ctx context.Context, code, reason string) error {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE certificates SET revoked_at = now(), revoke_reason = $2
+		 WHERE code = $1 AND revoked_at IS NULL`,
+		code, reason,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CheckinPolicyRepo stores classrooms and per-course check-in rules.
+type CheckinPolicyRepo struct{ db *pgxpool.Pool }
+
+func NewCheckinPolicyRepo(db *pgxpool.Pool) *CheckinPolicyRepo { return &CheckinPolicyRepo{db: db} }
+
+func (r *CheckinPolicyRepo) CreateRoom(ctx context.Context, room model.Room) (int, error) {
+	var id int
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO rooms(name, lat, lon, radius_m) VALUES ($1,$2,$3,$4) RETURNING id`,
+		room.Name, room.Lat, room.Lon, room.RadiusM,
+	).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return 0, apperr.Conflict("room_exists", "room name already exists")
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *CheckinPolicyRepo) ListRooms(ctx context.Context) ([]model.Room, error) {
+	rows, err := r.db.Query(ctx, `SELECT id, name, lat, lon, radius_m FROM rooms ORDER BY name ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.Room, 0)
+	for rows.Next() {
+		var x model.Room
+		if err := rows.Scan(&x.ID, &x.Name, &x.Lat, &x.Lon, &x.RadiusM); err != nil {
+			return nil, err
+		}
+		out = append(out, x)
+	}
+	return out, rows.Err()
+}
+
+// Get returns the course's policy, or the zero policy if none was saved.
+func (r *CheckinPolicyRepo) Get(ctx context.Context, courseID int) (model.CheckinPolicy, error) {
+	p := model.CheckinPolicy{CourseID: courseID, AllowedNetworks: []string{}}
+	var (
+		roomID   *int
+		name     *string
+		lat, lon *float64
+		radius   *int
+	)
+	err := r.db.QueryRow(ctx,
+		`SELECT p.room_id, p.require_location, p.require_device, p.allowed_networks,
+		        r.name, r.lat, r.lon, r.radius_m
+		 FROM checkin_policies p
+		 LEFT JOIN rooms r ON r.id = p.room_id
+		 WHERE p.course_id = $1`,
+		courseID,
+	).Scan(&roomID, &p.RequireLocation, &p.RequireDevice, &p.AllowedNetworks, &name, &lat, &lon, &radius)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return p, nil
+	}
+	if err != nil {
+		return p, err
+	}
+	if roomID != nil {
+		p.RoomID = *roomID
+		p.Room = &model.Room{ID: *roomID, Name: *name, Lat: *lat, Lon: *lon, RadiusM: *radius}
+	}
+	return p, nil
+}
+
+func (r *CheckinPolicyRepo) Save(ctx context.Context, p model.CheckinPolicy) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO checkin_policies(course_id, room_id, require_location, require_device, allowed_networks)
+		 VALUES ($1, NULLIF($2,0), $3, $4, $5)
+		 ON CONFLICT (course_id) DO UPDATE SET
+		   room_id = EXCLUDED.room_id,
+		   require_location = EXCLUDED.require_location,
+		   require_device = EXCLUDED.require_device,
+		   allowed_networks = EXCLUDED.allowed_networks,
+		   updated_at = now()`,
+		p.CourseID, p.RoomID, p.RequireLocation, p.RequireDevice, p.AllowedNetworks,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return apperr.NotFound("room_or_course_not_found", "room or course not found")
+	}
+	return err
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CheckinRepo struct{ db *pgxpool.Pool }
+
+func NewCheckinRepo(db *pgxpool.Pool) *CheckinRepo { return &CheckinRepo{db: db} }
+
+const checkinSessionColumns = `id, course_id, lesson_date, secret, COALESCE(opened_by,0), opened_at, late_after, closes_at, closed_at`
+
+func scanCheckinSession(row pgx.Row) (model.CheckinSession, error) {
+	var s model.CheckinSession
+	err := row.Scan(&s.ID, &s.CourseID, &s.LessonDate, &s.Secret, &s.OpenedBy, &s.OpenedAt, &s.LateAfter, &s.ClosesAt, &s.ClosedAt)
+	return s, err
+}
+
+func (r *CheckinRepo) Open(ctx context.Context, s model.CheckinSession) (int, error) {
+	var id int
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO checkin_sessions(course_id, lesson_date, secret, opened_by, late_after, closes_at)
+		 VALUES ($1,$2,$3,NULLIF($4,0),$5,$6) RETURNING id`,
+		s.CourseID, s.LessonDate, s.Secret, s.OpenedBy, s.LateAfter, s.ClosesAt,
+	).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return 0, apperr.Conflict("checkin_open", "a check-in window is already open for this course")
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetOpen returns the course's window that has not been closed yet. It may
+// already be past closes_at if the auto-close loop has not run.
+func (r *CheckinRepo) GetOpen(ctx context.Context, courseID int) (model.CheckinSession, error) {
+	s, err := scanCheckinSession(r.db.QueryRow(ctx,
+		`SELECT `+checkinSessionColumns+` FROM checkin_sessions WHERE course_id=$1 AND closed_at IS NULL`,
+		courseID,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return s, apperr.NotFound("checkin_not_open", "no open check-in window for this course")
+	}
+	return s, err
+}
+
+func (r *CheckinRepo) CountCheckins(ctx context.Context, sessionID int) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM checkins WHERE session_id=$1`, sessionID).Scan(&n)
+	return n, err
+}
+
+func (r *CheckinRepo) Failures(ctx context.Context, sessionID, studentID int) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx,
+		`SELECT COALESCE((SELECT attempts FROM checkin_failures WHERE session_id=$1 AND student_id=$2), 0)`,
+		sessionID, studentID,
+	).Scan(&n)
+	return n, err
+}
+
+// RecordFailure counts a wrong code and returns the attempts so far.
+func (r *CheckinRepo) RecordFailure(ctx context.Context, sessionID, studentID int) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO checkin_failures(session_id, student_id, attempts) VALUES ($1,$2,1)
+		 ON CONFLICT (session_id, student_id) DO UPDATE SET attempts = checkin_failures.attempts + 1
+		 RETURNING attempts`,
+		sessionID, studentID,
+	).Scan(&n)
+	return n, err
+}
+
+// CheckIn records the check-in and the attendance mark together. A student
+// can check in only once per window, and not at all once the lesson has a
+// mark.
+func (r *CheckinRepo) CheckIn(ctx context.Context, s model.CheckinSession, c model.Checkin) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO checkins(session_id, student_id, code_step, status, device_hash, ip, lat, lon)
+		 VALUES ($1,$2,$3,$4,NULLIF($5,''),NULLIF($6,''),$7,$8)`,
+		c.SessionID, c.StudentID, c.CodeStep, c.Status, c.DeviceHash, c.IP, c.Lat, c.Lon,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return apperr.Conflict("already_checked_in", "already checked in")
+		}
+		return err
+	}
+
+	// only a new mark: a check-in never replaces one set by staff
+	var (
+		saved   *int
+		changed bool
+	)
+	if err := tx.QueryRow(ctx, upsertAttendanceSQL,
+		s.CourseID, c.StudentID, s.LessonDate, c.Status, "self check-in", c.StudentID, NewOnly,
+	).Scan(&saved, &changed); err != nil {
+		return err
+	}
+	if saved == nil {
+		return apperr.Conflict("already_marked", "attendance for this lesson is already recorded, ask the teacher to change it")
+	}
+	return tx.Commit(ctx)
+}
+
+// Close ends a window and marks every enrolled student who has no mark for
+// the lesson yet as absent (excused if an approved excuse covers the date).
+// Marks set by the teacher in the meantime are left alone. It returns the
+// number of students marked.
+func (r *CheckinRepo) Close(ctx context.Context, sessionID, closedBy int) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	s, err := scanCheckinSession(tx.QueryRow(ctx,
+		`UPDATE checkin_sessions SET closed_at = now()
+		 WHERE id = $1 AND closed_at IS NULL
+		 RETURNING `+checkinSessionColumns,
+		sessionID,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, apperr.Conflict("checkin_closed", "check-in window is already closed")
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := tx.Exec(ctx,
+		`WITH ins AS (
+			INSERT INTO attendance(course_id, student_id, lesson_date, status, note)
+			SELECT e.course_id, e.student_id, $2::date,
+			       CASE WHEN EXISTS (
+			           SELECT 1 FROM attendance_excuses x
+			           WHERE x.student_id = e.student_id AND x.status = 'approved'
+			             AND (x.course_id IS NULL OR x.course_id = e.course_id)
+			             AND $2::date BETWEEN x.date_from AND x.date_to
+			       ) THEN 'excused' ELSE 'absent' END,
+			       'no check-in'
+			FROM enrollments e
+			WHERE e.course_id = $1 AND e.status = 'active'
+			ON CONFLICT (course_id, student_id, lesson_date) DO NOTHING
+			RETURNING id, status, note
+		)
+		INSERT INTO attendance_history(attendance_id, new_status, new_note, changed_by)
+		SELECT id, status, note, NULLIF($3, 0) FROM ins`,
+		s.CourseID, s.LessonDate, closedBy,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), tx.Commit(ctx)
+}
+
+// ListExpired returns windows past closes_at that are still open.
+func (r *CheckinRepo) ListExpired(ctx context.Context) ([]int, error) {
+	rows, err := r.db.Query(ctx, `SELECT id FROM checkin_sessions WHERE closed_at IS NULL AND closes_at <= now()`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+// SharedDevices lists devices that checked in several students during one
+// window of the course, newest lessons first.
+func (r *CheckinRepo) SharedDevices(ctx context.Context, courseID int) ([]model.SharedDevice, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT c.session_id, s.lesson_date, c.device_hash, array_agg(c.student_id ORDER BY c.student_id)
+		 FROM checkins c
+		 JOIN checkin_sessions s ON s.id = c.session_id
+		 WHERE s.course_id = $1 AND c.device_hash IS NOT NULL
+		 GROUP BY c.session_id, s.lesson_date, c.device_hash
+		 HAVING COUNT(*) > 1
+		 ORDER BY s.lesson_date DESC, c.session_id DESC
+		 LIMIT 200`,
+		courseID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.SharedDevice, 0)
+	for rows.Next() {
+		var d model.SharedDevice
+		if err := rows.Scan(&d.SessionID, &d.LessonDate, &d.DeviceHash, &d.StudentIDs); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
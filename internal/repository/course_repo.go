@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"lms-backend/internal/domain/model"
+	"lms-backend/internal/pagination"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -15,44 +16,126 @@ func NewCourseRepo(db *pgxpool.Pool) *CourseRepo { return &CourseRepo{db: db} }
 func (r *CourseRepo) Create(ctx context.Context, c model.Course) (int, error) {
 	var id int
 	err := r.db.QueryRow(ctx,
-		`INSERT INTO courses(title, teacher_id) VALUES ($1,$2) RETURNING id`,
-		c.Title, c.TeacherID,
+		`INSERT INTO courses(title, teacher_id, term, credits) VALUES ($1,$2,$3,$4) RETURNING id`,
+		c.Title, c.TeacherID, c.Term, c.Credits,
 	).Scan(&id)
 	return id, err
 }
 
-func (r *CourseRepo) List(ctx context.Context) ([]model.Course, error) {
-	rows, err := r.db.Query(ctx, `SELECT id, title, teacher_id, created_at FROM courses ORDER BY id DESC LIMIT 200`)
+func (r *CourseRepo) GetByID(ctx context.Context, id int) (model.Course, error) {
+	var c model.Course
+	err := r.db.QueryRow(ctx,
+		`SELECT id, title, teacher_id, term, credits::float8, created_at FROM courses WHERE id=$1`,
+		id,
+	).Scan(&c.ID, &c.Title, &c.TeacherID, &c.Term, &c.Credits, &c.CreatedAt)
+	return c, err
+}
+
+// ExistingIDs reports which of ids are courses.
+func (r *CourseRepo) ExistingIDs(ctx context.Context, ids []int) (map[int]bool, error) {
+	rows, err := r.db.Query(ctx, `SELECT id FROM courses WHERE id = ANY($1)`, ids)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	out := make([]model.Course, 0)
+	out := map[int]bool{}
 	for rows.Next() {
-		var c model.Course
-		if err := rows.Scan(&c.ID, &c.Title, &c.TeacherID, &c.CreatedAt); err != nil {
+		var id int
+		if err := rows.Scan(&id); err != nil {
 			return nil, err
 		}
-		out = append(out, c)
+		out[id] = true
 	}
 	return out, rows.Err()
 }
 
-func (r *CourseRepo) ListByTeacher(ctx context.Context, teacherID int) ([]model.Course, error) {
-	rows, err := r.db.Query(ctx, `SELECT id, title, teacher_id, created_at FROM courses WHERE teacher_id=$1 ORDER BY id DESC LIMIT 200`, teacherID)
+// ListByIDs returns the courses among ids.
+func (r *CourseRepo) ListByIDs(ctx context.Context, ids []int) ([]model.Course, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT id, title, teacher_id, term, credits::float8, created_at FROM courses WHERE id = ANY($1)`,
+		ids,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	out := make([]model.Course, 0)
+	out := make([]model.Course, 0, len(ids))
 	for rows.Next() {
 		var c model.Course
-		if err := rows.Scan(&c.ID, &c.Title, &c.TeacherID, &c.CreatedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.Title, &c.TeacherID, &c.Term, &c.Credits, &c.CreatedAt); err != nil {
 			return nil, err
 		}
 		out = append(out, c)
 	}
 	return out, rows.Err()
 }
+
+// CourseListSpec is what course lists accept.
+var CourseListSpec = &pagination.Spec{
+	ID: "c.id",
+	Sorts: map[string]pagination.Field{
+		"id":         {Column: "c.id", Type: "int"},
+		"title":      {Column: "c.title", Type: "text"},
+		"term":       {Column: "c.term", Type: "text"},
+		"created_at": {Column: "c.created_at", Type: "timestamp"},
+	},
+	Default: "-id",
+	Filters: map[string]pagination.Filter{
+		"q":              {Cond: `c.title ILIKE $?`, Like: true},
+		"teacher_id":     {Cond: `c.teacher_id = $?`, Kind: pagination.Int},
+		"term":           {Cond: `c.term = $?`},
+		"created_after":  {Cond: `c.created_at >= $?`, Kind: pagination.Time},
+		"created_before": {Cond: `c.created_at < $?`, Kind: pagination.Time},
+	},
+}
+
+// List returns one page of all courses and the cursor of the next page.
+func (r *CourseRepo) List(ctx context.Context, req pagination.Request) ([]model.Course, string, error) {
+	return listCourses(ctx, r.db, req, `FROM courses c WHERE TRUE`)
+}
+
+// ListByTeacher is List narrowed to the teacher's own courses.
+func (r *CourseRepo) ListByTeacher(ctx context.Context, teacherID int, req pagination.Request) ([]model.Course, string, error) {
+	return listCourses(ctx, r.db, req, `FROM courses c WHERE c.teacher_id = $1`, teacherID)
+}
+
+// listCourses pages through courses aliased c; from is the FROM and WHERE
+// part of the query with its own parameters in args.
+func listCourses(ctx context.Context, db *pgxpool.Pool, req pagination.Request, from string, args ...any) ([]model.Course, string, error) {
+	q, err := CourseListSpec.Query(req)
+	if err != nil {
+		return nil, "", err
+	}
+	cond, args := q.Where(args)
+	rows, err := db.Query(ctx,
+		`SELECT c.id, c.title, c.teacher_id, c.term, c.credits::float8, c.created_at`+q.KeyColumn()+`
+		 `+from+cond+q.OrderLimit(),
+		args...,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	out := make([]model.Course, 0)
+	keys := make([]pagination.Key, 0)
+	for rows.Next() {
+		var (
+			c model.Course
+			k pagination.Key
+		)
+		if err := rows.Scan(&c.ID, &c.Title, &c.TeacherID, &c.Term, &c.Credits, &c.CreatedAt, &k.Value); err != nil {
+			return nil, "", err
+		}
+		k.ID = c.ID
+		out = append(out, c)
+		keys = append(keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+	out, next := pagination.Trim(q, out, keys)
+	return out, next, nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DeviceRepo struct{ db *pgxpool.Pool }
+
+func NewDeviceRepo(db *pgxpool.Pool) *DeviceRepo { return &DeviceRepo{db: db} }
+
+// Register binds a device to a student who has none yet.
+func (r *DeviceRepo) Register(ctx context.Context, d model.StudentDevice) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO student_devices(student_id, fingerprint_hash, label) VALUES ($1,$2,NULLIF($3,''))`,
+		d.StudentID, d.FingerprintHash, d.Label,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		if pgErr.ConstraintName == "student_devices_pkey" {
+			return apperr.Conflict("device_registered", "a device is already registered, ask an admin to reset it")
+		}
+		return apperr.Conflict("device_taken", "this device is registered to another student")
+	}
+	return err
+}
+
+// Get returns the student's device; ok is false if none is registered.
+func (r *DeviceRepo) Get(ctx context.Context, studentID int) (d model.StudentDevice, ok bool, err error) {
+	err = r.db.QueryRow(ctx,
+		`SELECT student_id, fingerprint_hash, COALESCE(label,''), registered_at FROM student_devices WHERE student_id=$1`,
+		studentID,
+	).Scan(&d.StudentID, &d.FingerprintHash, &d.Label, &d.RegisteredAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return d, false, nil
+	}
+	return d, err == nil, err
+}
+
+func (r *DeviceRepo) Delete(ctx context.Context, studentID int) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM student_devices WHERE student_id=$1`, studentID)
+	return err
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 
 	"lms-backend/internal/domain/model"
+	"lms-backend/internal/pagination"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -12,72 +14,210 @@ type EnrollmentRepo struct{ db *pgxpool.Pool }
 
 func NewEnrollmentRepo(db *pgxpool.Pool) *EnrollmentRepo { return &EnrollmentRepo{db: db} }
 
-func (r *EnrollmentRepo) Enroll(ctx context.Context, courseID int, studentID int) error {
-	_, err := r.db.Exec(ctx,
-		`INSERT INTO enrollments(course_id, student_id)
-		 VALUES ($1,$2) ON CONFLICT (course_id, student_id) DO NOTHING`,
-		courseID, studentID,
+// reactivateOnConflict turns an enrollment insert into a re-enrollment when
+// the student dropped or was withdrawn before. Active rows stay untouched and
+// so do completed and failed ones: a result is final. The reason and the
+// acting user come from the inserted row, and the status trigger records the
+// change in enrollment_history.
+const reactivateOnConflict = `
+	ON CONFLICT (course_id, student_id) DO UPDATE
+	SET status = 'active', status_reason = EXCLUDED.status_reason, status_changed_by = EXCLUDED.status_changed_by,
+	    status_changed_at = now(), grade = NULL, group_id = EXCLUDED.group_id, enrolled_at = now()
+	WHERE enrollments.status IN ('dropped','withdrawn')`
+
+// enrollOneSQL enrolls or re-enrolls the student $2 in course $1 on behalf
+// of $3 (0 = nobody) and returns the status of the row afterwards; an
+// existing row the insert left alone is returned as it was.
+const enrollOneSQL = `
+WITH up AS (
+	INSERT INTO enrollments(course_id, student_id, status_changed_by)
+	VALUES ($1, $2, NULLIF($3,0))` + reactivateOnConflict + `
+	RETURNING status
+)
+SELECT status FROM up
+UNION ALL
+SELECT status FROM enrollments WHERE course_id = $1 AND student_id = $2 AND NOT EXISTS (SELECT 1 FROM up)`
+
+// enrollOne scans the result of enrollOneSQL; a student who completed or
+// failed the course is refused.
+func enrollOne(row pgx.Row) error {
+	var status string
+	if err := row.Scan(&status); err != nil {
+		return err
+	}
+	if status == "completed" || status == "failed" {
+		return ErrEnrollmentFinished
+	}
+	return nil
+}
+
+// Enroll adds the student to the course, or brings back one who dropped or
+// was withdrawn; by is the acting user.
+func (r *EnrollmentRepo) Enroll(ctx context.Context, courseID, studentID, by int) error {
+	return enrollOne(r.db.QueryRow(ctx, enrollOneSQL, courseID, studentID, by))
+}
+
+// ListCoursesByStudent returns one page of the student's courses in the
+// given enrollment status ("" = active).
+func (r *EnrollmentRepo) ListCoursesByStudent(ctx context.Context, studentID int, status string, req pagination.Request) ([]model.Course, string, error) {
+	if status == "" {
+		status = "active"
+	}
+	return listCourses(ctx, r.db, req,
+		`FROM enrollments e
+		 JOIN courses c ON c.id = e.course_id
+		 WHERE e.student_id = $1 AND e.status = $2`,
+		studentID, status,
+	)
+}
+
+// SetStatus moves an enrollment to another status; from lists the statuses
+// it may currently be in. A changed status or grade revokes a certificate
+// issued for the enrollment (a trigger does that for every writer). Leaving
+// "active" frees a seat, which goes to the course's waitlist. It returns the
+// promoted students.
+func (r *EnrollmentRepo) SetStatus(ctx context.Context, courseID, studentID int, ch model.EnrollmentChange, from []string) ([]int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockCourse(ctx, tx, courseID); err != nil {
+		return nil, err
+	}
+	tag, err := tx.Exec(ctx,
+		`UPDATE enrollments
+		 SET status = $3, status_reason = $4, status_changed_by = NULLIF($5,0), status_changed_at = now(),
+		     grade = CASE WHEN $3 IN ('completed','failed') THEN $6 ELSE grade END
+		 WHERE course_id = $1 AND student_id = $2 AND status = ANY($7)`,
+		courseID, studentID, ch.Status, ch.Reason, ch.ChangedBy, ch.Grade, from,
 	)
-	return err
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	promoted, err := promoteWaitlist(ctx, tx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return promoted, tx.Commit(ctx)
 }
 
-func (r *EnrollmentRepo) ListCoursesByStudent(ctx context.Context, studentID int) ([]model.Course, error) {
+// PromoteWaitlist fills free seats of the course from its waitlist, e.g.
+// after a group left it.
+func (r *EnrollmentRepo) PromoteWaitlist(ctx context.Context, courseID int) ([]int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockCourse(ctx, tx, courseID); err != nil {
+		return nil, err
+	}
+	promoted, err := promoteWaitlist(ctx, tx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return promoted, tx.Commit(ctx)
+}
+
+// RosterSpec is what GET /courses/:id/students accepts.
+var RosterSpec = &pagination.Spec{
+	ID: "u.id",
+	Sorts: map[string]pagination.Field{
+		"id":          {Column: "u.id", Type: "int"},
+		"full_name":   {Column: "u.full_name", Type: "text"},
+		"email":       {Column: "u.email", Type: "text"},
+		"enrolled_at": {Column: "e.enrolled_at", Type: "timestamp"},
+	},
+	Default: "-id",
+	Filters: userFilters,
+}
+
+// ListEnrolledStudents returns one page of the students of a course in the
+// given enrollment status ("" = active, the roster).
+func (r *EnrollmentRepo) ListEnrolledStudents(ctx context.Context, courseID int, status string, req pagination.Request) ([]model.User, string, error) {
+	if status == "" {
+		status = "active"
+	}
+	q, err := RosterSpec.Query(req)
+	if err != nil {
+		return nil, "", err
+	}
+	cond, args := q.Where([]any{courseID, status})
 	rows, err := r.db.Query(ctx,
-		`SELECT c.id, c.title, c.teacher_id, c.created_at
+		`SELECT u.id, u.full_name, u.email`+q.KeyColumn()+`
 		 FROM enrollments e
-		 JOIN courses c ON c.id = e.course_id
-		 WHERE e.student_id = $1
-		 ORDER BY c.id DESC
-		 LIMIT 200`,
-		studentID,
+		 JOIN users u ON u.id = e.student_id
+		 LEFT JOIN user_profiles p ON p.user_id = u.id
+		 WHERE e.course_id = $1 AND e.status = $2`+cond+q.OrderLimit(),
+		args...,
 	)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer rows.Close()
 
-	out := make([]model.Course, 0)
+	out := make([]model.User, 0)
+	keys := make([]pagination.Key, 0)
 	for rows.Next() {
-		var c model.Course
-		if err := rows.Scan(&c.ID, &c.Title, &c.TeacherID, &c.CreatedAt); err != nil {
-			return nil, err
+		var (
+			u model.User
+			k pagination.Key
+		)
+		if err := rows.Scan(&u.ID, &u.FullName, &u.Email, &k.Value); err != nil {
+			return nil, "", err
 		}
-		out = append(out, c)
+		k.ID = u.ID
+		out = append(out, u)
+		keys = append(keys, k)
 	}
-	return out, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+	out, next := pagination.Trim(q, out, keys)
+	return out, next, nil
 }
 
+// ListByCourses returns the enrollments in the given status ("" = active) of
+// all courses in courseIDs, ordered by student name.
+func (r *EnrollmentRepo) ListByCourses(ctx context.Context, courseIDs []int, status string) ([]model.Enrollment, error) {
+	return r.listEnrollments(ctx, `e.course_id = ANY($1)`, courseIDs, status)
+}
 
-func (r *EnrollmentRepo) Unenroll(ctx context.Context, courseID int, studentID int) error {
-	_, err := r.db.Exec(ctx,
-		`DELETE FROM enrollments WHERE course_id = $1 AND student_id = $2`,
-		courseID, studentID,
-	)
-	return err
+// ListByStudents is ListByCourses for the enrollments of students.
+func (r *EnrollmentRepo) ListByStudents(ctx context.Context, studentIDs []int, status string) ([]model.Enrollment, error) {
+	return r.listEnrollments(ctx, `e.student_id = ANY($1)`, studentIDs, status)
 }
 
-func (r *EnrollmentRepo) ListEnrolledStudents(ctx context.Context, courseID int) ([]model.User, error) {
+func (r *EnrollmentRepo) listEnrollments(ctx context.Context, where string, ids []int, status string) ([]model.Enrollment, error) {
+	if status == "" {
+		status = "active"
+	}
 	rows, err := r.db.Query(ctx,
-		`SELECT u.id, u.full_name, u.email
+		`SELECT e.course_id, e.student_id, e.status, e.grade::float8, e.enrolled_at
 		 FROM enrollments e
 		 JOIN users u ON u.id = e.student_id
-		 WHERE e.course_id = $1
-		 ORDER BY u.id DESC
-		 LIMIT 200`,
-		courseID,
+		 WHERE `+where+` AND e.status = $2
+		 ORDER BY u.full_name, e.student_id, e.course_id`,
+		ids, status,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	out := make([]model.User, 0)
+	out := make([]model.Enrollment, 0)
 	for rows.Next() {
-		var u model.User
-		if err := rows.Scan(&u.ID, &u.FullName, &u.Email); err != nil {
+		var e model.Enrollment
+		if err := rows.Scan(&e.CourseID, &e.StudentID, &e.Status, &e.Grade, &e.EnrolledAt); err != nil {
 			return nil, err
 		}
-		out = append(out, u)
+		out = append(out, e)
 	}
 	return out, rows.Err()
 }
@@ -85,19 +225,16 @@ func (r *EnrollmentRepo) ListEnrolledStudents(ctx context.Context, courseID int)
 func (r *EnrollmentRepo) IsEnrolled(ctx context.Context, courseID int, studentID int) (bool, error) {
 	var exists bool
 	err := r.db.QueryRow(ctx,
-		`SELECT EXISTS(SELECT 1 FROM enrollments WHERE course_id = $1 AND student_id = $2)`,
+		`SELECT EXISTS(SELECT 1 FROM enrollments WHERE course_id = $1 AND student_id = $2 AND status = 'active')`,
 		courseID, studentID,
 	).Scan(&exists)
 	return exists, err
 }
 
-func (r *EnrollmentRepo) ListAvailableStudents(ctx context.Context, courseID int) ([]model.User, error) {
+// EnrolledStudentIDs returns the IDs of every active student in the course, without a limit.
+func (r *EnrollmentRepo) EnrolledStudentIDs(ctx context.Context, courseID int) ([]int, error) {
 	rows, err := r.db.Query(ctx,
-		`SELECT id, full_name, email FROM users
-		 WHERE role_id = (SELECT id FROM roles WHERE name = 'student')
-		   AND id NOT IN (SELECT student_id FROM enrollments WHERE course_id = $1)
-		 ORDER BY id DESC
-		 LIMIT 200`,
+		`SELECT student_id FROM enrollments WHERE course_id = $1 AND status = 'active' ORDER BY student_id ASC`,
 		courseID,
 	)
 	if err != nil {
@@ -105,13 +242,137 @@ func (r *EnrollmentRepo) ListAvailableStudents(ctx context.Context, courseID int
 	}
 	defer rows.Close()
 
-	out := make([]model.User, 0)
+	out := make([]int, 0)
 	for rows.Next() {
-		var u model.User
-		if err := rows.Scan(&u.ID, &u.FullName, &u.Email); err != nil {
+		var id int
+		if err := rows.Scan(&id); err != nil {
 			return nil, err
 		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+// AvailableStudentsSpec is what GET /courses/:id/available-students accepts.
+var AvailableStudentsSpec = &pagination.Spec{
+	ID: "u.id",
+	Sorts: map[string]pagination.Field{
+		"id":        {Column: "u.id", Type: "int"},
+		"full_name": {Column: "u.full_name", Type: "text"},
+		"email":     {Column: "u.email", Type: "text"},
+	},
+	Default: "-id",
+	Filters: userFilters,
+}
+
+// ListAvailableStudents returns one page of the active students who are not
+// enrolled in the course and the cursor of the next page.
+func (r *EnrollmentRepo) ListAvailableStudents(ctx context.Context, courseID int, req pagination.Request) ([]model.User, string, error) {
+	q, err := AvailableStudentsSpec.Query(req)
+	if err != nil {
+		return nil, "", err
+	}
+	cond, args := q.Where([]any{courseID})
+	rows, err := r.db.Query(ctx,
+		`SELECT u.id, u.full_name, u.email`+q.KeyColumn()+`
+		 FROM users u
+		 LEFT JOIN user_profiles p ON p.user_id = u.id
+		 WHERE u.role_id = (SELECT id FROM roles WHERE name = 'student') AND u.active AND u.deleted_at IS NULL
+		   AND u.id NOT IN (SELECT student_id FROM enrollments WHERE course_id = $1 AND status = 'active')`+cond+q.OrderLimit(),
+		args...,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	out := make([]model.User, 0)
+	keys := make([]pagination.Key, 0)
+	for rows.Next() {
+		var (
+			u model.User
+			k pagination.Key
+		)
+		if err := rows.Scan(&u.ID, &u.FullName, &u.Email, &k.Value); err != nil {
+			return nil, "", err
+		}
+		k.ID = u.ID
 		out = append(out, u)
+		keys = append(keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+	out, next := pagination.Trim(q, out, keys)
+	return out, next, nil
+}
+// Transcript returns every course the student was ever enrolled in, newest
+// first, each with its status history.
+func (r *EnrollmentRepo) Transcript(ctx context.Context, studentID int) ([]model.TranscriptEntry, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT e.id, c.id, c.title, COALESCE(t.full_name,''), c.term, c.credits::float8, e.status, e.grade::float8,
+		        e.enrolled_at, e.status_changed_at, e.status_reason, COALESCE(g.name,'')
+		 FROM enrollments e
+		 JOIN courses c ON c.id = e.course_id
+		 LEFT JOIN users t ON t.id = c.teacher_id
+		 LEFT JOIN student_groups g ON g.id = e.group_id
+		 WHERE e.student_id = $1
+		 ORDER BY e.enrolled_at DESC, e.id DESC`,
+		studentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]model.TranscriptEntry, 0)
+	index := map[int]int{}
+	ids := make([]int, 0)
+	for rows.Next() {
+		var (
+			id int
+			x  model.TranscriptEntry
+		)
+		if err := rows.Scan(&id, &x.CourseID, &x.CourseTitle, &x.TeacherName, &x.Term, &x.Credits, &x.Status, &x.Grade,
+			&x.EnrolledAt, &x.StatusChangedAt, &x.Reason, &x.GroupName); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		x.History = make([]model.EnrollmentEvent, 0)
+		index[id] = len(out)
+		ids = append(ids, id)
+		out = append(out, x)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	rows, err = r.db.Query(ctx,
+		`SELECT h.enrollment_id, COALESCE(h.old_status,''), h.status, h.reason,
+		        COALESCE(h.changed_by,0), COALESCE(u.full_name,''), h.changed_at
+		 FROM enrollment_history h
+		 LEFT JOIN users u ON u.id = h.changed_by
+		 WHERE h.enrollment_id = ANY($1)
+		 ORDER BY h.changed_at ASC, h.id ASC`,
+		ids,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id int
+			ev model.EnrollmentEvent
+		)
+		if err := rows.Scan(&id, &ev.OldStatus, &ev.Status, &ev.Reason, &ev.ChangedBy, &ev.ChangedByName, &ev.ChangedAt); err != nil {
+			return nil, err
+		}
+		x := &out[index[id]]
+		x.History = append(x.History, ev)
 	}
 	return out, rows.Err()
-}
\ No newline at end of file
+}
@@ -0,0 +1,439 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EnrollmentRequestRepo stores enrollment policies and the requests and
+// waitlists of student self-service enrollment.
+type EnrollmentRequestRepo struct{ db *pgxpool.Pool }
+
+func NewEnrollmentRequestRepo(db *pgxpool.Pool) *EnrollmentRequestRepo {
+	return &EnrollmentRequestRepo{db: db}
+}
+
+var (
+	ErrAlreadyEnrolled    = apperr.Conflict("already_enrolled", "already enrolled in this course")
+	ErrRequestExists      = apperr.Conflict("request_exists", "you already have an open request for this course")
+	ErrGroupEnrollment    = apperr.Forbidden("group_enrollment", "enrolled through your group; ask the registrar to change it")
+	ErrEnrollmentFinished = apperr.Conflict("enrollment_finished", "the course was already completed or failed")
+)
+
+// GetPolicy returns the course's policy with seat counts; courses without
+// a saved policy are closed.
+func (r *EnrollmentRequestRepo) GetPolicy(ctx context.Context, courseID int) (model.EnrollmentPolicy, error) {
+	p := model.EnrollmentPolicy{CourseID: courseID, Policy: "closed"}
+	err := r.db.QueryRow(ctx,
+		`SELECT COALESCE(p.policy,'closed'), COALESCE(p.key_hash,''), COALESCE(p.capacity,0), p.opens_at, p.closes_at,
+		        (SELECT count(*) FROM enrollments e WHERE e.course_id = $1 AND e.status = 'active'),
+		        (SELECT count(*) FROM enrollment_requests q WHERE q.course_id = $1 AND q.status = 'waitlisted')
+		 FROM (SELECT $1::int AS course_id) c
+		 LEFT JOIN enrollment_policies p ON p.course_id = c.course_id`,
+		courseID,
+	).Scan(&p.Policy, &p.KeyHash, &p.Capacity, &p.OpensAt, &p.ClosesAt, &p.Enrolled, &p.Waitlisted)
+	return p, err
+}
+
+// SavePolicy stores the policy (keeping the old key when p.KeyHash is empty)
+// and, if seats were added or the capacity removed, promotes students from
+// the waitlist.
+func (r *EnrollmentRequestRepo) SavePolicy(ctx context.Context, p model.EnrollmentPolicy) ([]int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockCourse(ctx, tx, p.CourseID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO enrollment_policies(course_id, policy, key_hash, capacity, opens_at, closes_at)
+		 VALUES ($1, $2, NULLIF($3,''), NULLIF($4::int,0), $5, $6)
+		 ON CONFLICT (course_id) DO UPDATE SET
+		   policy     = EXCLUDED.policy,
+		   key_hash   = CASE WHEN EXCLUDED.policy <> 'key' THEN NULL
+		                     ELSE COALESCE(EXCLUDED.key_hash, enrollment_policies.key_hash) END,
+		   capacity   = EXCLUDED.capacity,
+		   opens_at   = EXCLUDED.opens_at,
+		   closes_at  = EXCLUDED.closes_at,
+		   updated_at = now()`,
+		p.CourseID, p.Policy, p.KeyHash, p.Capacity, p.OpensAt, p.ClosesAt,
+	); err != nil {
+		return nil, err
+	}
+	promoted, err := promoteWaitlist(ctx, tx, p.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	return promoted, tx.Commit(ctx)
+}
+
+// Submit files a student's request. With needsApproval it waits as pending;
+// otherwise the student is enrolled at once, or waitlisted if the course is
+// full. The returned request carries the outcome.
+func (r *EnrollmentRequestRepo) Submit(ctx context.Context, courseID, studentID int, needsApproval bool) (model.EnrollmentRequest, error) {
+	req := model.EnrollmentRequest{CourseID: courseID, StudentID: studentID}
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return req, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockCourse(ctx, tx, courseID); err != nil {
+		return req, err
+	}
+	var status string
+	err = tx.QueryRow(ctx,
+		`SELECT status FROM enrollments WHERE course_id=$1 AND student_id=$2`,
+		courseID, studentID,
+	).Scan(&status)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return req, err
+	}
+	switch status {
+	case "active":
+		return req, ErrAlreadyEnrolled
+	case "completed", "failed":
+		return req, ErrEnrollmentFinished
+	}
+
+	switch {
+	case needsApproval:
+		req.Status = "pending"
+	default:
+		full, err := courseFull(ctx, tx, courseID)
+		if err != nil {
+			return req, err
+		}
+		req.Status = "enrolled"
+		if full {
+			req.Status = "waitlisted"
+		}
+	}
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO enrollment_requests(course_id, student_id, status, queued_at, decided_at)
+		 VALUES ($1, $2, $3,
+		         CASE WHEN $3 = 'waitlisted' THEN now() END,
+		         CASE WHEN $3 = 'enrolled' THEN now() END)
+		 RETURNING id, created_at, queued_at`,
+		courseID, studentID, req.Status,
+	).Scan(&req.ID, &req.CreatedAt, &req.QueuedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return req, ErrRequestExists
+		}
+		return req, err
+	}
+
+	switch req.Status {
+	case "enrolled":
+		if err := enrollOne(tx.QueryRow(ctx, enrollOneSQL, courseID, studentID, studentID)); err != nil {
+			return req, err
+		}
+	case "waitlisted":
+		if req.Position, err = waitlistPosition(ctx, tx, req.ID); err != nil {
+			return req, err
+		}
+	}
+	return req, tx.Commit(ctx)
+}
+
+// Approve turns a pending request into an enrollment, or into a waitlist
+// place if the course is full.
+func (r *EnrollmentRequestRepo) Approve(ctx context.Context, courseID, requestID, decidedBy int, comment string) (model.EnrollmentRequest, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.EnrollmentRequest{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockCourse(ctx, tx, courseID); err != nil {
+		return model.EnrollmentRequest{}, err
+	}
+	req, err := getRequestForUpdate(ctx, tx, courseID, requestID)
+	if err != nil {
+		return req, err
+	}
+	if req.Status != "pending" {
+		return req, apperr.Conflict("request_not_pending", "request is not pending")
+	}
+
+	full, err := courseFull(ctx, tx, courseID)
+	if err != nil {
+		return req, err
+	}
+	req.Status = "enrolled"
+	if full {
+		req.Status = "waitlisted"
+	}
+	if err := tx.QueryRow(ctx,
+		`UPDATE enrollment_requests SET
+		   status = $2, comment = $3, decided_by = $4, decided_at = now(),
+		   queued_at = CASE WHEN $2 = 'waitlisted' THEN now() END
+		 WHERE id = $1
+		 RETURNING queued_at, decided_by, decided_at`,
+		requestID, req.Status, comment, decidedBy,
+	).Scan(&req.QueuedAt, &req.DecidedBy, &req.DecidedAt); err != nil {
+		return req, err
+	}
+	req.Comment = comment
+
+	if req.Status == "enrolled" {
+		if err := enrollOne(tx.QueryRow(ctx, enrollOneSQL, courseID, req.StudentID, decidedBy)); err != nil {
+			return req, err
+		}
+	} else if req.Position, err = waitlistPosition(ctx, tx, req.ID); err != nil {
+		return req, err
+	}
+	return req, tx.Commit(ctx)
+}
+
+// Reject closes a pending or waitlisted request.
+func (r *EnrollmentRequestRepo) Reject(ctx context.Context, courseID, requestID, decidedBy int, comment string) error {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE enrollment_requests SET status='rejected', comment=$3, decided_by=$4, decided_at=now()
+		 WHERE id=$1 AND course_id=$2 AND status IN ('pending','waitlisted')`,
+		requestID, courseID, comment, decidedBy,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Drop lets a student leave a course: an open request is cancelled, an
+// individual enrollment dropped (freeing a seat for the waitlist). It
+// returns what happened and who was promoted.
+func (r *EnrollmentRequestRepo) Drop(ctx context.Context, courseID, studentID int) (string, []int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockCourse(ctx, tx, courseID); err != nil {
+		return "", nil, err
+	}
+	tag, err := tx.Exec(ctx,
+		`UPDATE enrollment_requests SET status='cancelled', decided_at=now()
+		 WHERE course_id=$1 AND student_id=$2 AND status IN ('pending','waitlisted')`,
+		courseID, studentID,
+	)
+	if err != nil {
+		return "", nil, err
+	}
+	if tag.RowsAffected() > 0 {
+		return "cancelled", nil, tx.Commit(ctx)
+	}
+
+	var groupID *int
+	err = tx.QueryRow(ctx,
+		`UPDATE enrollments
+		 SET status = 'dropped', status_reason = 'dropped by student', status_changed_by = $2, status_changed_at = now()
+		 WHERE course_id=$1 AND student_id=$2 AND group_id IS NULL AND status = 'active'
+		 RETURNING group_id`,
+		courseID, studentID,
+	).Scan(&groupID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		var viaGroup bool
+		if err := tx.QueryRow(ctx,
+			`SELECT EXISTS(SELECT 1 FROM enrollments WHERE course_id=$1 AND student_id=$2 AND status='active')`,
+			courseID, studentID,
+		).Scan(&viaGroup); err != nil {
+			return "", nil, err
+		}
+		if viaGroup {
+			return "", nil, ErrGroupEnrollment
+		}
+		return "", nil, pgx.ErrNoRows
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	promoted, err := promoteWaitlist(ctx, tx, courseID)
+	if err != nil {
+		return "", nil, err
+	}
+	return "dropped", promoted, tx.Commit(ctx)
+}
+
+const requestSelect = `
+SELECT q.id, q.course_id, q.student_id, u.full_name, q.status, q.comment, q.created_at,
+       q.queued_at, q.decided_by, q.decided_at,
+       CASE WHEN q.status = 'waitlisted' THEN (
+         SELECT count(*) FROM enrollment_requests w
+         WHERE w.course_id = q.course_id AND w.status = 'waitlisted' AND (w.queued_at, w.id) <= (q.queued_at, q.id)
+       ) ELSE 0 END
+FROM enrollment_requests q
+JOIN users u ON u.id = q.student_id`
+
+func scanRequests(rows pgx.Rows) ([]model.EnrollmentRequest, error) {
+	defer rows.Close()
+	out := make([]model.EnrollmentRequest, 0)
+	for rows.Next() {
+		var x model.EnrollmentRequest
+		if err := rows.Scan(&x.ID, &x.CourseID, &x.StudentID, &x.StudentName, &x.Status, &x.Comment,
+			&x.CreatedAt, &x.QueuedAt, &x.DecidedBy, &x.DecidedAt, &x.Position); err != nil {
+			return nil, err
+		}
+		out = append(out, x)
+	}
+	return out, rows.Err()
+}
+
+// ListByCourse returns requests of a course, optionally by status;
+// the waitlist comes in queue order.
+func (r *EnrollmentRequestRepo) ListByCourse(ctx context.Context, courseID int, status string) ([]model.EnrollmentRequest, error) {
+	rows, err := r.db.Query(ctx,
+		requestSelect+`
+		 WHERE q.course_id = $1 AND ($2 = '' OR q.status = $2)
+		 ORDER BY q.queued_at ASC NULLS LAST, q.created_at ASC
+		 LIMIT 500`,
+		courseID, status,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanRequests(rows)
+}
+
+func (r *EnrollmentRequestRepo) ListByStudent(ctx context.Context, studentID int) ([]model.EnrollmentRequest, error) {
+	rows, err := r.db.Query(ctx,
+		requestSelect+`
+		 WHERE q.student_id = $1
+		 ORDER BY q.created_at DESC
+		 LIMIT 200`,
+		studentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanRequests(rows)
+}
+
+// lockCourse serializes seat-changing operations of one course. NO KEY
+// UPDATE still lets other transactions insert enrollments referencing it.
+func lockCourse(ctx context.Context, tx pgx.Tx, courseID int) error {
+	var id int
+	return tx.QueryRow(ctx, `SELECT id FROM courses WHERE id=$1 FOR NO KEY UPDATE`, courseID).Scan(&id)
+}
+
+func courseFull(ctx context.Context, tx pgx.Tx, courseID int) (bool, error) {
+	var full bool
+	err := tx.QueryRow(ctx,
+		`SELECT COALESCE(
+		   (SELECT p.capacity <= (SELECT count(*) FROM enrollments e WHERE e.course_id = $1 AND e.status = 'active')
+		    FROM enrollment_policies p WHERE p.course_id = $1 AND p.capacity IS NOT NULL),
+		   false)`,
+		courseID,
+	).Scan(&full)
+	return full, err
+}
+
+// unmetRequirementSQL finds a rule of course $1 that the waitlisted student
+// q.student_id does not meet, or a timetable clash. It repeats the service's
+// requirement check for promotions, which happen inside repository
+// transactions; keep the two in step.
+const unmetRequirementSQL = `
+	SELECT 1 FROM course_requirements r
+	WHERE r.course_id = $1 AND CASE r.kind
+	  WHEN 'prerequisite' THEN NOT EXISTS (
+	    SELECT 1 FROM enrollments d
+	    WHERE d.course_id = r.required_course_id AND d.student_id = q.student_id AND d.status = 'completed'
+	      AND (r.min_grade IS NULL OR d.grade >= r.min_grade))
+	  WHEN 'corequisite' THEN NOT EXISTS (
+	    SELECT 1 FROM enrollments d
+	    WHERE d.course_id = r.required_course_id AND d.student_id = q.student_id AND d.status IN ('active','completed'))
+	  WHEN 'year' THEN COALESCE((SELECT year_of_study FROM user_profiles WHERE user_id = q.student_id), 0) < r.min_year
+	  WHEN 'group' THEN NOT EXISTS (SELECT 1 FROM group_members m WHERE m.student_id = q.student_id AND m.group_id = r.group_id)
+	END
+	UNION ALL
+	SELECT 1 FROM course_schedule t
+	JOIN course_schedule o ON o.weekday = t.weekday AND o.starts_at < t.ends_at AND t.starts_at < o.ends_at
+	JOIN enrollments e ON e.course_id = o.course_id AND e.student_id = q.student_id AND e.status = 'active'
+	WHERE t.course_id = $1 AND o.course_id <> $1`
+
+// promoteWaitlist enrolls waitlisted students, oldest first, while seats are
+// free; without a capacity (LIMIT NULL) everyone queued gets in. Students who
+// no longer meet the course requirements keep their place but are skipped.
+// Nobody in particular promotes them, so the history names no user. The
+// caller holds the course lock.
+func promoteWaitlist(ctx context.Context, tx pgx.Tx, courseID int) ([]int, error) {
+	rows, err := tx.Query(ctx,
+		`WITH picked AS (
+		   SELECT q.id FROM enrollment_requests q
+		   WHERE q.course_id = $1 AND q.status = 'waitlisted' AND NOT EXISTS (`+unmetRequirementSQL+`)
+		   ORDER BY q.queued_at, q.id
+		   LIMIT (SELECT CASE WHEN p.capacity IS NULL THEN NULL
+		                 ELSE GREATEST(p.capacity - (SELECT count(*) FROM enrollments e WHERE e.course_id = $1 AND e.status = 'active'), 0) END
+		          FROM enrollment_policies p WHERE p.course_id = $1)
+		   FOR UPDATE OF q
+		 )
+		 UPDATE enrollment_requests q SET status = 'enrolled', decided_at = now()
+		 FROM picked WHERE q.id = picked.id
+		 RETURNING q.student_id`,
+		courseID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	promoted := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		promoted = append(promoted, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(promoted) == 0 {
+		return promoted, nil
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO enrollments(course_id, student_id, status_reason)
+		 SELECT $1, unnest($2::int[]), 'promoted from the waitlist'`+reactivateOnConflict,
+		courseID, promoted,
+	)
+	return promoted, err
+}
+
+func getRequestForUpdate(ctx context.Context, tx pgx.Tx, courseID, requestID int) (model.EnrollmentRequest, error) {
+	var x model.EnrollmentRequest
+	err := tx.QueryRow(ctx,
+		`SELECT id, course_id, student_id, status, comment, created_at
+		 FROM enrollment_requests WHERE id=$1 AND course_id=$2 FOR UPDATE`,
+		requestID, courseID,
+	).Scan(&x.ID, &x.CourseID, &x.StudentID, &x.Status, &x.Comment, &x.CreatedAt)
+	return x, err
+}
+
+func waitlistPosition(ctx context.Context, tx pgx.Tx, requestID int) (int, error) {
+	var n int
+	err := tx.QueryRow(ctx,
+		`SELECT count(*) FROM enrollment_requests w, enrollment_requests q
+		 WHERE q.id = $1 AND w.course_id = q.course_id AND w.status = 'waitlisted'
+		   AND (w.queued_at, w.id) <= (q.queued_at, q.id)`,
+		requestID,
+	).Scan(&n)
+	return n, err
+}
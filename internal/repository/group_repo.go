@@ -0,0 +1,402 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type GroupRepo struct{ db *pgxpool.Pool }
+
+func NewGroupRepo(db *pgxpool.Pool) *GroupRepo { return &GroupRepo{db: db} }
+
+var ErrGroupNameTaken = apperr.Conflict("group_name_taken", "group name already in use")
+
+const groupSelect = `
+SELECT g.id, g.name, COALESCE(g.faculty,''), COALESCE(g.year_of_study,0), g.created_at,
+       (SELECT count(*) FROM group_members m WHERE m.group_id = g.id)
+FROM student_groups g`
+
+func scanGroup(row pgx.Row) (model.StudentGroup, error) {
+	var g model.StudentGroup
+	err := row.Scan(&g.ID, &g.Name, &g.Faculty, &g.Year, &g.CreatedAt, &g.Members)
+	return g, err
+}
+
+func (r *GroupRepo) List(ctx context.Context) ([]model.StudentGroup, error) {
+	rows, err := r.db.Query(ctx, groupSelect+` ORDER BY g.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.StudentGroup, 0)
+	for rows.Next() {
+		g, err := scanGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, g)
+	}
+	return out, rows.Err()
+}
+
+func (r *GroupRepo) Get(ctx context.Context, id int) (model.StudentGroup, error) {
+	return scanGroup(r.db.QueryRow(ctx, groupSelect+` WHERE g.id = $1`, id))
+}
+
+func (r *GroupRepo) GetByName(ctx context.Context, name string) (model.StudentGroup, error) {
+	return scanGroup(r.db.QueryRow(ctx, groupSelect+` WHERE g.name = $1`, name))
+}
+
+func (r *GroupRepo) Create(ctx context.Context, g model.StudentGroup) (int, error) {
+	var id int
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO student_groups(name, faculty, year_of_study)
+		 VALUES ($1, NULLIF($2,''), NULLIF($3::int,0)) RETURNING id`,
+		g.Name, g.Faculty, g.Year,
+	).Scan(&id)
+	return id, groupNameErr(err)
+}
+
+// Update renames/edits a group and keeps its members' profiles in step.
+func (r *GroupRepo) Update(ctx context.Context, g model.StudentGroup) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE student_groups SET name=$2, faculty=NULLIF($3,''), year_of_study=NULLIF($4::int,0) WHERE id=$1`,
+		g.ID, g.Name, g.Faculty, g.Year,
+	)
+	if err != nil {
+		return groupNameErr(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE user_profiles p SET group_name = $2, updated_at = now()
+		 FROM group_members m WHERE m.group_id = $1 AND m.student_id = p.user_id`,
+		g.ID, g.Name,
+	); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Delete removes a group. Its course enrollments stay, now as individual
+// ones, and members' profiles lose the group name.
+func (r *GroupRepo) Delete(ctx context.Context, id int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE user_profiles p SET group_name = NULL, updated_at = now()
+		 FROM group_members m WHERE m.group_id = $1 AND m.student_id = p.user_id`,
+		id,
+	); err != nil {
+		return err
+	}
+	tag, err := tx.Exec(ctx, `DELETE FROM student_groups WHERE id=$1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *GroupRepo) Members(ctx context.Context, groupID int) ([]model.User, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT u.id, u.full_name, u.email
+		 FROM group_members m
+		 JOIN users u ON u.id = m.student_id
+		 WHERE m.group_id = $1
+		 ORDER BY u.full_name, u.id`,
+		groupID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.User, 0)
+	for rows.Next() {
+		var u model.User
+		if err := rows.Scan(&u.ID, &u.FullName, &u.Email); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// CourseIDs returns the courses the group is enrolled in.
+func (r *GroupRepo) CourseIDs(ctx context.Context, groupID int) ([]int, error) {
+	return r.ids(ctx, `SELECT course_id FROM course_groups WHERE group_id = $1 ORDER BY course_id`, groupID)
+}
+
+// EnrollableMembers returns the members group enrollment enrolls: the
+// active, undeleted ones.
+func (r *GroupRepo) EnrollableMembers(ctx context.Context, groupID int) ([]int, error) {
+	return r.ids(ctx,
+		`SELECT m.student_id FROM group_members m
+		 JOIN users u ON u.id = m.student_id
+		 WHERE m.group_id = $1 AND u.active AND u.deleted_at IS NULL
+		 ORDER BY m.student_id`,
+		groupID,
+	)
+}
+
+func (r *GroupRepo) ids(ctx context.Context, sql string, args ...any) ([]int, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+// NonStudents returns the ids among ids that are not active, undeleted
+// students.
+func (r *GroupRepo) NonStudents(ctx context.Context, ids []int) ([]int, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT x.id FROM unnest($1::int[]) AS x(id)
+		 WHERE NOT EXISTS (
+		   SELECT 1 FROM users u
+		   WHERE u.id = x.id AND u.active AND u.deleted_at IS NULL
+		     AND u.role_id = (SELECT id FROM roles WHERE name = 'student')
+		 )`,
+		ids,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+// AddMembers moves students into a group. Each student leaves their old
+// group's courses (enrollments that came from that group) and joins the
+// courses of the new one; individual enrollments are not touched. Only
+// active, undeleted students are enrolled. by is the acting user.
+func (r *GroupRepo) AddMembers(ctx context.Context, groupID int, studentIDs []int, by int) (model.GroupSync, error) {
+	var res model.GroupSync
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return res, err
+	}
+	defer tx.Rollback(ctx)
+
+	var name string
+	if err := tx.QueryRow(ctx, `SELECT name FROM student_groups WHERE id=$1 FOR UPDATE`, groupID).Scan(&name); err != nil {
+		return res, err
+	}
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE enrollments e
+		 SET status = 'dropped', status_reason = 'moved to group ' || $3, status_changed_by = NULLIF($4,0), status_changed_at = now()
+		 FROM group_members m
+		 WHERE m.student_id = ANY($1) AND m.group_id <> $2
+		   AND e.student_id = m.student_id AND e.group_id = m.group_id AND e.status = 'active'`,
+		studentIDs, groupID, name, by,
+	)
+	if err != nil {
+		return res, err
+	}
+	res.Unenrolled = int(tag.RowsAffected())
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO group_members(student_id, group_id)
+		 SELECT unnest($1::int[]), $2
+		 ON CONFLICT (student_id) DO UPDATE SET group_id = EXCLUDED.group_id, joined_at = now()
+		 WHERE group_members.group_id <> EXCLUDED.group_id`,
+		studentIDs, groupID,
+	); err != nil {
+		return res, err
+	}
+
+	tag, err = tx.Exec(ctx,
+		`INSERT INTO enrollments(course_id, student_id, group_id, status_changed_by)
+		 SELECT cg.course_id, u.id, $2, NULLIF($3,0)
+		 FROM course_groups cg, users u
+		 WHERE cg.group_id = $2 AND u.id = ANY($1) AND u.active AND u.deleted_at IS NULL`+reactivateOnConflict,
+		studentIDs, groupID, by,
+	)
+	if err != nil {
+		return res, err
+	}
+	res.Enrolled = int(tag.RowsAffected())
+
+	if err := setProfileGroup(ctx, tx, studentIDs, name); err != nil {
+		return res, err
+	}
+	return res, tx.Commit(ctx)
+}
+
+// RemoveMember takes a student out of a group and drops the courses they
+// were enrolled in through it.
+func (r *GroupRepo) RemoveMember(ctx context.Context, groupID, studentID, by int) (model.GroupSync, error) {
+	var res model.GroupSync
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return res, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `DELETE FROM group_members WHERE group_id=$1 AND student_id=$2`, groupID, studentID)
+	if err != nil {
+		return res, err
+	}
+	if tag.RowsAffected() == 0 {
+		return res, pgx.ErrNoRows
+	}
+	tag, err = tx.Exec(ctx,
+		`UPDATE enrollments
+		 SET status = 'dropped', status_reason = 'left the group', status_changed_by = NULLIF($3,0), status_changed_at = now()
+		 WHERE group_id=$1 AND student_id=$2 AND status = 'active'`,
+		groupID, studentID, by,
+	)
+	if err != nil {
+		return res, err
+	}
+	res.Unenrolled = int(tag.RowsAffected())
+
+	if err := setProfileGroup(ctx, tx, []int{studentID}, ""); err != nil {
+		return res, err
+	}
+	return res, tx.Commit(ctx)
+}
+
+// EnrollGroup enrolls every active, undeleted member of the group in the
+// course; later members are enrolled when they join the group.
+func (r *GroupRepo) EnrollGroup(ctx context.Context, courseID, groupID, by int) (model.GroupSync, error) {
+	var res model.GroupSync
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return res, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO course_groups(course_id, group_id) VALUES ($1,$2) ON CONFLICT DO NOTHING`,
+		courseID, groupID,
+	); err != nil {
+		return res, err
+	}
+	tag, err := tx.Exec(ctx,
+		`INSERT INTO enrollments(course_id, student_id, group_id, status_changed_by)
+		 SELECT $1, m.student_id, $2, NULLIF($3,0)
+		 FROM group_members m
+		 JOIN users u ON u.id = m.student_id
+		 WHERE m.group_id = $2 AND u.active AND u.deleted_at IS NULL`+reactivateOnConflict,
+		courseID, groupID, by,
+	)
+	if err != nil {
+		return res, err
+	}
+	res.Enrolled = int(tag.RowsAffected())
+	return res, tx.Commit(ctx)
+}
+
+// UnenrollGroup removes the group from the course and withdraws the
+// enrollments that came from it.
+func (r *GroupRepo) UnenrollGroup(ctx context.Context, courseID, groupID, by int) (model.GroupSync, error) {
+	var res model.GroupSync
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return res, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `DELETE FROM course_groups WHERE course_id=$1 AND group_id=$2`, courseID, groupID)
+	if err != nil {
+		return res, err
+	}
+	if tag.RowsAffected() == 0 {
+		return res, pgx.ErrNoRows
+	}
+	tag, err = tx.Exec(ctx,
+		`UPDATE enrollments
+		 SET status = 'withdrawn', status_reason = 'group unenrolled', status_changed_by = NULLIF($3,0), status_changed_at = now()
+		 WHERE course_id=$1 AND group_id=$2 AND status = 'active'`,
+		courseID, groupID, by,
+	)
+	if err != nil {
+		return res, err
+	}
+	res.Unenrolled = int(tag.RowsAffected())
+	return res, tx.Commit(ctx)
+}
+
+func (r *GroupRepo) CourseGroups(ctx context.Context, courseID int) ([]model.StudentGroup, error) {
+	rows, err := r.db.Query(ctx,
+		groupSelect+` JOIN course_groups cg ON cg.group_id = g.id WHERE cg.course_id = $1 ORDER BY g.name`,
+		courseID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.StudentGroup, 0)
+	for rows.Next() {
+		g, err := scanGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, g)
+	}
+	return out, rows.Err()
+}
+
+// setProfileGroup mirrors group membership into user_profiles.group_name,
+// which the user filters use.
+func setProfileGroup(ctx context.Context, tx pgx.Tx, studentIDs []int, name string) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO user_profiles(user_id, group_name)
+		 SELECT unnest($1::int[]), NULLIF($2,'')
+		 ON CONFLICT (user_id) DO UPDATE SET group_name = EXCLUDED.group_name, updated_at = now()`,
+		studentIDs, name,
+	)
+	return err
+}
+
+func groupNameErr(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrGroupNameTaken
+	}
+	return err
+}
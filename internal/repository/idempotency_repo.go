@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"lms-backend/internal/domain/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdempotencyRepo struct{ db *pgxpool.Pool }
+
+func NewIdempotencyRepo(db *pgxpool.Pool) *IdempotencyRepo { return &IdempotencyRepo{db: db} }
+
+// Reserve claims the key for a new request, also when an earlier use has
+// expired or its request has run for ten minutes without finishing, which
+// means the instance died under it. When the key is taken, reserved is false and stored is the row
+// holding it.
+func (r *IdempotencyRepo) Reserve(ctx context.Context, userID int, key, hash string, ttl time.Duration) (stored model.IdempotentResponse, reserved bool, err error) {
+	for range 2 { // the holder may release the key between the two queries
+		tag, err := r.db.Exec(ctx, `
+			INSERT INTO idempotency_keys(user_id, key, request_hash, expires_at)
+			VALUES ($1, $2, $3, now() + $4 * interval '1 second')
+			ON CONFLICT (user_id, key) DO UPDATE
+			SET request_hash = EXCLUDED.request_hash, status = NULL, content_type = '', body = NULL,
+			    created_at = now(), expires_at = EXCLUDED.expires_at
+			WHERE idempotency_keys.expires_at <= now()
+			   OR (idempotency_keys.status IS NULL AND idempotency_keys.created_at <= now() - interval '10 minutes')`,
+			userID, key, hash, int64(ttl/time.Second),
+		)
+		if err != nil {
+			return stored, false, err
+		}
+		if tag.RowsAffected() == 1 {
+			return stored, true, nil
+		}
+
+		err = r.db.QueryRow(ctx, `
+			SELECT user_id, key, request_hash, COALESCE(status, 0), content_type, COALESCE(body, ''::bytea), expires_at
+			FROM idempotency_keys WHERE user_id=$1 AND key=$2`,
+			userID, key,
+		).Scan(&stored.UserID, &stored.Key, &stored.RequestHash, &stored.Status, &stored.ContentType, &stored.Body, &stored.ExpiresAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		return stored, false, err
+	}
+	return stored, false, errors.New("idempotency key released and taken again")
+}
+
+// Complete stores the response of the request holding the key.
+func (r *IdempotencyRepo) Complete(ctx context.Context, resp model.IdempotentResponse) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE idempotency_keys SET status=$3, content_type=$4, body=$5
+		 WHERE user_id=$1 AND key=$2 AND request_hash=$6 AND status IS NULL`,
+		resp.UserID, resp.Key, resp.Status, resp.ContentType, resp.Body, resp.RequestHash,
+	)
+	return err
+}
+
+// Release frees a key whose request stored no response, so a retry runs it
+// again.
+func (r *IdempotencyRepo) Release(ctx context.Context, userID int, key, hash string) error {
+	_, err := r.db.Exec(ctx,
+		`DELETE FROM idempotency_keys WHERE user_id=$1 AND key=$2 AND request_hash=$3 AND status IS NULL`,
+		userID, key, hash,
+	)
+	return err
+}
+
+func (r *IdempotencyRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= now()`)
+	return tag.RowsAffected(), err
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+
+	"lms-backend/internal/domain/model"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ImportRepo struct{ db *pgxpool.Pool }
+
+func NewImportRepo(db *pgxpool.Pool) *ImportRepo { return &ImportRepo{db: db} }
+
+// upsertUserSQL creates the user or updates only the fields the row sets.
+// Rows that would change nothing are not touched and come back from the
+// second SELECT with action "unchanged".
+const upsertUserSQL = `
+WITH up AS (
+  INSERT INTO users(email, password_hash, full_name, role_id)
+  VALUES ($1, $2, $3, COALESCE(NULLIF($4::int, 0), (SELECT id FROM roles WHERE name = 'student')))
+  ON CONFLICT (email) DO UPDATE SET
+    full_name     = COALESCE(NULLIF($3, ''), users.full_name),
+    role_id       = COALESCE(NULLIF($4::int, 0), users.role_id),
+    password_hash = COALESCE(NULLIF($2, ''), users.password_hash)
+  WHERE (NULLIF($3, '') IS NOT NULL AND users.full_name <> $3)
+     OR (NULLIF($4::int, 0) IS NOT NULL AND users.role_id <> $4::int)
+     OR NULLIF($2, '') IS NOT NULL
+  RETURNING id, CASE WHEN xmax = 0 THEN 'created' ELSE 'updated' END AS action
+)
+SELECT id, action FROM up
+UNION ALL
+SELECT id, 'unchanged' FROM users WHERE email = $1 AND NOT EXISTS (SELECT 1 FROM up)`
+
+// Apply runs the whole roster in one transaction. With dryRun the
+// transaction is rolled back, so the results show exactly what would happen.
+// by is the importing user, recorded as the one who enrolled the students.
+func (r *ImportRepo) Apply(ctx context.Context, rows []model.ImportRow, dryRun bool, by int) ([]model.ImportResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	out := make([]model.ImportResult, 0, len(rows))
+	for _, row := range rows {
+		res := model.ImportResult{Line: row.Line, Email: row.Email, Enrolled: []int{}}
+		if err := tx.QueryRow(ctx, upsertUserSQL, row.Email, row.PasswordHash, row.FullName, row.RoleID).
+			Scan(&res.UserID, &res.Action); err != nil {
+			return nil, err
+		}
+
+		if len(row.CourseIDs) > 0 {
+			enrolled, err := tx.Query(ctx,
+				`INSERT INTO enrollments(course_id, student_id, status_changed_by)
+				 SELECT unnest($1::int[]), $2, NULLIF($3,0)`+reactivateOnConflict+`
+				 RETURNING course_id`,
+				row.CourseIDs, res.UserID, by,
+			)
+			if err != nil {
+				return nil, err
+			}
+			for enrolled.Next() {
+				var id int
+				if err := enrolled.Scan(&id); err != nil {
+					enrolled.Close()
+					return nil, err
+				}
+				res.Enrolled = append(res.Enrolled, id)
+			}
+			enrolled.Close()
+			if err := enrolled.Err(); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, res)
+	}
+
+	if dryRun {
+		return out, nil
+	}
+	return out, tx.Commit(ctx)
+}
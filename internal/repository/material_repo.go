@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+
+	"lms-backend/internal/domain/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type MaterialRepo struct{ db *pgxpool.Pool }
+
+func NewMaterialRepo(db *pgxpool.Pool) *MaterialRepo { return &MaterialRepo{db: db} }
+
+func (r *MaterialRepo) Create(ctx context.Context, m model.CourseMaterial) (int, error) {
+	var id int
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO course_materials(course_id, title, body, created_by) VALUES ($1,$2,$3,$4) RETURNING id`,
+		m.CourseID, m.Title, m.Body, m.CreatedBy,
+	).Scan(&id)
+	return id, err
+}
+
+func (r *MaterialRepo) Get(ctx context.Context, courseID, id int) (model.CourseMaterial, error) {
+	var m model.CourseMaterial
+	err := r.db.QueryRow(ctx,
+		`SELECT id, course_id, title, body, created_by, created_at FROM course_materials WHERE course_id = $1 AND id = $2`,
+		courseID, id,
+	).Scan(&m.ID, &m.CourseID, &m.Title, &m.Body, &m.CreatedBy, &m.CreatedAt)
+	return m, err
+}
+
+// ListByCourse returns the course's materials without their bodies, newest
+// first.
+func (r *MaterialRepo) ListByCourse(ctx context.Context, courseID int) ([]model.CourseMaterial, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT id, course_id, title, created_by, created_at
+		 FROM course_materials
+		 WHERE course_id = $1
+		 ORDER BY created_at DESC, id DESC`,
+		courseID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.CourseMaterial, 0)
+	for rows.Next() {
+		var m model.CourseMaterial
+		if err := rows.Scan(&m.ID, &m.CourseID, &m.Title, &m.CreatedBy, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+func (r *MaterialRepo) Delete(ctx context.Context, courseID, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM course_materials WHERE course_id = $1 AND id = $2`, courseID, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
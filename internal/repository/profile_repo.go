@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/pagination"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ProfileRepo struct{ db *pgxpool.Pool }
+
+func NewProfileRepo(db *pgxpool.Pool) *ProfileRepo { return &ProfileRepo{db: db} }
+
+var ErrStudentNumberTaken = apperr.Conflict("student_number_taken", "student_number already in use")
+
+// Get returns the profile of a user, or an empty one if none was saved yet.
+func (r *ProfileRepo) Get(ctx context.Context, userID int) (model.UserProfile, error) {
+	p := model.UserProfile{UserID: userID}
+	err := r.db.QueryRow(ctx,
+		`SELECT COALESCE(student_number,''), COALESCE(group_name,''), COALESCE(year_of_study,0),
+		        COALESCE(faculty,''), COALESCE(phone,''), COALESCE(language,''),
+		        COALESCE(department,''), COALESCE(title,''), COALESCE(avatar_path,''), updated_at
+		 FROM user_profiles WHERE user_id=$1`,
+		userID,
+	).Scan(&p.StudentNumber, &p.Group, &p.Year, &p.Faculty, &p.Phone, &p.Language,
+		&p.Department, &p.Title, &p.AvatarPath, &p.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return p, nil
+	}
+	return p, err
+}
+
+// Save stores every field of p except the avatar.
+func (r *ProfileRepo) Save(ctx context.Context, p model.UserProfile) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO user_profiles(user_id, student_number, group_name, year_of_study, faculty,
+		                           phone, language, department, title)
+		 VALUES ($1, NULLIF($2,''), NULLIF($3,''), NULLIF($4::int,0), NULLIF($5,''),
+		         NULLIF($6,''), NULLIF($7,''), NULLIF($8,''), NULLIF($9,''))
+		 ON CONFLICT (user_id) DO UPDATE SET
+		   student_number = EXCLUDED.student_number,
+		   group_name     = EXCLUDED.group_name,
+		   year_of_study  = EXCLUDED.year_of_study,
+		   faculty        = EXCLUDED.faculty,
+		   phone          = EXCLUDED.phone,
+		   language       = EXCLUDED.language,
+		   department     = EXCLUDED.department,
+		   title          = EXCLUDED.title,
+		   updated_at     = now()`,
+		p.UserID, p.StudentNumber, p.Group, p.Year, p.Faculty,
+		p.Phone, p.Language, p.Department, p.Title,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrStudentNumberTaken
+	}
+	return err
+}
+
+// SetAvatar stores a new avatar path (empty clears it) and returns the old
+// one so the caller can remove the file.
+func (r *ProfileRepo) SetAvatar(ctx context.Context, userID int, path string) (string, error) {
+	var old string
+	err := r.db.QueryRow(ctx,
+		`WITH prev AS (SELECT avatar_path FROM user_profiles WHERE user_id=$1)
+		 INSERT INTO user_profiles(user_id, avatar_path) VALUES ($1, NULLIF($2,''))
+		 ON CONFLICT (user_id) DO UPDATE SET avatar_path = EXCLUDED.avatar_path, updated_at = now()
+		 RETURNING COALESCE((SELECT avatar_path FROM prev),'')`,
+		userID, path,
+	).Scan(&old)
+	return old, err
+}
+
+// userFilters are the list filters of queries that join users as u and
+// user_profiles as p: user lists and course rosters.
+var userFilters = map[string]pagination.Filter{
+	"role":       {Cond: `u.role_id = (SELECT id FROM roles WHERE name = $?)`},
+	"group":      {Cond: `p.group_name = $?`},
+	"year":       {Cond: `p.year_of_study = $?`, Kind: pagination.Int},
+	"faculty":    {Cond: `p.faculty = $?`},
+	"department": {Cond: `p.department = $?`},
+	"language":   {Cond: `p.language = $?`},
+	"q":          {Cond: `(u.full_name ILIKE $? OR u.email ILIKE $? OR p.student_number ILIKE $?)`, Like: true},
+}
+
+// withFilters returns base extended by extra.
+func withFilters(base, extra map[string]pagination.Filter) map[string]pagination.Filter {
+	out := make(map[string]pagination.Filter, len(base)+len(extra))
+	for k, f := range base {
+		out[k] = f
+	}
+	for k, f := range extra {
+		out[k] = f
+	}
+	return out
+}
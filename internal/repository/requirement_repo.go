@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RequirementRepo stores course prerequisites/co-requisites and the weekly
+// schedule, and reads what a student has taken for eligibility checks.
+type RequirementRepo struct{ db *pgxpool.Pool }
+
+func NewRequirementRepo(db *pgxpool.Pool) *RequirementRepo { return &RequirementRepo{db: db} }
+
+var (
+	ErrRequirementCycle  = apperr.Conflict("requirement_cycle", "prerequisite would create a cycle")
+	ErrRequirementTarget = apperr.Unprocessable("requirement_target_not_found", "required course or group not found")
+)
+
+func (r *RequirementRepo) List(ctx context.Context, courseID int) ([]model.CourseRequirement, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT q.id, q.course_id, q.kind, COALESCE(q.required_course_id,0), COALESCE(c.title,''),
+		        q.min_grade::float8, COALESCE(q.min_year,0), COALESCE(q.group_id,0), COALESCE(g.name,'')
+		 FROM course_requirements q
+		 LEFT JOIN courses c ON c.id = q.required_course_id
+		 LEFT JOIN student_groups g ON g.id = q.group_id
+		 WHERE q.course_id = $1
+		 ORDER BY q.id`,
+		courseID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.CourseRequirement, 0)
+	for rows.Next() {
+		var q model.CourseRequirement
+		if err := rows.Scan(&q.ID, &q.CourseID, &q.Kind, &q.RequiredCourseID, &q.RequiredCourse,
+			&q.MinGrade, &q.MinYear, &q.GroupID, &q.GroupName); err != nil {
+			return nil, err
+		}
+		out = append(out, q)
+	}
+	return out, rows.Err()
+}
+
+// Add stores a requirement. Prerequisites must not make a course (indirectly)
+// require itself.
+func (r *RequirementRepo) Add(ctx context.Context, q model.CourseRequirement) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if q.Kind == "prerequisite" {
+		// serialize prerequisite edits so two inserts cannot close a cycle together
+		if _, err := tx.Exec(ctx, `LOCK TABLE course_requirements IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+			return 0, err
+		}
+		var cycle bool
+		err := tx.QueryRow(ctx,
+			`WITH RECURSIVE chain(course_id) AS (
+			   SELECT $1::int
+			   UNION
+			   SELECT q.required_course_id FROM course_requirements q
+			   JOIN chain ON q.course_id = chain.course_id
+			   WHERE q.kind = 'prerequisite'
+			 )
+			 SELECT EXISTS (SELECT 1 FROM chain WHERE course_id = $2)`,
+			q.RequiredCourseID, q.CourseID,
+		).Scan(&cycle)
+		if err != nil {
+			return 0, err
+		}
+		if cycle {
+			return 0, ErrRequirementCycle
+		}
+	}
+
+	var id int
+	err = tx.QueryRow(ctx,
+		`INSERT INTO course_requirements(course_id, kind, required_course_id, min_grade, min_year, group_id)
+		 VALUES ($1, $2, NULLIF($3,0), $4, NULLIF($5,0), NULLIF($6,0))
+		 RETURNING id`,
+		q.CourseID, q.Kind, q.RequiredCourseID, q.MinGrade, q.MinYear, q.GroupID,
+	).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return 0, ErrRequirementTarget
+		}
+		return 0, err
+	}
+	return id, tx.Commit(ctx)
+}
+
+func (r *RequirementRepo) Delete(ctx context.Context, courseID, id int) error {
+	tag, err := r.db.Exec(ctx,
+		`DELETE FROM course_requirements WHERE id = $1 AND course_id = $2`, id, courseID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *RequirementRepo) Schedule(ctx context.Context, courseID int) ([]model.ScheduleSlot, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT course_id, weekday, to_char(starts_at,'HH24:MI'), to_char(ends_at,'HH24:MI')
+		 FROM course_schedule
+		 WHERE course_id = $1
+		 ORDER BY weekday, starts_at`,
+		courseID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.ScheduleSlot, 0)
+	for rows.Next() {
+		var s model.ScheduleSlot
+		if err := rows.Scan(&s.CourseID, &s.Weekday, &s.StartsAt, &s.EndsAt); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// SetSchedule replaces the weekly timetable of a course.
+func (r *RequirementRepo) SetSchedule(ctx context.Context, courseID int, slots []model.ScheduleSlot) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM course_schedule WHERE course_id = $1`, courseID); err != nil {
+		return err
+	}
+	for _, s := range slots {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO course_schedule(course_id, weekday, starts_at, ends_at)
+			 VALUES ($1, $2, $3::time, $4::time)`,
+			courseID, s.Weekday, s.StartsAt, s.EndsAt,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// Standing collects the student's year, group and course history.
+func (r *RequirementRepo) Standing(ctx context.Context, studentID int) (model.StudentStanding, error) {
+	st := model.StudentStanding{Courses: map[int]model.CourseStanding{}}
+	err := r.db.QueryRow(ctx,
+		`SELECT COALESCE((SELECT year_of_study FROM user_profiles WHERE user_id = $1), 0),
+		        COALESCE((SELECT group_id FROM group_members WHERE student_id = $1), 0)`,
+		studentID,
+	).Scan(&st.Year, &st.GroupID)
+	if err != nil {
+		return st, err
+	}
+
+	rows, err := r.db.Query(ctx,
+		`SELECT course_id, status = 'active', status = 'completed', grade::float8
+		 FROM enrollments WHERE student_id = $1 AND status IN ('active','completed')`,
+		studentID,
+	)
+	if err != nil {
+		return st, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			courseID int
+			cs       model.CourseStanding
+		)
+		if err := rows.Scan(&courseID, &cs.Enrolled, &cs.Completed, &cs.Grade); err != nil {
+			return st, err
+		}
+		st.Courses[courseID] = cs
+	}
+	return st, rows.Err()
+}
+
+// Conflicts returns slots of courses the student currently attends that
+// overlap the timetable of courseID.
+func (r *RequirementRepo) Conflicts(ctx context.Context, courseID, studentID int) ([]model.ScheduleConflict, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT DISTINCT c.id, c.title, o.weekday, to_char(o.starts_at,'HH24:MI'), to_char(o.ends_at,'HH24:MI')
+		 FROM course_schedule t
+		 JOIN course_schedule o ON o.weekday = t.weekday
+		                       AND o.starts_at < t.ends_at AND t.starts_at < o.ends_at
+		 JOIN enrollments e ON e.course_id = o.course_id AND e.student_id = $2 AND e.status = 'active'
+		 JOIN courses c ON c.id = o.course_id
+		 WHERE t.course_id = $1 AND o.course_id <> $1
+		 ORDER BY o.weekday, 4`,
+		courseID, studentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.ScheduleConflict, 0)
+	for rows.Next() {
+		var x model.ScheduleConflict
+		if err := rows.Scan(&x.CourseID, &x.CourseTitle, &x.Weekday, &x.StartsAt, &x.EndsAt); err != nil {
+			return nil, err
+		}
+		out = append(out, x)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RiskRepo stores at-risk rules, the alerts they raise and student advisors.
+type RiskRepo struct{ db *pgxpool.Pool }
+
+func NewRiskRepo(db *pgxpool.Pool) *RiskRepo { return &RiskRepo{db: db} }
+
+func (r *RiskRepo) ListRules(ctx context.Context, activeOnly bool) ([]model.RiskRule, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT id, name, kind, threshold, min_lessons, COALESCE(course_id,0), active, created_at
+		 FROM risk_rules
+		 WHERE active OR NOT $1
+		 ORDER BY id ASC`,
+		activeOnly,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.RiskRule, 0)
+	for rows.Next() {
+		var x model.RiskRule
+		if err := rows.Scan(&x.ID, &x.Name, &x.Kind, &x.Threshold, &x.MinLessons, &x.CourseID, &x.Active, &x.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, x)
+	}
+	return out, rows.Err()
+}
+
+func (r *RiskRepo) CreateRule(ctx context.Context, x model.RiskRule) (int, error) {
+	var id int
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO risk_rules(name, kind, threshold, min_lessons, course_id, active)
+		 VALUES ($1,$2,$3,$4,NULLIF($5,0),$6) RETURNING id`,
+		x.Name, x.Kind, x.Threshold, x.MinLessons, x.CourseID, x.Active,
+	).Scan(&id)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return 0, apperr.NotFound("course_not_found", "course not found")
+	}
+	return id, err
+}
+
+func (r *RiskRepo) DeleteRule(ctx context.Context, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM risk_rules WHERE id=$1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound("rule_not_found", "rule not found")
+	}
+	return nil
+}
+
+// SetAdvisor assigns an advisor to a student; advisorID == 0 removes it.
+func (r *RiskRepo) SetAdvisor(ctx context.Context, studentID, advisorID int) error {
+	if advisorID == 0 {
+		_, err := r.db.Exec(ctx, `DELETE FROM student_advisors WHERE student_id=$1`, studentID)
+		return err
+	}
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO student_advisors(student_id, advisor_id) VALUES ($1,$2)
+		 ON CONFLICT (student_id) DO UPDATE SET advisor_id = EXCLUDED.advisor_id, assigned_at = now()`,
+		studentID, advisorID,
+	)
+	return err
+}
+
+// SyncAlerts makes the open alerts of a course match flagged for the given
+// rules: new ones are opened, existing ones get the latest value and the
+// rest are resolved. It returns the number of newly opened alerts.
+func (r *RiskRepo) SyncAlerts(ctx context.Context, courseID int, ruleIDs []int, flagged []model.RiskAlert) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	opened := 0
+	rules := make([]int, 0, len(flagged))
+	students := make([]int, 0, len(flagged))
+	for _, a := range flagged {
+		// xmax = 0 only for freshly inserted rows
+		var inserted bool
+		err := tx.QueryRow(ctx,
+			`INSERT INTO risk_alerts(rule_id, course_id, student_id, value, detail)
+			 VALUES ($1,$2,$3,$4,$5)
+			 ON CONFLICT (rule_id, course_id, student_id) WHERE resolved_at IS NULL
+			 DO UPDATE SET value = EXCLUDED.value, detail = EXCLUDED.detail
+			 RETURNING (xmax = 0)`,
+			a.RuleID, courseID, a.StudentID, a.Value, a.Detail,
+		).Scan(&inserted)
+		if err != nil {
+			return 0, err
+		}
+		if inserted {
+			opened++
+		}
+		rules = append(rules, a.RuleID)
+		students = append(students, a.StudentID)
+	}
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE risk_alerts SET resolved_at = now()
+		 WHERE course_id = $1 AND resolved_at IS NULL AND rule_id = ANY($2)
+		   AND (rule_id, student_id) NOT IN (SELECT * FROM unnest($3::int[], $4::int[]))`,
+		courseID, ruleIDs, rules, students,
+	); err != nil {
+		return 0, err
+	}
+	return opened, tx.Commit(ctx)
+}
+
+const riskAlertColumns = `a.id, a.rule_id, r.name, a.course_id, a.student_id, a.value, a.detail,
+	a.created_at, a.notified_at, COALESCE(a.acknowledged_by,0), a.acknowledged_at, a.resolved_at`
+
+// visibleTo limits alerts to those of courses the viewer teaches or of
+// students they advise. $1 is the viewer; 0 means everything (admin).
+const riskAlertVisibleTo = `($1 = 0
+	OR EXISTS (SELECT 1 FROM courses c WHERE c.id = a.course_id AND c.teacher_id = $1)
+	OR EXISTS (SELECT 1 FROM student_advisors sa WHERE sa.student_id = a.student_id AND sa.advisor_id = $1))`
+
+// ListAlerts lists alerts visible to viewerID (0 = all), optionally for one
+// course and including resolved ones.
+func (r *RiskRepo) ListAlerts(ctx context.Context, viewerID, courseID int, includeResolved bool) ([]model.RiskAlert, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+riskAlertColumns+`
+		 FROM risk_alerts a
+		 JOIN risk_rules r ON r.id = a.rule_id
+		 WHERE `+riskAlertVisibleTo+`
+		   AND ($2 = 0 OR a.course_id = $2)
+		   AND ($3 OR a.resolved_at IS NULL)
+		 ORDER BY a.created_at DESC
+		 LIMIT 500`,
+		viewerID, courseID, includeResolved,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.RiskAlert, 0)
+	for rows.Next() {
+		var x model.RiskAlert
+		if err := rows.Scan(&x.ID, &x.RuleID, &x.RuleName, &x.CourseID, &x.StudentID, &x.Value, &x.Detail,
+			&x.CreatedAt, &x.NotifiedAt, &x.AcknowledgedBy, &x.AcknowledgedAt, &x.ResolvedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, x)
+	}
+	return out, rows.Err()
+}
+
+func (r *RiskRepo) Acknowledge(ctx context.Context, viewerID, alertID, ackBy int) error {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE risk_alerts a SET acknowledged_by = $3, acknowledged_at = now()
+		 WHERE a.id = $2 AND `+riskAlertVisibleTo,
+		viewerID, alertID, ackBy,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound("alert_not_found", "alert not found")
+	}
+	return nil
+}
+
+// ClaimDigest marks every open alert not yet notified as notified and
+// returns one entry per alert and recipient (the course teacher and the
+// student's advisor). Claiming and reading happen in one statement so two
+// API instances never send the same alert twice.
+func (r *RiskRepo) ClaimDigest(ctx context.Context) ([]model.DigestEntry, error) {
+	rows, err := r.db.Query(ctx,
+		`WITH claimed AS (
+			UPDATE risk_alerts SET notified_at = now()
+			WHERE notified_at IS NULL AND resolved_at IS NULL
+			RETURNING id, rule_id, course_id, student_id, detail
+		)
+		SELECT cl.id, rcp.id, rcp.email, c.title, s.full_name, r.name, cl.detail
+		FROM claimed cl
+		JOIN risk_rules r ON r.id = cl.rule_id
+		JOIN courses c ON c.id = cl.course_id
+		JOIN users s ON s.id = cl.student_id
+		JOIN LATERAL (
+			SELECT c.teacher_id AS uid
+			UNION
+			SELECT sa.advisor_id FROM student_advisors sa WHERE sa.student_id = cl.student_id
+		) x ON true
+		JOIN users rcp ON rcp.id = x.uid AND rcp.active
+		ORDER BY rcp.id, c.title, s.full_name`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.DigestEntry, 0)
+	for rows.Next() {
+		var d model.DigestEntry
+		if err := rows.Scan(&d.AlertID, &d.RecipientID, &d.RecipientEmail, &d.CourseTitle, &d.StudentName, &d.RuleName, &d.Detail); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
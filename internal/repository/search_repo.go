@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+
+	"lms-backend/internal/domain/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SearchRepo runs full-text queries with a trigram fallback. Every method
+// takes the raw query q, like, the same text as an ILIKE substring pattern,
+// and limit. Full-text matches rank by ts_rank, fuzzy ones by similarity.
+type SearchRepo struct{ db *pgxpool.Pool }
+
+func NewSearchRepo(db *pgxpool.Pool) *SearchRepo { return &SearchRepo{db: db} }
+
+// Users finds users by name, email or student number. teacherID > 0
+// narrows the result to students active in that teacher's courses.
+func (r *SearchRepo) Users(ctx context.Context, q, like string, teacherID, limit int) ([]model.SearchHit, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT u.id, u.full_name, u.email, ro.name,
+		        (ts_rank(u.search_tsv, websearch_to_tsquery('simple', $1))
+		          + greatest(similarity(u.full_name, $1), similarity(u.email, $1)))::float8 AS rank
+		 FROM users u
+		 JOIN roles ro ON ro.id = u.role_id
+		 LEFT JOIN user_profiles p ON p.user_id = u.id
+		 WHERE u.deleted_at IS NULL
+		   AND (u.search_tsv @@ websearch_to_tsquery('simple', $1)
+		        OR u.full_name % $1 OR u.email ILIKE $2 OR p.student_number = $1)
+		   AND ($3 = 0 OR EXISTS (
+		        SELECT 1 FROM enrollments e JOIN courses c ON c.id = e.course_id
+		        WHERE e.student_id = u.id AND e.status = 'active' AND c.teacher_id = $3))
+		 ORDER BY rank DESC, u.id DESC
+		 LIMIT $4`,
+		q, like, teacherID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectHits(rows, func(h *model.SearchHit) []any {
+		return []any{&h.ID, &h.Title, &h.Detail, &h.Role, &h.Rank}
+	})
+}
+
+// Courses finds courses by title or term; every course is listed to
+// everyone, so there is no scope.
+func (r *SearchRepo) Courses(ctx context.Context, q, like string, limit int) ([]model.SearchHit, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT c.id, c.title, c.term,
+		        (ts_rank(c.search_tsv, websearch_to_tsquery('simple', $1)) + similarity(c.title, $1))::float8 AS rank
+		 FROM courses c
+		 WHERE c.search_tsv @@ websearch_to_tsquery('simple', $1) OR c.title % $1 OR c.title ILIKE $2
+		 ORDER BY rank DESC, c.id DESC
+		 LIMIT $3`,
+		q, like, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectHits(rows, func(h *model.SearchHit) []any {
+		return []any{&h.ID, &h.Title, &h.Detail, &h.Rank}
+	})
+}
+
+// Materials finds course materials by title and text. studentID > 0 limits
+// them to courses the student is active in, teacherID > 0 to courses the
+// teacher teaches.
+func (r *SearchRepo) Materials(ctx context.Context, q, like string, studentID, teacherID, limit int) ([]model.SearchHit, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT m.id, m.title, c.title, m.course_id,
+		        ts_headline('english', m.body, websearch_to_tsquery('english', $1),
+		                    'StartSel=<b>, StopSel=</b>, MaxWords=30, MinWords=10, MaxFragments=1'),
+		        (ts_rank(m.search_tsv, websearch_to_tsquery('english', $1)) + similarity(m.title, $1))::float8 AS rank
+		 FROM course_materials m
+		 JOIN courses c ON c.id = m.course_id
+		 WHERE (m.search_tsv @@ websearch_to_tsquery('english', $1) OR m.title % $1 OR m.title ILIKE $2)
+		   AND ($3 = 0 OR EXISTS (
+		        SELECT 1 FROM enrollments e
+		        WHERE e.course_id = m.course_id AND e.student_id = $3 AND e.status = 'active'))
+		   AND ($4 = 0 OR c.teacher_id = $4)
+		 ORDER BY rank DESC, m.id DESC
+		 LIMIT $5`,
+		q, like, studentID, teacherID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectHits(rows, func(h *model.SearchHit) []any {
+		return []any{&h.ID, &h.Title, &h.Detail, &h.CourseID, &h.Snippet, &h.Rank}
+	})
+}
+
+func collectHits(rows pgx.Rows, dest func(*model.SearchHit) []any) ([]model.SearchHit, error) {
+	defer rows.Close()
+
+	out := make([]model.SearchHit, 0)
+	for rows.Next() {
+		var h model.SearchHit
+		if err := rows.Scan(dest(&h)...); err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// StreamTicketRepo stores the one-time tickets that open event streams, by
+// their hash.
+type StreamTicketRepo struct{ db *pgxpool.Pool }
+
+func NewStreamTicketRepo(db *pgxpool.Pool) *StreamTicketRepo { return &StreamTicketRepo{db: db} }
+
+// Create stores a ticket of the user valid for ttl and purges expired ones.
+func (r *StreamTicketRepo) Create(ctx context.Context, hash string, userID int, ttl time.Duration) error {
+	_, err := r.db.Exec(ctx,
+		`WITH gone AS (DELETE FROM stream_tickets WHERE expires_at <= now())
+		 INSERT INTO stream_tickets(ticket_hash, user_id, expires_at)
+		 VALUES ($1, $2, now() + $3 * interval '1 second')`,
+		hash, userID, int64(ttl/time.Second),
+	)
+	return err
+}
+
+// Redeem uses the ticket up and returns its user; pgx.ErrNoRows when the
+// ticket is unknown, used or expired.
+func (r *StreamTicketRepo) Redeem(ctx context.Context, hash string) (int, error) {
+	var userID int
+	err := r.db.QueryRow(ctx,
+		`DELETE FROM stream_tickets WHERE ticket_hash = $1 AND expires_at > now() RETURNING user_id`,
+		hash,
+	).Scan(&userID)
+	return userID, err
+}
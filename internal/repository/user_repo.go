@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 
+	"lms-backend/internal/apperr"
 	"lms-backend/internal/domain/model"
+	"lms-backend/internal/pagination"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -18,6 +21,10 @@ func NewUserRepo(db *pgxpool.Pool) *UserRepo {
 	return &UserRepo{db: db}
 }
 
+const userColumns = `id, email, password_hash, full_name, role_id, active, deleted_at, version`
+
+var ErrEmailTaken = apperr.Conflict("email_taken", "email already registered")
+
 func (r *UserRepo) Create(ctx context.Context, u model.User) (int, error) {
 	var id int
 	err := r.db.QueryRow(ctx,
@@ -28,50 +35,234 @@ func (r *UserRepo) Create(ctx context.Context, u model.User) (int, error) {
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			return 0, errors.New("email already registered")
+			return 0, ErrEmailTaken
 		}
 		return 0, err
 	}
 	return id, nil
 }
 
-func (r *UserRepo) List(ctx context.Context) ([]model.User, error) {
-	rows, err := r.db.Query(ctx, `SELECT id, email, password_hash, full_name, role_id FROM users ORDER BY id DESC LIMIT 200`)
+// UserListSpec is what GET /users accepts.
+var UserListSpec = &pagination.Spec{
+	ID: "u.id",
+	Sorts: map[string]pagination.Field{
+		"id":         {Column: "u.id", Type: "int"},
+		"full_name":  {Column: "u.full_name", Type: "text"},
+		"email":      {Column: "u.email", Type: "text"},
+		"created_at": {Column: "u.created_at", Type: "timestamptz"},
+	},
+	Default: "-id",
+	Filters: withFilters(userFilters, map[string]pagination.Filter{
+		"active":         {Cond: `u.active = $?`, Kind: pagination.Bool},
+		"created_after":  {Cond: `u.created_at >= $?`, Kind: pagination.Time},
+		"created_before": {Cond: `u.created_at < $?`, Kind: pagination.Time},
+	}),
+}
+
+// List returns one page of users that are not deleted and the cursor of the
+// next page.
+func (r *UserRepo) List(ctx context.Context, req pagination.Request) ([]model.User, string, error) {
+	q, err := UserListSpec.Query(req)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+	cond, args := q.Where(nil)
+	rows, err := r.db.Query(ctx,
+		`SELECT u.id, u.email, u.password_hash, u.full_name, u.role_id, u.active, u.deleted_at, u.version, u.created_at`+q.KeyColumn()+`
+		 FROM users u
+		 LEFT JOIN user_profiles p ON p.user_id = u.id
+		 WHERE u.deleted_at IS NULL`+cond+q.OrderLimit(),
+		args...,
+	)
+	if err != nil {
+		return nil, "", err
 	}
 	defer rows.Close()
 
 	out := make([]model.User, 0)
+	keys := make([]pagination.Key, 0)
 	for rows.Next() {
-		var u model.User
-		if err := rows.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.FullName, &u.RoleID); err != nil {
-			return nil, err
+		var (
+			u model.User
+			k pagination.Key
+		)
+		if err := rows.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.FullName, &u.RoleID, &u.Active, &u.DeletedAt, &u.Version, &u.CreatedAt, &k.Value); err != nil {
+			return nil, "", err
 		}
+		k.ID = u.ID
 		out = append(out, u)
+		keys = append(keys, k)
 	}
-	return out, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+	out, next := pagination.Trim(q, out, keys)
+	return out, next, nil
 }
 
 func (r *UserRepo) GetByEmail(ctx context.Context, email string) (model.User, error) {
 	var u model.User
 	err := r.db.QueryRow(ctx,
-		`SELECT id, email, password_hash, full_name, role_id FROM users WHERE email=$1`,
+		`SELECT `+userColumns+` FROM users WHERE email=$1`,
 		email,
-	).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.FullName, &u.RoleID)
+	).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.FullName, &u.RoleID, &u.Active, &u.DeletedAt, &u.Version)
 	return u, err
 }
 
 func (r *UserRepo) GetByID(ctx context.Context, id int) (model.User, error) {
 	var u model.User
 	err := r.db.QueryRow(ctx,
-		`SELECT id, email, password_hash, full_name, role_id FROM users WHERE id=$1`,
+		`SELECT `+userColumns+` FROM users WHERE id=$1`,
 		id,
-	).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.FullName, &u.RoleID)
+	).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.FullName, &u.RoleID, &u.Active, &u.DeletedAt, &u.Version)
 	return u, err
 }
 
-func (r *UserRepo) UpdateRole(ctx context.Context, userID int, roleID int) error {
-	_, err := r.db.Exec(ctx, `UPDATE users SET role_id=$1 WHERE id=$2`, roleID, userID)
-	return err
-}
\ No newline at end of file
+// UpdateRole changes the role of a user that has the given version, or any
+// version with AnyVersion, and returns the new version.
+func (r *UserRepo) UpdateRole(ctx context.Context, userID int, roleID int, version int) (int, error) {
+	err := r.db.QueryRow(ctx,
+		`UPDATE users SET role_id=$1 WHERE id=$2 AND ($3 = 0 OR version = $3) RETURNING version`,
+		roleID, userID, version,
+	).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, missedVersion(ctx, r.db, `SELECT version FROM users WHERE id=$1`, userID)
+	}
+	return version, err
+}
+// ListByEmails returns the registered users among emails.
+func (r *UserRepo) ListByEmails(ctx context.Context, emails []string) ([]model.User, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+userColumns+` FROM users WHERE email = ANY($1)`,
+		emails,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.User, 0)
+	for rows.Next() {
+		var u model.User
+		if err := rows.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.FullName, &u.RoleID, &u.Active, &u.DeletedAt, &u.Version); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// ListByIDs returns the users among ids, deleted ones included.
+func (r *UserRepo) ListByIDs(ctx context.Context, ids []int) ([]model.User, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+userColumns+`, created_at FROM users WHERE id = ANY($1)`,
+		ids,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]model.User, 0, len(ids))
+	for rows.Next() {
+		var u model.User
+		if err := rows.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.FullName, &u.RoleID, &u.Active, &u.DeletedAt, &u.Version, &u.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// Update applies the non-nil fields of p to a user that is not deleted and
+// has the given version (any with AnyVersion), and returns the new version.
+// It returns pgx.ErrNoRows when there is no such user and
+// ErrVersionMismatch when it has another version.
+func (r *UserRepo) Update(ctx context.Context, id int, p model.UserPatch, version int) (int, error) {
+	err := r.db.QueryRow(ctx,
+		`UPDATE users SET
+		   email         = COALESCE($2, email),
+		   full_name     = COALESCE($3, full_name),
+		   role_id       = COALESCE($4, role_id),
+		   active        = COALESCE($5, active),
+		   password_hash = COALESCE($6, password_hash)
+		 WHERE id = $1 AND deleted_at IS NULL AND ($7 = 0 OR version = $7)
+		 RETURNING version`,
+		id, p.Email, p.FullName, p.RoleID, p.Active, p.PasswordHash, version,
+	).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, missedVersion(ctx, r.db, `SELECT version FROM users WHERE id=$1 AND deleted_at IS NULL`, id)
+	}
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return 0, ErrEmailTaken
+		}
+		return 0, err
+	}
+	return version, nil
+}
+
+// IsActive reports whether the user exists, is not deleted and may log in.
+func (r *UserRepo) IsActive(ctx context.Context, id int) (bool, error) {
+	var active bool
+	err := r.db.QueryRow(ctx, `SELECT active FROM users WHERE id=$1`, id).Scan(&active)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return active, err
+}
+
+// SoftDelete anonymizes the user and drops their personal data (profile,
+// devices, advisor assignments) and withdraws their active enrollments. The
+// row itself stays, so attendance and enrollment history and the courses
+// they taught keep pointing at it. The
+// returned avatar path, if any, is for the caller to remove.
+func (r *UserRepo) SoftDelete(ctx context.Context, id int) (string, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE users SET
+		   email         = 'deleted-' || id || '@deleted.invalid',
+		   full_name     = 'Deleted user #' || id,
+		   password_hash = '!',
+		   active        = false,
+		   deleted_at    = now()
+		 WHERE id = $1 AND deleted_at IS NULL`,
+		id,
+	)
+	if err != nil {
+		return "", err
+	}
+	if tag.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
+	var avatar string
+	err = tx.QueryRow(ctx,
+		`SELECT COALESCE((SELECT avatar_path FROM user_profiles WHERE user_id = $1), '')`,
+		id,
+	).Scan(&avatar)
+	if err != nil {
+		return "", err
+	}
+
+	for _, q := range []string{
+		`DELETE FROM user_profiles WHERE user_id = $1`,
+		`DELETE FROM student_devices WHERE student_id = $1`,
+		`DELETE FROM student_advisors WHERE student_id = $1 OR advisor_id = $1`,
+		`DELETE FROM group_members WHERE student_id = $1`,
+		`UPDATE enrollments
+		 SET status = 'withdrawn', status_reason = 'account deleted', status_changed_by = NULL, status_changed_at = now()
+		 WHERE student_id = $1 AND status = 'active'`,
+	} {
+		if _, err := tx.Exec(ctx, q, id); err != nil {
+			return "", err
+		}
+	}
+	return avatar, tx.Commit(ctx)
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"lms-backend/internal/apperr"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Preconditions of conditional writes, besides the version the row must have.
+const (
+	AnyVersion   = 0  // write whatever is stored
+	NewOnly      = -1 // only create; fail if the row exists
+	KeepExisting = -2 // only create; leave an existing row alone
+)
+
+var (
+	ErrVersionMismatch = apperr.PreconditionFailed("version_mismatch", "it was changed in the meantime; reload it and try again")
+	ErrVersionRequired = apperr.PreconditionRequired("version_required", "it already exists; send the version (If-Match) you are changing")
+)
+
+// missedVersion explains why a conditional UPDATE touched no row: query
+// selects the row, which is gone (pgx.ErrNoRows) or has another version.
+func missedVersion(ctx context.Context, db *pgxpool.Pool, query string, args ...any) error {
+	var v int
+	if err := db.QueryRow(ctx, query, args...).Scan(&v); err != nil {
+		return err
+	}
+	return ErrVersionMismatch
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/notify"
+	"lms-backend/internal/repository"
+)
+
+type AnalyticsService struct {
+	attendance *repository.AttendanceRepo
+	risk       *repository.RiskRepo
+	users      *repository.UserRepo
+	roles      *repository.RoleRepo
+	mailer     *notify.Mailer
+}
+
+func NewAnalyticsService(attendance *repository.AttendanceRepo, risk *repository.RiskRepo, users *repository.UserRepo, roles *repository.RoleRepo, mailer *notify.Mailer) *AnalyticsService {
+	return &AnalyticsService{attendance: attendance, risk: risk, users: users, roles: roles, mailer: mailer}
+}
+
+// CourseAnalytics computes rates per student, per lesson and per week plus
+// absence streaks from every mark of the course.
+func (s *AnalyticsService) CourseAnalytics(ctx context.Context, courseID int) (model.CourseAnalytics, error) {
+	if courseID <= 0 {
+		return model.CourseAnalytics{}, apperr.Field("course_id", "must be > 0")
+	}
+	marks, err := s.attendance.ListMarks(ctx, courseID)
+	if err != nil {
+		return model.CourseAnalytics{}, err
+	}
+	return buildCourseAnalytics(courseID, marks), nil
+}
+
+// buildCourseAnalytics expects marks ordered by student and lesson date.
+func buildCourseAnalytics(courseID int, marks []model.AttendanceMark) model.CourseAnalytics {
+	out := model.CourseAnalytics{
+		CourseID: courseID,
+		Overall:  model.AttendanceSummary{CourseID: courseID, ByStatus: map[string]int{}},
+		Students: make([]model.StudentAttendanceStats, 0),
+		Lessons:  make([]model.LessonStats, 0),
+		Trend:    make([]model.TrendPoint, 0),
+	}
+	lessons := map[time.Time]*model.LessonStats{}
+	weeks := map[time.Time]*model.TrendPoint{}
+
+	var cur *model.StudentAttendanceStats
+	for _, m := range marks {
+		if cur == nil || cur.StudentID != m.StudentID {
+			out.Students = append(out.Students, model.StudentAttendanceStats{
+				AttendanceSummary: model.AttendanceSummary{CourseID: courseID, StudentID: m.StudentID, ByStatus: map[string]int{}},
+			})
+			cur = &out.Students[len(out.Students)-1]
+		}
+		addMark(&cur.AttendanceSummary, m)
+		addMark(&out.Overall, m)
+
+		switch m.CountsAs {
+		case "absent":
+			cur.CurrentAbsenceStreak++
+			if cur.CurrentAbsenceStreak > cur.LongestAbsenceStreak {
+				cur.LongestAbsenceStreak = cur.CurrentAbsenceStreak
+			}
+		case "present":
+			cur.CurrentAbsenceStreak = 0
+		}
+
+		l, ok := lessons[m.LessonDate]
+		if !ok {
+			l = &model.LessonStats{LessonDate: m.LessonDate, AttendanceSummary: model.AttendanceSummary{CourseID: courseID, ByStatus: map[string]int{}}}
+			lessons[m.LessonDate] = l
+		}
+		addMark(&l.AttendanceSummary, m)
+
+		ws := weekStart(m.LessonDate)
+		w, ok := weeks[ws]
+		if !ok {
+			w = &model.TrendPoint{WeekStart: ws, AttendanceSummary: model.AttendanceSummary{CourseID: courseID, ByStatus: map[string]int{}}}
+			weeks[ws] = w
+		}
+		addMark(&w.AttendanceSummary, m)
+	}
+
+	for _, l := range lessons {
+		out.Lessons = append(out.Lessons, *l)
+	}
+	sort.Slice(out.Lessons, func(i, j int) bool { return out.Lessons[i].LessonDate.Before(out.Lessons[j].LessonDate) })
+	for _, w := range weeks {
+		out.Trend = append(out.Trend, *w)
+	}
+	sort.Slice(out.Trend, func(i, j int) bool { return out.Trend[i].WeekStart.Before(out.Trend[j].WeekStart) })
+	return out
+}
+
+func addMark(sum *model.AttendanceSummary, m model.AttendanceMark) {
+	sum.Total++
+	sum.ByStatus[m.Status]++
+	switch m.CountsAs {
+	case "present":
+		sum.Attended++
+	case "absent":
+		sum.Absent++
+	case "excused":
+		sum.Excused++
+	}
+}
+
+// weekStart returns the Monday of t's week.
+func weekStart(t time.Time) time.Time {
+	d := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7))
+}
+
+func (s *AnalyticsService) ListRules(ctx context.Context) ([]model.RiskRule, error) {
+	return s.risk.ListRules(ctx, false)
+}
+
+func (s *AnalyticsService) CreateRule(ctx context.Context, r model.RiskRule) (int, error) {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return 0, apperr.Field("name", "is required")
+	}
+	switch r.Kind {
+	case "absence_streak":
+		if r.Threshold < 1 || r.Threshold != float64(int(r.Threshold)) {
+			return 0, apperr.Field("threshold", "must be a whole number of absences >= 1")
+		}
+	case "rate_below":
+		if r.Threshold <= 0 || r.Threshold > 1 {
+			return 0, apperr.Field("threshold", "must be a rate in (0,1]")
+		}
+	default:
+		return 0, apperr.Field("kind", "must be absence_streak|rate_below")
+	}
+	if r.MinLessons < 0 || r.CourseID < 0 {
+		return 0, apperr.Invalid("min_lessons and course_id must be >= 0")
+	}
+	return s.risk.CreateRule(ctx, r)
+}
+
+func (s *AnalyticsService) DeleteRule(ctx context.Context, id int) error {
+	if id <= 0 {
+		return apperr.Field("id", "must be > 0")
+	}
+	return s.risk.DeleteRule(ctx, id)
+}
+
+// SetAdvisor assigns a teacher or admin as a student's advisor (0 removes it).
+func (s *AnalyticsService) SetAdvisor(ctx context.Context, studentID, advisorID int) error {
+	if studentID <= 0 || advisorID < 0 {
+		return apperr.Invalid("student_id must be > 0 and advisor_id >= 0")
+	}
+	if advisorID > 0 {
+		u, err := s.users.GetByID(ctx, advisorID)
+		if err != nil {
+			return apperr.NotFound("advisor_not_found", "advisor not found")
+		}
+		role, err := s.roles.GetNameByID(ctx, u.RoleID)
+		if err != nil {
+			return err
+		}
+		if role != "teacher" && role != "admin" {
+			return apperr.Field("advisor_id", "must be a teacher or admin")
+		}
+	}
+	return s.risk.SetAdvisor(ctx, studentID, advisorID)
+}
+
+// Evaluate applies the active rules to one course and syncs its alerts.
+// It returns the number of newly flagged (rule, student) pairs.
+func (s *AnalyticsService) Evaluate(ctx context.Context, courseID int) (int, error) {
+	rules, err := s.risk.ListRules(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+	return s.evaluate(ctx, courseID, rules)
+}
+
+// EvaluateAll runs Evaluate for every course that has attendance.
+func (s *AnalyticsService) EvaluateAll(ctx context.Context) (int, error) {
+	rules, err := s.risk.ListRules(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+	ids, err := s.attendance.CoursesWithMarks(ctx)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, id := range ids {
+		n, err := s.evaluate(ctx, id, rules)
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+func (s *AnalyticsService) evaluate(ctx context.Context, courseID int, rules []model.RiskRule) (int, error) {
+	if courseID <= 0 {
+		return 0, apperr.Field("course_id", "must be > 0")
+	}
+	applicable := make([]model.RiskRule, 0, len(rules))
+	ruleIDs := make([]int, 0, len(rules))
+	for _, r := range rules {
+		if r.CourseID == 0 || r.CourseID == courseID {
+			applicable = append(applicable, r)
+			ruleIDs = append(ruleIDs, r.ID)
+		}
+	}
+
+	a, err := s.CourseAnalytics(ctx, courseID)
+	if err != nil {
+		return 0, err
+	}
+	flagged := make([]model.RiskAlert, 0)
+	for _, st := range a.Students {
+		for _, r := range applicable {
+			if alert, ok := checkRule(r, st); ok {
+				flagged = append(flagged, alert)
+			}
+		}
+	}
+	return s.risk.SyncAlerts(ctx, courseID, ruleIDs, flagged)
+}
+
+func checkRule(r model.RiskRule, st model.StudentAttendanceStats) (model.RiskAlert, bool) {
+	alert := model.RiskAlert{RuleID: r.ID, RuleName: r.Name, CourseID: st.CourseID, StudentID: st.StudentID}
+	switch r.Kind {
+	case "absence_streak":
+		if float64(st.CurrentAbsenceStreak) >= r.Threshold {
+			alert.Value = float64(st.CurrentAbsenceStreak)
+			alert.Detail = fmt.Sprintf("%d absences in a row", st.CurrentAbsenceStreak)
+			return alert, true
+		}
+	case "rate_below":
+		rate, ok := st.Rate()
+		if ok && st.Total-st.Excused >= r.MinLessons && rate < r.Threshold {
+			alert.Value = rate
+			alert.Detail = fmt.Sprintf("attendance %.0f%% (%d of %d lessons)", rate*100, st.Attended, st.Total-st.Excused)
+			return alert, true
+		}
+	}
+	return alert, false
+}
+
+// ListAlerts returns alerts the viewer may see: admins see everything,
+// teachers see their courses and their advisees.
+func (s *AnalyticsService) ListAlerts(ctx context.Context, viewerID int, role string, courseID int, includeResolved bool) ([]model.RiskAlert, error) {
+	if courseID < 0 {
+		return nil, apperr.Field("course_id", "must be >= 0")
+	}
+	if role == "admin" {
+		viewerID = 0
+	}
+	return s.risk.ListAlerts(ctx, viewerID, courseID, includeResolved)
+}
+
+func (s *AnalyticsService) Acknowledge(ctx context.Context, viewerID int, role string, alertID int) error {
+	if alertID <= 0 {
+		return apperr.Field("id", "must be > 0")
+	}
+	scope := viewerID
+	if role == "admin" {
+		scope = 0
+	}
+	return s.risk.Acknowledge(ctx, scope, alertID, viewerID)
+}
+
+// SendDigest emails every teacher/advisor the alerts raised since the last
+// digest. It returns the number of emails sent.
+func (s *AnalyticsService) SendDigest(ctx context.Context) (int, error) {
+	entries, err := s.risk.ClaimDigest(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := 0; i < len(entries); {
+		j := i
+		var b strings.Builder
+		b.WriteString("The following students were flagged as at risk:\n\n")
+		for ; j < len(entries) && entries[j].RecipientID == entries[i].RecipientID; j++ {
+			e := entries[j]
+			fmt.Fprintf(&b, "- %s, %s: %s (%s)\n", e.StudentName, e.CourseTitle, e.Detail, e.RuleName)
+		}
+		if err := s.mailer.Send(ctx, []string{entries[i].RecipientEmail}, "LMS: students at risk", b.String()); err != nil {
+			log.Printf("analytics: digest to %s: %v", entries[i].RecipientEmail, err)
+		} else {
+			sent++
+		}
+		i = j
+	}
+	return sent, nil
+}
+
+// RunDigest evaluates the rules and sends the digest right away and then
+// every interval until ctx is done, so a restart does not postpone it.
+func (s *AnalyticsService) RunDigest(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		s.digest(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (s *AnalyticsService) digest(ctx context.Context) {
+	if _, err := s.EvaluateAll(ctx); err != nil {
+		log.Println("analytics: evaluate error:", err)
+		return
+	}
+	n, err := s.SendDigest(ctx)
+	if err != nil {
+		log.Println("analytics: digest error:", err)
+		return
+	}
+	log.Printf("analytics: digest sent to %d recipients", n)
+}
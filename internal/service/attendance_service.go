@@ -4,45 +4,383 @@ import (
 	"context"
 	"errors"
 	"log"
+	"regexp"
+	"strings"
 	"time"
 
+	"lms-backend/internal/apperr"
 	"lms-backend/internal/domain/model"
+	"lms-backend/internal/pagination"
 	"lms-backend/internal/repository"
 )
 
 type AttendanceService struct {
-	repo *repository.AttendanceRepo
+	repo        *repository.AttendanceRepo
+	corrections *repository.AttendanceCorrectionRepo
+	statuses    *repository.AttendanceStatusRepo
+	excuses     *repository.AttendanceExcuseRepo
+	enrollments *repository.EnrollmentRepo
 }
 
-func NewAttendanceService(repo *repository.AttendanceRepo) *AttendanceService {
-	return &AttendanceService{repo: repo}
+func NewAttendanceService(
+	repo *repository.AttendanceRepo,
+	corrections *repository.AttendanceCorrectionRepo,
+	statuses *repository.AttendanceStatusRepo,
+	excuses *repository.AttendanceExcuseRepo,
+	enrollments *repository.EnrollmentRepo,
+) *AttendanceService {
+	return &AttendanceService{repo: repo, corrections: corrections, statuses: statuses, excuses: excuses, enrollments: enrollments}
 }
 
-func (s *AttendanceService) Mark(ctx context.Context, a model.Attendance) error {
-	switch a.Status {
-	case "present", "absent", "late":
-	default:
-		return errors.New("status must be present|absent|late")
+var statusCodeRe = regexp.MustCompile(`^[a-z][a-z0-9_]{1,31}$`)
+
+// ErrRollCallRejected is returned by MarkLesson when at least one row is
+// invalid; the per-row results say which, and nothing is saved.
+var ErrRollCallRejected = apperr.Unprocessable("roll_call_rejected", "some rows are invalid, nothing was saved")
+
+// ErrRollCallConflict is returned by MarkLesson when a row would change a mark
+// without naming its current version; those rows carry version_required or
+// version_mismatch and the current version, and nothing is saved.
+var ErrRollCallConflict = apperr.PreconditionFailed("roll_call_conflict", "some marks exist or were changed in the meantime, nothing was saved")
+
+const maxRollCallRows = 1000
+
+// Mark saves a mark on behalf of markedBy (the teacher/admin from the token)
+// and returns its version. An existing mark is only changed when it still has
+// version; with NewOnly the mark must not exist yet, with AnyVersion it is
+// overwritten. An absence on a date covered by an approved excuse is stored
+// as excused.
+func (s *AttendanceService) Mark(ctx context.Context, a model.Attendance, markedBy int, version int) (int, error) {
+	if a.Status == "" {
+		return 0, apperr.Field("status", "is required")
 	}
 	if a.CourseID <= 0 || a.StudentID <= 0 {
-		return errors.New("course_id and student_id must be > 0")
+		return 0, apperr.Invalid("course_id and student_id must be > 0")
 	}
 	if a.LessonDate.Equal((time.Time{})) {
-		return errors.New("lesson_date is required")
+		return 0, apperr.Field("lesson_date", "is required")
+	}
+
+	st, err := s.statuses.Get(ctx, a.Status)
+	if err != nil {
+		return 0, err
+	}
+	if st.CountsAs == "absent" {
+		covered, err := s.excuses.Covers(ctx, a.StudentID, a.CourseID, a.LessonDate)
+		if err != nil {
+			return 0, err
+		}
+		if covered {
+			a.Status = "excused"
+		}
+	}
+	return s.repo.Upsert(ctx, a, markedBy, version)
+}
+
+// MarkLesson saves a whole roll call for one lesson in a single transaction.
+// Rows in items are taken as given (StudentID, Status, Note); when
+// defaultStatus is set every other enrolled student without a mark gets it
+// ("everyone present except..."). Every student must be enrolled and every
+// status known, otherwise ErrRollCallRejected is returned together with
+// per-row errors. As with Mark, a row only changes an existing mark when its
+// Version is the mark's current one (0 only creates); otherwise
+// ErrRollCallConflict is returned with the rows at fault.
+func (s *AttendanceService) MarkLesson(ctx context.Context, courseID int, lessonDate time.Time, defaultStatus string, items []model.Attendance, markedBy int) ([]model.AttendanceMarkResult, error) {
+	if courseID <= 0 {
+		return nil, apperr.Field("course_id", "must be > 0")
+	}
+	if lessonDate.Equal((time.Time{})) {
+		return nil, apperr.Field("lesson_date", "is required")
+	}
+	if len(items) == 0 && defaultStatus == "" {
+		return nil, apperr.Invalid("items or default_status is required")
+	}
+
+	statuses, err := s.statuses.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	countsAs := make(map[string]string, len(statuses))
+	for _, st := range statuses {
+		countsAs[st.Code] = st.CountsAs
+	}
+	if _, ok := countsAs[defaultStatus]; defaultStatus != "" && !ok {
+		return nil, apperr.Field("default_status", "is unknown: "+defaultStatus)
+	}
+
+	ids, err := s.enrollments.EnrolledStudentIDs(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	enrolled := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		enrolled[id] = true
+	}
+	excused, err := s.excuses.CoveredStudents(ctx, courseID, lessonDate)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]model.Attendance, 0, len(ids))
+	results := make([]model.AttendanceMarkResult, 0, len(ids))
+	seen := map[int]bool{}
+	invalid := false
+	for _, a := range items {
+		res := model.AttendanceMarkResult{StudentID: a.StudentID, Status: a.Status}
+		switch _, known := countsAs[a.Status]; {
+		case a.StudentID <= 0:
+			res.Error = "student_id must be > 0"
+		case seen[a.StudentID]:
+			res.Error = "duplicate student_id"
+		case !enrolled[a.StudentID]:
+			res.Error = "student is not enrolled in this course"
+		case a.Status == "":
+			res.Error = "status is required"
+		case !known:
+			res.Error = "unknown attendance status: " + a.Status
+		case a.Version < 0:
+			res.Error = "version must be > 0"
+		}
+		seen[a.StudentID] = true
+		if res.Error != "" {
+			invalid = true
+		}
+		if a.Version == 0 {
+			a.Version = NewOnly
+		}
+		rows = append(rows, a)
+		results = append(results, res)
+	}
+	if defaultStatus != "" {
+		for _, id := range ids {
+			if seen[id] {
+				continue
+			}
+			rows = append(rows, model.Attendance{StudentID: id, Status: defaultStatus, Version: repository.KeepExisting})
+			results = append(results, model.AttendanceMarkResult{StudentID: id, Status: defaultStatus})
+		}
+	}
+	if len(rows) > maxRollCallRows {
+		return nil, apperr.Invalid("too many rows in one roll call")
+	}
+	if invalid {
+		return results, ErrRollCallRejected
+	}
+
+	for i := range rows {
+		rows[i].CourseID = courseID
+		rows[i].LessonDate = lessonDate
+		if countsAs[rows[i].Status] == "absent" && excused[rows[i].StudentID] {
+			rows[i].Status = "excused"
+			results[i].Status = "excused"
+		}
 	}
-	return s.repo.Upsert(ctx, a)
+
+	saved, err := s.repo.UpsertMany(ctx, rows, markedBy)
+	if err != nil {
+		return nil, err
+	}
+	conflict := false
+	for i, x := range saved {
+		results[i].Changed, results[i].Version = x.Changed, x.Version
+		var ae *apperr.Error
+		if errors.As(x.Err, &ae) {
+			results[i].Error, results[i].Code = ae.Message, ae.Code
+			conflict = true
+		}
+	}
+	if conflict {
+		return results, ErrRollCallConflict
+	}
+	return results, nil
 }
 
-func (s *AttendanceService) ListByCourse(ctx context.Context, courseID int) ([]model.Attendance, error) {
+func (s *AttendanceService) ListByCourse(ctx context.Context, courseID int, req pagination.Request) ([]model.Attendance, string, error) {
 	log.Printf("Listing attendance for course ID: %d", courseID)
 	if courseID <= 0 {
-		return nil, errors.New("course_id must be > 0")
+		return nil, "", apperr.Field("course_id", "must be > 0")
 	}
-	return s.repo.ListByCourse(ctx, courseID)
+	return s.repo.ListByCourse(ctx, courseID, req)
 }
-func (s *AttendanceService) ListByStudent(ctx context.Context, studentID int, courseID int) ([]model.Attendance, error) {
+func (s *AttendanceService) ListByStudent(ctx context.Context, studentID int, req pagination.Request) ([]model.Attendance, string, error) {
 	if studentID <= 0 {
-		return nil, errors.New("student_id must be > 0")
+		return nil, "", apperr.Field("student_id", "must be > 0")
+	}
+	return s.repo.ListByStudent(ctx, studentID, req)
+}
+
+// ListByCourses groups the marks of several courses between from and to
+// (zero = unbounded) by course; studentID > 0 keeps only that student's.
+func (s *AttendanceService) ListByCourses(ctx context.Context, courseIDs []int, studentID int, from, to time.Time) (map[int][]model.Attendance, error) {
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return nil, apperr.Field("to", "must not be before from")
+	}
+	items, err := s.repo.ListByCourses(ctx, courseIDs, studentID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	out := map[int][]model.Attendance{}
+	for _, a := range items {
+		out[a.CourseID] = append(out[a.CourseID], a)
+	}
+	return out, nil
+}
+
+func (s *AttendanceService) History(ctx context.Context, courseID int, studentID int) ([]model.AttendanceChange, error) {
+	if courseID <= 0 || studentID <= 0 {
+		return nil, apperr.Invalid("course_id and student_id must be > 0")
+	}
+	return s.repo.ListHistory(ctx, courseID, studentID)
+}
+
+// RequestCorrection is the student side of "I was there": it only records the
+// request, the mark itself changes when a teacher approves it.
+func (s *AttendanceService) RequestCorrection(ctx context.Context, c model.AttendanceCorrection) (int, error) {
+	c.Reason = strings.TrimSpace(c.Reason)
+	if c.RequestedStatus == "" {
+		c.RequestedStatus = "present"
+	}
+	switch c.RequestedStatus {
+	case "present", "late":
+	default:
+		return 0, apperr.Field("requested_status", "must be present|late")
+	}
+	if c.CourseID <= 0 || c.StudentID <= 0 {
+		return 0, apperr.Invalid("course_id and student_id must be > 0")
+	}
+	if c.LessonDate.Equal((time.Time{})) {
+		return 0, apperr.Field("lesson_date", "is required")
+	}
+	if c.LessonDate.After(time.Now()) {
+		return 0, apperr.Field("lesson_date", "cannot be in the future")
+	}
+	if c.Reason == "" {
+		return 0, apperr.Field("reason", "is required")
+	}
+
+	enrolled, err := s.enrollments.IsEnrolled(ctx, c.CourseID, c.StudentID)
+	if err != nil {
+		return 0, err
+	}
+	if !enrolled {
+		return 0, apperr.Forbidden("not_enrolled_in_course", "student is not enrolled in this course")
+	}
+	return s.corrections.Create(ctx, c)
+}
+
+// ListCorrections lists a course's requests; status may be "" for all.
+func (s *AttendanceService) ListCorrections(ctx context.Context, courseID int, status string) ([]model.AttendanceCorrection, error) {
+	if courseID <= 0 {
+		return nil, apperr.Field("course_id", "must be > 0")
+	}
+	switch status {
+	case "", "pending", "approved", "rejected":
+	default:
+		return nil, apperr.Field("status", "must be pending|approved|rejected")
+	}
+	return s.corrections.ListByCourse(ctx, courseID, status)
+}
+
+func (s *AttendanceService) MyCorrections(ctx context.Context, studentID int, courseID int) ([]model.AttendanceCorrection, error) {
+	if studentID <= 0 {
+		return nil, apperr.Field("student_id", "must be > 0")
+	}
+	return s.corrections.ListByStudent(ctx, studentID, courseID)
+}
+
+// ReviewCorrection approves or rejects a pending request. On approval the
+// attendance record is updated with the requested status.
+func (s *AttendanceService) ReviewCorrection(ctx context.Context, courseID, correctionID, reviewerID int, approve bool, comment string) (model.AttendanceCorrection, error) {
+	if courseID <= 0 || correctionID <= 0 {
+		return model.AttendanceCorrection{}, apperr.Invalid("course_id and correction_id must be > 0")
+	}
+	comment = strings.TrimSpace(comment)
+	if approve {
+		return s.corrections.Approve(ctx, courseID, correctionID, reviewerID, comment)
+	}
+	return s.corrections.Reject(ctx, courseID, correctionID, reviewerID, comment)
+}
+
+func (s *AttendanceService) ListStatuses(ctx context.Context) ([]model.AttendanceStatus, error) {
+	return s.statuses.List(ctx)
+}
+
+// CreateStatus adds an institution-specific status, e.g. {"sick", "Sick leave", "excused"}.
+func (s *AttendanceService) CreateStatus(ctx context.Context, st model.AttendanceStatus) error {
+	st.Code = strings.TrimSpace(strings.ToLower(st.Code))
+	st.Label = strings.TrimSpace(st.Label)
+	if !statusCodeRe.MatchString(st.Code) {
+		return apperr.Field("code", "must be 2-32 chars of a-z, 0-9, _ and start with a letter")
+	}
+	if st.Label == "" {
+		return apperr.Field("label", "is required")
+	}
+	switch st.CountsAs {
+	case "present", "absent", "excused":
+	default:
+		return apperr.Field("counts_as", "must be present|absent|excused")
+	}
+	return s.statuses.Create(ctx, st)
+}
+
+func (s *AttendanceService) DeleteStatus(ctx context.Context, code string) error {
+	code = strings.TrimSpace(strings.ToLower(code))
+	if code == "" {
+		return apperr.Field("code", "is required")
+	}
+	return s.statuses.Delete(ctx, code)
+}
+
+// CourseSummary returns one summary per student of the course.
+func (s *AttendanceService) CourseSummary(ctx context.Context, courseID int) ([]model.AttendanceSummary, error) {
+	if courseID <= 0 {
+		return nil, apperr.Field("course_id", "must be > 0")
+	}
+	tallies, err := s.repo.Tally(ctx, courseID, 0)
+	if err != nil {
+		return nil, err
+	}
+	return summarize(tallies), nil
+}
+
+// StudentSummary returns one summary per course the student has marks in
+// (only courseID's if it is > 0).
+func (s *AttendanceService) StudentSummary(ctx context.Context, studentID int, courseID int) ([]model.AttendanceSummary, error) {
+	if studentID <= 0 {
+		return nil, apperr.Field("student_id", "must be > 0")
+	}
+	tallies, err := s.repo.Tally(ctx, courseID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	return summarize(tallies), nil
+}
+
+// summarize folds tallies into one summary per (course, student) pair,
+// keeping the order in which pairs first appear.
+func summarize(tallies []model.AttendanceTally) []model.AttendanceSummary {
+	type key struct{ course, student int }
+	idx := map[key]int{}
+	out := make([]model.AttendanceSummary, 0)
+	for _, t := range tallies {
+		k := key{t.CourseID, t.StudentID}
+		i, ok := idx[k]
+		if !ok {
+			i = len(out)
+			idx[k] = i
+			out = append(out, model.AttendanceSummary{CourseID: t.CourseID, StudentID: t.StudentID, ByStatus: map[string]int{}})
+		}
+		sum := &out[i]
+		sum.Total += t.Count
+		sum.ByStatus[t.Status] += t.Count
+		switch t.CountsAs {
+		case "present":
+			sum.Attended += t.Count
+		case "absent":
+			sum.Absent += t.Count
+		case "excused":
+			sum.Excused += t.Count
+		}
 	}
-	return s.repo.ListByStudent(ctx, studentID, courseID)
+	return out
 }
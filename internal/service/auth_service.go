@@ -2,42 +2,57 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"strings"
 	"time"
 
+	"lms-backend/internal/apperr"
 	"lms-backend/internal/domain/model"
 	"lms-backend/internal/repository"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthService struct {
-	users  *repository.UserRepo
-	roles  *repository.RoleRepo
-	secret []byte
-	ttl    time.Duration
+	users   *repository.UserRepo
+	roles   *repository.RoleRepo
+	tickets *repository.StreamTicketRepo
+	secret  []byte
+	ttl     time.Duration
 }
 
+// streamTicketTTL is how long a stream ticket waits to be used.
+const streamTicketTTL = 30 * time.Second
+
+var (
+	ErrAccountDisabled = apperr.Unauthorized("account_disabled", "account is disabled")
+	ErrInvalidTicket   = apperr.Unauthorized("invalid_ticket", "unknown, used or expired stream ticket")
+)
+
 type Claims struct {
 	UserID int    `json:"user_id"`
 	Role   string `json:"role"`
 	jwt.RegisteredClaims
 }
 
-func NewAuthService(users *repository.UserRepo, roles *repository.RoleRepo, secret string, ttlMinutes int) *AuthService {
+func NewAuthService(users *repository.UserRepo, roles *repository.RoleRepo, tickets *repository.StreamTicketRepo, secret string, ttlMinutes int) *AuthService {
 	return &AuthService{
-		users:  users,
-		roles:  roles,
-		secret: []byte(secret),
-		ttl:    time.Duration(ttlMinutes) * time.Minute,
+		users:   users,
+		roles:   roles,
+		tickets: tickets,
+		secret:  []byte(secret),
+		ttl:     time.Duration(ttlMinutes) * time.Minute,
 	}
 }
 
 func (s *AuthService) HashPassword(password string) (string, error) {
 	if len(password) < 4 {
-		return "", errors.New("password too short (min 4)")
+		return "", apperr.Field("password", "must be at least 4 characters")
 	}
 	b, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	return string(b), err
@@ -48,7 +63,7 @@ func (s *AuthService) RegisterStudent(ctx context.Context, email, password, full
 	email = strings.TrimSpace(strings.ToLower(email))
 	fullName = strings.TrimSpace(fullName)
 	if email == "" || password == "" || fullName == "" {
-		return 0, errors.New("email, password, full_name required")
+		return 0, apperr.Invalid("email, password, full_name required")
 	}
 
 	roleID, err := s.roles.GetIDByName(ctx, "student")
@@ -76,16 +91,19 @@ func (s *AuthService) RegisterStudent(ctx context.Context, email, password, full
 func (s *AuthService) Login(ctx context.Context, email, password string) (string, error) {
 	email = strings.TrimSpace(strings.ToLower(email))
 	if email == "" || password == "" {
-		return "", errors.New("email and password required")
+		return "", apperr.Invalid("email and password required")
 	}
 
 	u, err := s.users.GetByEmail(ctx, email)
 	if err != nil {
-		return "", errors.New("invalid credentials")
+		return "", apperr.Unauthorized("invalid_credentials", "invalid credentials")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
-		return "", errors.New("invalid credentials")
+		return "", apperr.Unauthorized("invalid_credentials", "invalid credentials")
+	}
+	if !u.Active {
+		return "", ErrAccountDisabled
 	}
 
 	roleName, err := s.roles.GetNameByID(ctx, u.RoleID)
@@ -116,7 +134,67 @@ func (s *AuthService) Parse(tokenStr string) (*Claims, error) {
 	}
 	claims, ok := token.Claims.(*Claims)
 	if !ok || !token.Valid {
-		return nil, errors.New("invalid token")
+		return nil, apperr.Unauthorized("invalid_token", "invalid token")
 	}
 	return claims, nil
 }
+
+// Authenticate parses the token and checks that its user is still active,
+// so deactivating or deleting an account takes effect immediately.
+func (s *AuthService) Authenticate(ctx context.Context, tokenStr string) (*Claims, error) {
+	claims, err := s.Parse(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+	active, err := s.users.IsActive(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, ErrAccountDisabled
+	}
+	return claims, nil
+}
+
+// StreamTicket issues a ticket that opens one event stream for the user
+// within the returned lifetime.
+func (s *AuthService) StreamTicket(ctx context.Context, userID int) (string, time.Duration, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", 0, err
+	}
+	ticket := hex.EncodeToString(b)
+	if err := s.tickets.Create(ctx, ticketHash(ticket), userID, streamTicketTTL); err != nil {
+		return "", 0, err
+	}
+	return ticket, streamTicketTTL, nil
+}
+
+// RedeemStreamTicket uses the ticket up and returns the claims of its user
+// as they are now.
+func (s *AuthService) RedeemStreamTicket(ctx context.Context, ticket string) (*Claims, error) {
+	userID, err := s.tickets.Redeem(ctx, ticketHash(ticket))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvalidTicket
+	}
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !u.Active || u.DeletedAt != nil {
+		return nil, ErrAccountDisabled
+	}
+	role, err := s.roles.GetNameByID(ctx, u.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	return &Claims{UserID: userID, Role: role}, nil
+}
+
+func ticketHash(ticket string) string {
+	sum := sha256.Sum256([]byte(ticket))
+	return hex.EncodeToString(sum[:])
+}
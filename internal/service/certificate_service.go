@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/export"
+	"lms-backend/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrNotCompleted        = apperr.Conflict("course_not_completed", "the course has not been completed with a grade")
+	ErrCertificateNotFound = apperr.NotFound("certificate_not_found", "certificate not found")
+)
+
+// codeAlphabet leaves out 0/O and 1/I so codes can be read over the phone.
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// CertificateService issues signed completion certificates and verifies them.
+// The signature is an HMAC over the certificate's content, so a row edited
+// in the database no longer verifies.
+type CertificateService struct {
+	certs      *repository.CertificateRepo
+	attendance *repository.AttendanceRepo
+	secret     []byte
+	issuer     string
+	verifyURL  string
+}
+
+func NewCertificateService(certs *repository.CertificateRepo, attendance *repository.AttendanceRepo, secret, issuer, verifyURL string) *CertificateService {
+	return &CertificateService{
+		certs:      certs,
+		attendance: attendance,
+		secret:     []byte(secret),
+		issuer:     issuer,
+		verifyURL:  strings.TrimRight(verifyURL, "/") + "/",
+	}
+}
+
+// Issue returns the student's valid certificate for a completed course,
+// creating it on first use.
+func (s *CertificateService) Issue(ctx context.Context, courseID, studentID int) (model.Certificate, error) {
+	c, err := s.certs.Valid(ctx, courseID, studentID)
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return c, err
+	}
+
+	c, err = s.certs.Completion(ctx, courseID, studentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c, ErrNotCompleted
+	}
+	if err != nil {
+		return c, err
+	}
+	tallies, err := s.attendance.Tally(ctx, courseID, studentID)
+	if err != nil {
+		return c, err
+	}
+	if sums := summarize(tallies); len(sums) > 0 {
+		if r, ok := sums[0].Rate(); ok {
+			r = math.Round(r*10000) / 10000 // stored as NUMERIC(5,4)
+			c.AttendanceRate = &r
+		}
+	}
+
+	c.Code, err = newCertificateCode()
+	if err != nil {
+		return c, err
+	}
+	c.IssuedAt = time.Now().UTC().Truncate(time.Microsecond)
+	c.Signature = s.sign(c)
+	if c.ID, err = s.certs.Create(ctx, c); errors.Is(err, repository.ErrCertificateExists) {
+		return s.certs.Valid(ctx, courseID, studentID)
+	}
+	return c, err
+}
+
+func (s *CertificateService) ListByStudent(ctx context.Context, studentID int) ([]model.Certificate, error) {
+	return s.certs.ListByStudent(ctx, studentID)
+}
+
+// Verify looks a certificate up by code and reports whether its signature
+// still matches its content. Revoked certificates are returned as well.
+func (s *CertificateService) Verify(ctx context.Context, code string) (model.Certificate, bool, error) {
+	c, err := s.certs.GetByCode(ctx, normalizeCode(code))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c, false, ErrCertificateNotFound
+	}
+	if err != nil {
+		return c, false, err
+	}
+	return c, hmac.Equal([]byte(c.Signature), []byte(s.sign(c))), nil
+}
+
+func (s *CertificateService) Revoke(ctx context.Context, code, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return apperr.Field("reason", "is required")
+	}
+	err := s.certs.Revoke(ctx, normalizeCode(code), reason)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCertificateNotFound
+	}
+	return err
+}
+
+// WritePDF renders the certificate with its verification link.
+func (s *CertificateService) WritePDF(w io.Writer, c model.Certificate) error {
+	return export.WriteCertificate(w, c, s.issuer, s.VerifyURL(c.Code))
+}
+
+func (s *CertificateService) VerifyURL(code string) string {
+	return s.verifyURL + code
+}
+
+// sign is the hex HMAC-SHA256 of every field printed on the certificate.
+func (s *CertificateService) sign(c model.Certificate) string {
+	rate := ""
+	if c.AttendanceRate != nil {
+		rate = fmt.Sprintf("%.4f", *c.AttendanceRate)
+	}
+	payload := strings.Join([]string{
+		c.Code,
+		fmt.Sprint(c.EnrollmentID), fmt.Sprint(c.StudentID), fmt.Sprint(c.CourseID),
+		c.StudentName, c.CourseTitle, c.TeacherName, c.Term,
+		fmt.Sprintf("%.1f", c.Credits), fmt.Sprintf("%.2f", c.Grade), rate,
+		c.CompletedAt.UTC().Format(time.RFC3339Nano), c.IssuedAt.UTC().Format(time.RFC3339Nano),
+	}, "\n")
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newCertificateCode returns a random code like "K7QM-2XWD-HT9P".
+func newCertificateCode() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for i, v := range b {
+		if i > 0 && i%4 == 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteByte(codeAlphabet[int(v)%len(codeAlphabet)])
+	}
+	return sb.String(), nil
+}
+
+// normalizeCode accepts codes typed in lower case, with spaces or without
+// dashes.
+func normalizeCode(code string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		}
+	}
+	s := sb.String()
+	if len(s) != 12 {
+		return s
+	}
+	return s[:4] + "-" + s[4:8] + "-" + s[8:]
+}
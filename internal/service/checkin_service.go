@@ -0,0 +1,444 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math"
+	"net/netip"
+	"strings"
+	"time"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/repository"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	checkinCodePeriod    = 30 * time.Second
+	checkinCodeDigits    = 6
+	checkinMaxFailures   = 5
+	defaultCheckinWindow = 15 * time.Minute
+	defaultCheckinLate   = 10 * time.Minute
+)
+
+type CheckinService struct {
+	checkins    *repository.CheckinRepo
+	policies    *repository.CheckinPolicyRepo
+	devices     *repository.DeviceRepo
+	enrollments *repository.EnrollmentRepo
+}
+
+func NewCheckinService(checkins *repository.CheckinRepo, policies *repository.CheckinPolicyRepo, devices *repository.DeviceRepo, enrollments *repository.EnrollmentRepo) *CheckinService {
+	return &CheckinService{checkins: checkins, policies: policies, devices: devices, enrollments: enrollments}
+}
+
+// CheckinProof is what the client sends along with the code. Which parts are
+// required depends on the course's CheckinPolicy.
+type CheckinProof struct {
+	IP                string
+	Lat, Lon          *float64
+	DeviceFingerprint string
+}
+
+// CheckinCode is what the teacher's screen shows right now.
+type CheckinCode struct {
+	Session   model.CheckinSession
+	Code      string
+	ExpiresAt time.Time
+	CheckedIn int
+}
+
+// Open starts a check-in window for a lesson. Students checking in before
+// lateAfter are present, afterwards late; the window closes after duration.
+// Zero durations fall back to the defaults.
+func (s *CheckinService) Open(ctx context.Context, courseID, teacherID int, lessonDate time.Time, duration, lateAfter time.Duration) (model.CheckinSession, error) {
+	if courseID <= 0 {
+		return model.CheckinSession{}, apperr.Field("course_id", "must be > 0")
+	}
+	if duration == 0 {
+		duration = defaultCheckinWindow
+	}
+	if lateAfter == 0 {
+		lateAfter = defaultCheckinLate
+	}
+	if duration < 0 || lateAfter < 0 {
+		return model.CheckinSession{}, apperr.Field("durations", "must be > 0")
+	}
+	if lateAfter > duration {
+		lateAfter = duration
+	}
+
+	now := time.Now()
+	if lessonDate.Equal(time.Time{}) {
+		lessonDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return model.CheckinSession{}, err
+	}
+
+	sess := model.CheckinSession{
+		CourseID:   courseID,
+		LessonDate: lessonDate,
+		Secret:     secret,
+		OpenedBy:   teacherID,
+		OpenedAt:   now,
+		LateAfter:  now.Add(lateAfter),
+		ClosesAt:   now.Add(duration),
+	}
+	id, err := s.checkins.Open(ctx, sess)
+	if err != nil {
+		return model.CheckinSession{}, err
+	}
+	sess.ID = id
+	return sess, nil
+}
+
+// Current returns the code valid right now for the course's open window.
+func (s *CheckinService) Current(ctx context.Context, courseID int) (CheckinCode, error) {
+	if courseID <= 0 {
+		return CheckinCode{}, apperr.Field("course_id", "must be > 0")
+	}
+	sess, err := s.checkins.GetOpen(ctx, courseID)
+	if err != nil {
+		return CheckinCode{}, err
+	}
+	now := time.Now()
+	if !now.Before(sess.ClosesAt) {
+		return CheckinCode{}, apperr.Conflict("checkin_closed", "check-in window has ended")
+	}
+	n, err := s.checkins.CountCheckins(ctx, sess.ID)
+	if err != nil {
+		return CheckinCode{}, err
+	}
+
+	step := codeStep(now)
+	expires := time.Unix(0, 0).Add(time.Duration(step+1) * checkinCodePeriod)
+	if expires.After(sess.ClosesAt) {
+		expires = sess.ClosesAt
+	}
+	return CheckinCode{Session: sess, Code: checkinCode(sess.Secret, step), ExpiresAt: expires, CheckedIn: n}, nil
+}
+
+// QR renders the current code as a PNG. The payload carries the course so a
+// scanning app can submit it without asking.
+func (s *CheckinService) QR(ctx context.Context, courseID int, size int) ([]byte, error) {
+	cur, err := s.Current(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if size <= 0 || size > 1024 {
+		size = 256
+	}
+	payload := fmt.Sprintf("lms://checkin?course_id=%d&code=%s", courseID, cur.Code)
+	return qrcode.Encode(payload, qrcode.Medium, size)
+}
+
+// CheckIn marks the student present (or late after the threshold) if the
+// code matches the current or previous rotation step.
+// The course's policy (network, geofence, registered device) is enforced last.
+func (s *CheckinService) CheckIn(ctx context.Context, courseID, studentID int, code string, proof CheckinProof) (string, error) {
+	code = strings.TrimSpace(code)
+	if courseID <= 0 || studentID <= 0 {
+		return "", apperr.Invalid("course_id and student_id must be > 0")
+	}
+	if len(code) != checkinCodeDigits {
+		return "", apperr.Field("code", "is invalid")
+	}
+
+	sess, err := s.checkins.GetOpen(ctx, courseID)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	if !now.Before(sess.ClosesAt) {
+		return "", apperr.Conflict("checkin_closed", "check-in window has ended")
+	}
+
+	enrolled, err := s.enrollments.IsEnrolled(ctx, courseID, studentID)
+	if err != nil {
+		return "", err
+	}
+	if !enrolled {
+		return "", apperr.Forbidden("not_enrolled_in_course", "student is not enrolled in this course")
+	}
+
+	fails, err := s.checkins.Failures(ctx, sess.ID, studentID)
+	if err != nil {
+		return "", err
+	}
+	if fails >= checkinMaxFailures {
+		return "", apperr.Forbidden("too_many_attempts", "too many wrong codes, ask the teacher to mark you")
+	}
+
+	// accept the previous step too, so a code shown just before rotation still works
+	step := codeStep(now)
+	matched := int64(-1)
+	for _, st := range []int64{step, step - 1} {
+		if hmac.Equal([]byte(checkinCode(sess.Secret, st)), []byte(code)) {
+			matched = st
+			break
+		}
+	}
+	if matched < 0 {
+		if _, err := s.checkins.RecordFailure(ctx, sess.ID, studentID); err != nil {
+			return "", err
+		}
+		return "", apperr.Field("code", "is invalid or expired")
+	}
+
+	deviceHash := ""
+	if fp := strings.TrimSpace(proof.DeviceFingerprint); fp != "" {
+		deviceHash = fingerprintHash(fp)
+	}
+	if err := s.enforcePolicy(ctx, courseID, studentID, deviceHash, proof); err != nil {
+		return "", err
+	}
+
+	status := "present"
+	if now.After(sess.LateAfter) {
+		status = "late"
+	}
+	err = s.checkins.CheckIn(ctx, sess, model.Checkin{
+		SessionID:  sess.ID,
+		StudentID:  studentID,
+		CodeStep:   matched,
+		Status:     status,
+		DeviceHash: deviceHash,
+		IP:         proof.IP,
+		Lat:        proof.Lat,
+		Lon:        proof.Lon,
+	})
+	if err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+// Close ends the course's open window; enrolled students who did not check
+// in and have no mark yet become absent. Returns how many were marked.
+func (s *CheckinService) Close(ctx context.Context, courseID, closedBy int) (int, error) {
+	if courseID <= 0 {
+		return 0, apperr.Field("course_id", "must be > 0")
+	}
+	sess, err := s.checkins.GetOpen(ctx, courseID)
+	if err != nil {
+		return 0, err
+	}
+	return s.checkins.Close(ctx, sess.ID, closedBy)
+}
+
+// CloseExpired closes every window past its end time.
+func (s *CheckinService) CloseExpired(ctx context.Context) error {
+	ids, err := s.checkins.ListExpired(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		n, err := s.checkins.Close(ctx, id, 0)
+		if err != nil {
+			// another instance may have closed it first
+			log.Printf("checkin: close session %d: %v", id, err)
+			continue
+		}
+		log.Printf("checkin: closed session %d, %d marked absent", id, n)
+	}
+	return nil
+}
+
+// RunAutoClose calls CloseExpired every interval until ctx is done.
+func (s *CheckinService) RunAutoClose(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := s.CloseExpired(ctx); err != nil {
+				log.Println("checkin: auto-close error:", err)
+			}
+		}
+	}
+}
+
+func (s *CheckinService) enforcePolicy(ctx context.Context, courseID, studentID int, deviceHash string, proof CheckinProof) error {
+	p, err := s.policies.Get(ctx, courseID)
+	if err != nil {
+		return err
+	}
+
+	if len(p.AllowedNetworks) > 0 {
+		ip, err := netip.ParseAddr(proof.IP)
+		if err != nil {
+			return apperr.Forbidden("checkin_network", "check-in is only allowed from the campus network")
+		}
+		allowed := false
+		for _, n := range p.AllowedNetworks {
+			if pfx, err := netip.ParsePrefix(n); err == nil && pfx.Contains(ip.Unmap()) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return apperr.Forbidden("checkin_network", "check-in is only allowed from the campus network")
+		}
+	}
+
+	if p.RequireLocation {
+		if p.Room == nil {
+			// the room was deleted after the policy was saved
+			return apperr.Forbidden("checkin_room_missing", "check-in needs a location but the course has no room; ask the teacher to set one")
+		}
+		if proof.Lat == nil || proof.Lon == nil {
+			return apperr.Field("location", "is required to check in")
+		}
+		if distanceMeters(*proof.Lat, *proof.Lon, p.Room.Lat, p.Room.Lon) > float64(p.Room.RadiusM) {
+			return apperr.Forbidden("checkin_location", "you are not in the classroom")
+		}
+	}
+
+	if p.RequireDevice {
+		if deviceHash == "" {
+			return apperr.Invalid("device fingerprint is required to check in")
+		}
+		d, ok, err := s.devices.Get(ctx, studentID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return apperr.Forbidden("device_not_registered", "register your device before checking in")
+		}
+		if d.FingerprintHash != deviceHash {
+			return apperr.Forbidden("checkin_device", "check-in is only allowed from your registered device")
+		}
+	}
+	return nil
+}
+
+func (s *CheckinService) GetPolicy(ctx context.Context, courseID int) (model.CheckinPolicy, error) {
+	if courseID <= 0 {
+		return model.CheckinPolicy{}, apperr.Field("course_id", "must be > 0")
+	}
+	return s.policies.Get(ctx, courseID)
+}
+
+// SavePolicy replaces a course's policy. Networks are normalized CIDRs.
+func (s *CheckinService) SavePolicy(ctx context.Context, p model.CheckinPolicy) error {
+	if p.CourseID <= 0 {
+		return apperr.Field("course_id", "must be > 0")
+	}
+	if p.RoomID < 0 {
+		return apperr.Field("room_id", "must be >= 0")
+	}
+	if p.RequireLocation && p.RoomID == 0 {
+		return apperr.Field("room_id", "is required when require_location is set")
+	}
+	nets := make([]string, 0, len(p.AllowedNetworks))
+	for _, n := range p.AllowedNetworks {
+		pfx, err := netip.ParsePrefix(strings.TrimSpace(n))
+		if err != nil {
+			return apperr.Field("networks", fmt.Sprintf("has an invalid network %q, expected CIDR like 10.0.0.0/8", n))
+		}
+		nets = append(nets, pfx.Masked().String())
+	}
+	p.AllowedNetworks = nets
+	return s.policies.Save(ctx, p)
+}
+
+func (s *CheckinService) CreateRoom(ctx context.Context, room model.Room) (int, error) {
+	room.Name = strings.TrimSpace(room.Name)
+	if room.Name == "" {
+		return 0, apperr.Field("name", "is required")
+	}
+	if room.Lat < -90 || room.Lat > 90 || room.Lon < -180 || room.Lon > 180 {
+		return 0, apperr.Invalid("lat must be in [-90,90] and lon in [-180,180]")
+	}
+	if room.RadiusM <= 0 {
+		return 0, apperr.Field("radius_m", "must be > 0")
+	}
+	return s.policies.CreateRoom(ctx, room)
+}
+
+func (s *CheckinService) ListRooms(ctx context.Context) ([]model.Room, error) {
+	return s.policies.ListRooms(ctx)
+}
+
+// RegisterDevice binds the student's device. Only the hash of the
+// fingerprint is stored.
+func (s *CheckinService) RegisterDevice(ctx context.Context, studentID int, fingerprint, label string) error {
+	fingerprint = strings.TrimSpace(fingerprint)
+	if studentID <= 0 {
+		return apperr.Field("student_id", "must be > 0")
+	}
+	if len(fingerprint) < 8 {
+		return apperr.Field("fingerprint", "is too short")
+	}
+	return s.devices.Register(ctx, model.StudentDevice{
+		StudentID:       studentID,
+		FingerprintHash: fingerprintHash(fingerprint),
+		Label:           strings.TrimSpace(label),
+	})
+}
+
+func (s *CheckinService) GetDevice(ctx context.Context, studentID int) (model.StudentDevice, bool, error) {
+	return s.devices.Get(ctx, studentID)
+}
+
+// ResetDevice lets an admin unbind a lost or replaced device.
+func (s *CheckinService) ResetDevice(ctx context.Context, studentID int) error {
+	if studentID <= 0 {
+		return apperr.Field("student_id", "must be > 0")
+	}
+	return s.devices.Delete(ctx, studentID)
+}
+
+// FraudReport lists devices that checked in more than one student in a window.
+func (s *CheckinService) FraudReport(ctx context.Context, courseID int) ([]model.SharedDevice, error) {
+	if courseID <= 0 {
+		return nil, apperr.Field("course_id", "must be > 0")
+	}
+	return s.checkins.SharedDevices(ctx, courseID)
+}
+
+func fingerprintHash(fp string) string {
+	sum := sha256.Sum256([]byte(fp))
+	return hex.EncodeToString(sum[:])
+}
+
+// distanceMeters is the haversine distance between two points.
+func distanceMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371000.0
+	rad := func(d float64) float64 { return d * math.Pi / 180 }
+	dLat := rad(lat2 - lat1)
+	dLon := rad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(rad(lat1))*math.Cos(rad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+func codeStep(t time.Time) int64 {
+	return t.Unix() / int64(checkinCodePeriod/time.Second)
+}
+
+// checkinCode is a TOTP-style (RFC 6238) numeric code for one step.
+func checkinCode(secret []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	off := sum[len(sum)-1] & 0x0f
+	v := binary.BigEndian.Uint32(sum[off:off+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", checkinCodeDigits, v%1000000)
+}
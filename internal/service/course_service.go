@@ -5,73 +5,229 @@ import (
 	"errors"
 	"strings"
 
+	"lms-backend/internal/apperr"
 	"lms-backend/internal/domain/model"
+	"lms-backend/internal/pagination"
 	"lms-backend/internal/repository"
+
+	"github.com/jackc/pgx/v5"
 )
 
+var ErrEnrollmentNotFound = apperr.NotFound("enrollment_not_found", "no enrollment that can change to this status")
+
 type CourseService struct {
 	courses     *repository.CourseRepo
 	enrollments *repository.EnrollmentRepo
+	reqs        *repository.RequirementRepo
+	attendance  *repository.AttendanceRepo
 }
 
-func NewCourseService(courses *repository.CourseRepo, enrollments *repository.EnrollmentRepo) *CourseService {
-	return &CourseService{courses: courses, enrollments: enrollments}
+func NewCourseService(courses *repository.CourseRepo, enrollments *repository.EnrollmentRepo, reqs *repository.RequirementRepo, attendance *repository.AttendanceRepo) *CourseService {
+	return &CourseService{courses: courses, enrollments: enrollments, reqs: reqs, attendance: attendance}
 }
 
 func (s *CourseService) Create(ctx context.Context, c model.Course) (int, error) {
 	c.Title = strings.TrimSpace(c.Title)
 	if c.Title == "" {
-		return 0, errors.New("title is required")
+		return 0, apperr.Field("title", "is required")
 	}
 	if c.TeacherID <= 0 {
-		return 0, errors.New("teacher_id must be > 0")
+		return 0, apperr.Field("teacher_id", "must be > 0")
+	}
+	c.Term = strings.TrimSpace(c.Term)
+	if c.Credits < 0 || c.Credits > 999 {
+		return 0, apperr.Field("credits", "must be 0..999")
 	}
 	return s.courses.Create(ctx, c)
 }
 
-func (s *CourseService) List(ctx context.Context) ([]model.Course, error) {
-	return s.courses.List(ctx)
+func (s *CourseService) List(ctx context.Context, req pagination.Request) ([]model.Course, string, error) {
+	return s.courses.List(ctx, req)
 }
 
-func (s *CourseService) ListByTeacher(ctx context.Context, teacherID int) ([]model.Course, error) {
+func (s *CourseService) ListByTeacher(ctx context.Context, teacherID int, req pagination.Request) ([]model.Course, string, error) {
 	if teacherID <= 0 {
-		return nil, errors.New("teacher_id must be > 0")
+		return nil, "", apperr.Field("teacher_id", "must be > 0")
 	}
-	return s.courses.ListByTeacher(ctx, teacherID)
+	return s.courses.ListByTeacher(ctx, teacherID, req)
 }
 
-func (s *CourseService) ListByStudent(ctx context.Context, studentID int) ([]model.Course, error) {
+// ListByStudent returns the student's courses in an enrollment status
+// ("" = active).
+func (s *CourseService) ListByStudent(ctx context.Context, studentID int, status string, req pagination.Request) ([]model.Course, string, error) {
 	if studentID <= 0 {
-		return nil, errors.New("student_id must be > 0")
+		return nil, "", apperr.Field("student_id", "must be > 0")
 	}
-	return s.enrollments.ListCoursesByStudent(ctx, studentID)
+	if err := validEnrollmentStatus(status); err != nil {
+		return nil, "", err
+	}
+	return s.enrollments.ListCoursesByStudent(ctx, studentID, status, req)
 }
 
-func (s *CourseService) Enroll(ctx context.Context, courseID int, studentID int) error {
+// Enroll adds a student to a course. Prerequisites, co-requisites and the
+// timetable are checked first and reported as a *RequirementsError, unless
+// override is set. A course the student completed or failed stays closed.
+func (s *CourseService) Enroll(ctx context.Context, courseID, studentID, actorID int, override bool) error {
 	if courseID <= 0 || studentID <= 0 {
-		return errors.New("course_id and student_id must be > 0")
+		return apperr.Invalid("course_id and student_id must be > 0")
+	}
+	if !override {
+		if err := checkRequirements(ctx, s.reqs, courseID, studentID); err != nil {
+			return err
+		}
 	}
-	return s.enrollments.Enroll(ctx, courseID, studentID)
+	return s.enrollments.Enroll(ctx, courseID, studentID, actorID)
 }
 
-func (s *CourseService) Unenroll(ctx context.Context, courseID int, studentID int) error {
+// Unenroll withdraws a student; waitlisted students move up into the free seat.
+func (s *CourseService) Unenroll(ctx context.Context, courseID, studentID, actorID int, reason string) ([]int, error) {
+	return s.SetEnrollmentStatus(ctx, courseID, studentID, model.EnrollmentChange{
+		Status: "withdrawn", Reason: reason, ChangedBy: actorID,
+	})
+}
+
+// SetEnrollmentStatus records that a student dropped, was withdrawn, or
+// finished the course. Grades of finished courses can be corrected; a
+// dropped or withdrawn student comes back through Enroll. It returns the
+// students promoted from the waitlist.
+func (s *CourseService) SetEnrollmentStatus(ctx context.Context, courseID, studentID int, ch model.EnrollmentChange) ([]int, error) {
 	if courseID <= 0 || studentID <= 0 {
-		return errors.New("course_id and student_id must be > 0")
+		return nil, apperr.Invalid("course_id and student_id must be > 0")
+	}
+	ch.Reason = strings.TrimSpace(ch.Reason)
+
+	var from []string
+	switch ch.Status {
+	case "dropped", "withdrawn":
+		from = []string{"active"}
+		ch.Grade = nil
+	case "completed", "failed":
+		from = []string{"active", "completed", "failed"}
+		if ch.Grade != nil && (*ch.Grade < 0 || *ch.Grade > 100) {
+			return nil, apperr.Field("grade", "must be 0..100")
+		}
+		if ch.Status == "completed" && ch.Grade == nil {
+			return nil, apperr.Field("grade", "is required to complete a course")
+		}
+	default:
+		return nil, apperr.Field("status", "must be dropped|withdrawn|completed|failed")
+	}
+
+	promoted, err := s.enrollments.SetStatus(ctx, courseID, studentID, ch, from)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrEnrollmentNotFound
+	}
+	return promoted, err
+}
+
+// Transcript lists the courses the student has been in ("" = all statuses),
+// with attendance rate and status history.
+func (s *CourseService) Transcript(ctx context.Context, studentID int, status string) ([]model.TranscriptEntry, error) {
+	if studentID <= 0 {
+		return nil, apperr.Field("student_id", "must be > 0")
+	}
+	if err := validEnrollmentStatus(status); err != nil {
+		return nil, err
+	}
+	all, err := s.enrollments.Transcript(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	tallies, err := s.attendance.Tally(ctx, 0, studentID)
+	if err != nil {
+		return nil, err
+	}
+	rates := map[int]float64{}
+	for _, sum := range summarize(tallies) {
+		if r, ok := sum.Rate(); ok {
+			rates[sum.CourseID] = r
+		}
+	}
+
+	out := make([]model.TranscriptEntry, 0, len(all))
+	for _, x := range all {
+		if status != "" && x.Status != status {
+			continue
+		}
+		if r, ok := rates[x.CourseID]; ok {
+			x.AttendanceRate = &r
+		}
+		out = append(out, x)
 	}
-	return s.enrollments.Unenroll(ctx, courseID, studentID)
+	return out, nil
 }
 
 
-func (s *CourseService) GetStudents(ctx context.Context, courseID int) ([]model.User, error) {
+// GetStudents returns the roster ("" = active students) or the students in
+// another enrollment status.
+func (s *CourseService) GetStudents(ctx context.Context, courseID int, status string, req pagination.Request) ([]model.User, string, error) {
 	if courseID <= 0 {
-		return nil, errors.New("course_id must be > 0")
+		return nil, "", apperr.Field("course_id", "must be > 0")
+	}
+	if err := validEnrollmentStatus(status); err != nil {
+		return nil, "", err
+	}
+	return s.enrollments.ListEnrolledStudents(ctx, courseID, status, req)
+}
+
+// GetMany returns the courses among ids by id.
+func (s *CourseService) GetMany(ctx context.Context, ids []int) (map[int]model.Course, error) {
+	courses, err := s.courses.ListByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int]model.Course, len(courses))
+	for _, c := range courses {
+		out[c.ID] = c
+	}
+	return out, nil
+}
+
+// EnrollmentsByCourses groups the enrollments in a status ("" = active) of
+// several courses by course.
+func (s *CourseService) EnrollmentsByCourses(ctx context.Context, courseIDs []int, status string) (map[int][]model.Enrollment, error) {
+	if err := validEnrollmentStatus(status); err != nil {
+		return nil, err
+	}
+	items, err := s.enrollments.ListByCourses(ctx, courseIDs, status)
+	if err != nil {
+		return nil, err
 	}
-	return s.enrollments.ListEnrolledStudents(ctx, courseID)
+	out := map[int][]model.Enrollment{}
+	for _, e := range items {
+		out[e.CourseID] = append(out[e.CourseID], e)
+	}
+	return out, nil
+}
+
+// EnrollmentsByStudents groups the enrollments in a status ("" = active) of
+// several students by student.
+func (s *CourseService) EnrollmentsByStudents(ctx context.Context, studentIDs []int, status string) (map[int][]model.Enrollment, error) {
+	if err := validEnrollmentStatus(status); err != nil {
+		return nil, err
+	}
+	items, err := s.enrollments.ListByStudents(ctx, studentIDs, status)
+	if err != nil {
+		return nil, err
+	}
+	out := map[int][]model.Enrollment{}
+	for _, e := range items {
+		out[e.StudentID] = append(out[e.StudentID], e)
+	}
+	return out, nil
 }
 
-func (s *CourseService) GetAvailableStudents(ctx context.Context, courseID int) ([]model.User, error) {
+func (s *CourseService) GetAvailableStudents(ctx context.Context, courseID int, req pagination.Request) ([]model.User, string, error) {
 	if courseID <= 0 {
-		return nil, errors.New("course_id must be > 0")
+		return nil, "", apperr.Field("course_id", "must be > 0")
 	}
-	return s.enrollments.ListAvailableStudents(ctx, courseID)
-}
\ No newline at end of file
+	return s.enrollments.ListAvailableStudents(ctx, courseID, req)
+}
+
+func validEnrollmentStatus(status string) error {
+	switch status {
+	case "", "active", "dropped", "withdrawn", "completed", "failed":
+		return nil
+	}
+	return apperr.Field("status", "must be active|dropped|withdrawn|completed|failed")
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrEnrollmentClosed = apperr.Forbidden("enrollment_closed", "this course is not open for enrollment")
+	ErrEnrollmentKey    = apperr.Forbidden("wrong_enrollment_key", "wrong enrollment key")
+	ErrRequestNotFound  = apperr.NotFound("request_not_found", "enrollment request not found")
+	ErrNotEnrolled      = apperr.NotFound("not_enrolled", "not enrolled and no open request")
+	ErrAlreadyEnrolled  = repository.ErrAlreadyEnrolled
+	ErrRequestExists    = repository.ErrRequestExists
+	ErrGroupEnrollment  = repository.ErrGroupEnrollment
+)
+
+var enrollmentPolicies = map[string]bool{"closed": true, "open": true, "approval": true, "key": true}
+
+// EnrollmentService handles student self-service enrollment: policies,
+// capacity, the waitlist and teacher approval.
+type EnrollmentService struct {
+	requests *repository.EnrollmentRequestRepo
+	courses  *repository.CourseRepo
+	reqs     *repository.RequirementRepo
+}
+
+func NewEnrollmentService(requests *repository.EnrollmentRequestRepo, courses *repository.CourseRepo, reqs *repository.RequirementRepo) *EnrollmentService {
+	return &EnrollmentService{requests: requests, courses: courses, reqs: reqs}
+}
+
+func (s *EnrollmentService) Policy(ctx context.Context, courseID int) (model.EnrollmentPolicy, error) {
+	if err := s.courseExists(ctx, courseID); err != nil {
+		return model.EnrollmentPolicy{}, err
+	}
+	return s.requests.GetPolicy(ctx, courseID)
+}
+
+// SavePolicy validates and stores the policy; key is the plain enrollment key
+// (empty keeps the current one). Raising or removing the capacity promotes
+// waitlisted students, whose ids are returned.
+func (s *EnrollmentService) SavePolicy(ctx context.Context, p model.EnrollmentPolicy, key string) ([]int, error) {
+	if !enrollmentPolicies[p.Policy] {
+		return nil, apperr.Field("policy", "must be closed|open|approval|key")
+	}
+	if p.Capacity < 0 {
+		return nil, apperr.Field("capacity", "must be >= 0 (0 = unlimited)")
+	}
+	if p.OpensAt != nil && p.ClosesAt != nil && !p.ClosesAt.After(*p.OpensAt) {
+		return nil, apperr.Field("closes_at", "must be after opens_at")
+	}
+	current, err := s.Policy(ctx, p.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Policy == "key" {
+		switch {
+		case key != "":
+			if len(key) < 4 {
+				return nil, apperr.Field("key", "must be at least 4 characters")
+			}
+			hash, err := bcrypt.GenerateFromPassword([]byte(key), bcrypt.DefaultCost)
+			if err != nil {
+				return nil, err
+			}
+			p.KeyHash = string(hash)
+		case current.KeyHash == "":
+			return nil, apperr.Field("key", "is required for policy key")
+		}
+	}
+	return s.requests.SavePolicy(ctx, p)
+}
+
+// Request lets a student join a course according to its policy and the
+// course requirements. The result says whether they were enrolled, are pending approval or waitlisted.
+func (s *EnrollmentService) Request(ctx context.Context, courseID, studentID int, key string) (model.EnrollmentRequest, error) {
+	p, err := s.Policy(ctx, courseID)
+	if err != nil {
+		return model.EnrollmentRequest{}, err
+	}
+	now := time.Now()
+	if p.Policy == "closed" ||
+		(p.OpensAt != nil && now.Before(*p.OpensAt)) ||
+		(p.ClosesAt != nil && !now.Before(*p.ClosesAt)) {
+		return model.EnrollmentRequest{}, ErrEnrollmentClosed
+	}
+	if p.Policy == "key" && bcrypt.CompareHashAndPassword([]byte(p.KeyHash), []byte(key)) != nil {
+		return model.EnrollmentRequest{}, ErrEnrollmentKey
+	}
+	if err := checkRequirements(ctx, s.reqs, courseID, studentID); err != nil {
+		return model.EnrollmentRequest{}, err
+	}
+	return s.requests.Submit(ctx, courseID, studentID, p.Policy == "approval")
+}
+
+// Drop cancels the student's open request or removes their own enrollment;
+// the freed seat goes to the first student on the waitlist.
+func (s *EnrollmentService) Drop(ctx context.Context, courseID, studentID int) (string, []int, error) {
+	status, promoted, err := s.requests.Drop(ctx, courseID, studentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil, ErrNotEnrolled
+	}
+	return status, promoted, err
+}
+
+func (s *EnrollmentService) ListRequests(ctx context.Context, courseID int, status string) ([]model.EnrollmentRequest, error) {
+	switch status {
+	case "", "pending", "waitlisted", "enrolled", "rejected", "cancelled":
+	default:
+		return nil, apperr.Field("status", "must be pending|waitlisted|enrolled|rejected|cancelled")
+	}
+	return s.requests.ListByCourse(ctx, courseID, status)
+}
+
+func (s *EnrollmentService) MyRequests(ctx context.Context, studentID int) ([]model.EnrollmentRequest, error) {
+	return s.requests.ListByStudent(ctx, studentID)
+}
+
+// Review approves or rejects a request. Approving enrolls the student, or
+// puts them on the waitlist when the course is full.
+func (s *EnrollmentService) Review(ctx context.Context, courseID, requestID, reviewerID int, approve bool, comment string) (model.EnrollmentRequest, error) {
+	var (
+		req model.EnrollmentRequest
+		err error
+	)
+	if approve {
+		req, err = s.requests.Approve(ctx, courseID, requestID, reviewerID, comment)
+	} else {
+		err = s.requests.Reject(ctx, courseID, requestID, reviewerID, comment)
+		req = model.EnrollmentRequest{ID: requestID, CourseID: courseID, Status: "rejected", Comment: comment}
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return req, ErrRequestNotFound
+	}
+	return req, err
+}
+
+func (s *EnrollmentService) courseExists(ctx context.Context, courseID int) error {
+	if courseID <= 0 {
+		return apperr.Field("course_id", "must be > 0")
+	}
+	_, err := s.courses.GetByID(ctx, courseID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCourseNotFound
+	}
+	return err
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/realtime"
+	"lms-backend/internal/repository"
+)
+
+// maxEventTopics bounds the courses one connection may follow.
+const maxEventTopics = 50
+
+var ErrNotCourseStudent = apperr.Forbidden("not_enrolled_in_course", "student is not enrolled in this course")
+
+// EventService opens real-time subscriptions to course topics. Staff may
+// follow any course; students only courses they are active in, and they
+// only receive the events about themselves.
+type EventService struct {
+	hub         *realtime.Hub
+	courses     *repository.CourseRepo
+	enrollments *repository.EnrollmentRepo
+}
+
+func NewEventService(hub *realtime.Hub, courses *repository.CourseRepo, enrollments *repository.EnrollmentRepo) *EventService {
+	return &EventService{hub: hub, courses: courses, enrollments: enrollments}
+}
+
+// Subscribe opens a subscription for the user and joins courseIDs.
+func (s *EventService) Subscribe(ctx context.Context, userID int, role string, courseIDs []int) (*realtime.Subscription, error) {
+	if len(courseIDs) > maxEventTopics {
+		return nil, apperr.Field("course_id", "at most 50 courses")
+	}
+	studentID := 0
+	if role == "student" {
+		studentID = userID
+	}
+	sub := s.hub.Subscribe(studentID)
+	for _, id := range courseIDs {
+		if err := s.Join(ctx, sub, userID, role, id); err != nil {
+			sub.Close()
+			return nil, err
+		}
+	}
+	return sub, nil
+}
+
+// Join adds a course to an open subscription after checking the user may
+// follow it.
+func (s *EventService) Join(ctx context.Context, sub *realtime.Subscription, userID int, role string, courseID int) error {
+	if courseID <= 0 {
+		return apperr.Field("course_id", "must be > 0")
+	}
+	if len(sub.Courses()) >= maxEventTopics {
+		return apperr.Field("course_id", "at most 50 courses")
+	}
+	exists, err := s.courses.ExistingIDs(ctx, []int{courseID})
+	if err != nil {
+		return err
+	}
+	if !exists[courseID] {
+		return ErrCourseNotFound
+	}
+	if role == "student" {
+		ok, err := s.enrollments.IsEnrolled(ctx, courseID, userID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrNotCourseStudent
+		}
+	}
+	sub.Join(courseID)
+	return nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/repository"
+	"lms-backend/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrExcuseNotFound  = apperr.NotFound("excuse_not_found", "excuse request not found")
+	ErrExcuseAdminOnly = apperr.Forbidden("excuse_admin_only", "an excuse for all courses can only be reviewed by an admin")
+)
+
+// documentTypes maps accepted justification document types to file extensions.
+var documentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+type ExcuseService struct {
+	excuses     *repository.AttendanceExcuseRepo
+	enrollments *repository.EnrollmentRepo
+	files       *storage.LocalStore
+	maxBytes    int64
+}
+
+func NewExcuseService(excuses *repository.AttendanceExcuseRepo, enrollments *repository.EnrollmentRepo, files *storage.LocalStore, maxUploadMB int) *ExcuseService {
+	return &ExcuseService{excuses: excuses, enrollments: enrollments, files: files, maxBytes: int64(maxUploadMB) << 20}
+}
+
+// Document is an uploaded file attached to a request.
+type Document struct {
+	Name    string
+	Size    int64
+	Content io.Reader
+}
+
+// Submit stores a student's excuse request together with an optional document.
+func (s *ExcuseService) Submit(ctx context.Context, e model.AttendanceExcuse, doc *Document) (int, error) {
+	e.Reason = strings.TrimSpace(e.Reason)
+	if e.StudentID <= 0 {
+		return 0, apperr.Field("student_id", "must be > 0")
+	}
+	if e.CourseID < 0 {
+		return 0, apperr.Field("course_id", "must be >= 0")
+	}
+	if e.DateFrom.Equal(time.Time{}) || e.DateTo.Equal(time.Time{}) {
+		return 0, apperr.Invalid("date_from and date_to are required")
+	}
+	if e.DateTo.Before(e.DateFrom) {
+		return 0, apperr.Field("date_to", "must not be before date_from")
+	}
+	if e.Reason == "" {
+		return 0, apperr.Field("reason", "is required")
+	}
+	if e.CourseID > 0 {
+		enrolled, err := s.enrollments.IsEnrolled(ctx, e.CourseID, e.StudentID)
+		if err != nil {
+			return 0, err
+		}
+		if !enrolled {
+			return 0, apperr.Forbidden("not_enrolled_in_course", "student is not enrolled in this course")
+		}
+	}
+
+	if doc != nil {
+		if doc.Size > s.maxBytes {
+			return 0, apperr.New(apperr.KindInvalid, "file_too_large", "document is too large")
+		}
+		br := bufio.NewReader(doc.Content)
+		head, _ := br.Peek(512)
+		ctype := http.DetectContentType(head)
+		ext, ok := documentTypes[ctype]
+		if !ok {
+			return 0, apperr.Field("document", "must be a PDF, JPEG or PNG")
+		}
+		path, err := s.files.Save("excuses", ext, br, s.maxBytes)
+		if err != nil {
+			return 0, err
+		}
+		e.DocumentPath, e.DocumentName, e.DocumentType = path, doc.Name, ctype
+	}
+
+	id, err := s.excuses.Create(ctx, e)
+	if err != nil && e.DocumentPath != "" {
+		_ = s.files.Remove(e.DocumentPath)
+	}
+	return id, err
+}
+
+func (s *ExcuseService) ListMine(ctx context.Context, studentID int) ([]model.AttendanceExcuse, error) {
+	if studentID <= 0 {
+		return nil, apperr.Field("student_id", "must be > 0")
+	}
+	return s.excuses.ListByStudent(ctx, studentID)
+}
+
+// List returns requests for reviewers. status may be "" and courseID 0 for all.
+func (s *ExcuseService) List(ctx context.Context, status string, courseID int) ([]model.AttendanceExcuse, error) {
+	switch status {
+	case "", "pending", "approved", "rejected":
+	default:
+		return nil, apperr.Field("status", "must be pending|approved|rejected")
+	}
+	if courseID < 0 {
+		return nil, apperr.Field("course_id", "must be >= 0")
+	}
+	return s.excuses.List(ctx, status, courseID)
+}
+
+func (s *ExcuseService) Get(ctx context.Context, id int) (model.AttendanceExcuse, error) {
+	if id <= 0 {
+		return model.AttendanceExcuse{}, apperr.Field("id", "must be > 0")
+	}
+	e, err := s.excuses.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return e, ErrExcuseNotFound
+	}
+	return e, err
+}
+
+// OpenDocument opens the file attached to e. The caller closes it.
+func (s *ExcuseService) OpenDocument(e model.AttendanceExcuse) (*os.File, error) {
+	if e.DocumentPath == "" {
+		return nil, apperr.NotFound("no_document", "no document attached")
+	}
+	return s.files.Open(e.DocumentPath)
+}
+
+// Review approves or rejects a pending excuse. On approval every absence in
+// the range becomes excused; converted is the number of records changed.
+// Excuses without a course cover every course of the student, so only admins
+// review them.
+func (s *ExcuseService) Review(ctx context.Context, id, reviewerID int, role string, approve bool, comment string) (e model.AttendanceExcuse, converted int, err error) {
+	if id <= 0 {
+		return model.AttendanceExcuse{}, 0, apperr.Field("id", "must be > 0")
+	}
+	if role != "admin" {
+		x, err := s.Get(ctx, id)
+		if err != nil {
+			return x, 0, err
+		}
+		if x.CourseID == 0 {
+			return model.AttendanceExcuse{}, 0, ErrExcuseAdminOnly
+		}
+	}
+	comment = strings.TrimSpace(comment)
+	if approve {
+		return s.excuses.Approve(ctx, id, reviewerID, comment)
+	}
+	e, err = s.excuses.Reject(ctx, id, reviewerID, comment)
+	return e, 0, err
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrGroupNotFound  = apperr.NotFound("group_not_found", "group not found")
+	ErrGroupNameTaken = repository.ErrGroupNameTaken
+)
+
+type GroupService struct {
+	groups      *repository.GroupRepo
+	courses     *repository.CourseRepo
+	enrollments *repository.EnrollmentRepo
+	reqs        *repository.RequirementRepo
+}
+
+func NewGroupService(groups *repository.GroupRepo, courses *repository.CourseRepo, enrollments *repository.EnrollmentRepo, reqs *repository.RequirementRepo) *GroupService {
+	return &GroupService{groups: groups, courses: courses, enrollments: enrollments, reqs: reqs}
+}
+
+func (s *GroupService) List(ctx context.Context) ([]model.StudentGroup, error) {
+	return s.groups.List(ctx)
+}
+
+// Get returns the group and its members.
+func (s *GroupService) Get(ctx context.Context, id int) (model.StudentGroup, []model.User, error) {
+	g, err := s.groups.Get(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return g, nil, ErrGroupNotFound
+	}
+	if err != nil {
+		return g, nil, err
+	}
+	members, err := s.groups.Members(ctx, id)
+	return g, members, err
+}
+
+func (s *GroupService) Create(ctx context.Context, g model.StudentGroup) (int, error) {
+	if err := normalizeGroup(&g); err != nil {
+		return 0, err
+	}
+	return s.groups.Create(ctx, g)
+}
+
+func (s *GroupService) Update(ctx context.Context, g model.StudentGroup) error {
+	if err := normalizeGroup(&g); err != nil {
+		return err
+	}
+	return groupErr(s.groups.Update(ctx, g))
+}
+
+func (s *GroupService) Delete(ctx context.Context, id int) error {
+	return groupErr(s.groups.Delete(ctx, id))
+}
+
+func normalizeGroup(g *model.StudentGroup) error {
+	g.Name = strings.TrimSpace(g.Name)
+	g.Faculty = strings.TrimSpace(g.Faculty)
+	if g.Name == "" || len(g.Name) > 50 {
+		return apperr.Field("name", "is required (max 50 characters)")
+	}
+	if g.Year < 0 || g.Year > 10 {
+		return apperr.Field("year", "must be between 1 and 10")
+	}
+	return nil
+}
+
+// AddMembers moves students into the group; they leave the courses of their
+// previous group and join the courses of this one. The requirements of those
+// courses are checked for every student and reported as a *RequirementsError,
+// unless override is set.
+func (s *GroupService) AddMembers(ctx context.Context, groupID int, studentIDs []int, actorID int, override bool) (model.GroupSync, error) {
+	if len(studentIDs) == 0 {
+		return model.GroupSync{}, apperr.Field("student_ids", "must not be empty")
+	}
+	bad, err := s.groups.NonStudents(ctx, studentIDs)
+	if err != nil {
+		return model.GroupSync{}, err
+	}
+	if len(bad) > 0 {
+		return model.GroupSync{}, apperr.Field("student_ids", fmt.Sprintf("contains users that are not active students: %v", bad))
+	}
+	if !override {
+		courseIDs, err := s.groups.CourseIDs(ctx, groupID)
+		if err != nil {
+			return model.GroupSync{}, err
+		}
+		if err := checkGroupRequirements(ctx, s.reqs, groupID, courseIDs, studentIDs); err != nil {
+			return model.GroupSync{}, err
+		}
+	}
+	res, err := s.groups.AddMembers(ctx, groupID, studentIDs, actorID)
+	return res, groupErr(err)
+}
+
+func (s *GroupService) RemoveMember(ctx context.Context, groupID, studentID, actorID int) (model.GroupSync, error) {
+	res, err := s.groups.RemoveMember(ctx, groupID, studentID, actorID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return res, apperr.NotFound("not_group_member", "student is not in this group")
+	}
+	return res, err
+}
+
+func (s *GroupService) CourseGroups(ctx context.Context, courseID int) ([]model.StudentGroup, error) {
+	return s.groups.CourseGroups(ctx, courseID)
+}
+
+// EnrollGroup enrolls the whole group in a course. Students who join the
+// group later are enrolled automatically. Unless override is set, every
+// member must meet the course requirements; the ones who do not are
+// reported as a *RequirementsError.
+func (s *GroupService) EnrollGroup(ctx context.Context, courseID, groupID, actorID int, override bool) (model.GroupSync, error) {
+	if _, err := s.courses.GetByID(ctx, courseID); errors.Is(err, pgx.ErrNoRows) {
+		return model.GroupSync{}, ErrCourseNotFound
+	} else if err != nil {
+		return model.GroupSync{}, err
+	}
+	if _, err := s.groups.Get(ctx, groupID); err != nil {
+		return model.GroupSync{}, groupErr(err)
+	}
+	if !override {
+		members, err := s.groups.EnrollableMembers(ctx, groupID)
+		if err != nil {
+			return model.GroupSync{}, err
+		}
+		if err := checkGroupRequirements(ctx, s.reqs, groupID, []int{courseID}, members); err != nil {
+			return model.GroupSync{}, err
+		}
+	}
+	return s.groups.EnrollGroup(ctx, courseID, groupID, actorID)
+}
+
+// UnenrollGroup removes the group from the course; students who were also
+// enrolled individually stay. Enrolled counts students promoted from the
+// waitlist into the freed seats.
+func (s *GroupService) UnenrollGroup(ctx context.Context, courseID, groupID, actorID int) (model.GroupSync, error) {
+	res, err := s.groups.UnenrollGroup(ctx, courseID, groupID, actorID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return res, apperr.NotFound("group_not_enrolled", "group is not enrolled in this course")
+	}
+	if err != nil {
+		return res, err
+	}
+	// the freed seats go to the waitlist
+	promoted, err := s.enrollments.PromoteWaitlist(ctx, courseID)
+	res.Enrolled = len(promoted)
+	return res, err
+}
+
+func groupErr(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrGroupNotFound
+	}
+	return err
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/repository"
+)
+
+// MaxIdempotentBody is the largest response that is stored for replay.
+const MaxIdempotentBody = 1 << 20
+
+var (
+	ErrIdempotencyKeyInvalid = apperr.Field("Idempotency-Key", "must be 1-255 visible ASCII characters")
+	ErrIdempotencyKeyReused  = apperr.Unprocessable("idempotency_key_reused", "this Idempotency-Key was used for a different request")
+	ErrIdempotencyInProgress = apperr.Conflict("idempotency_in_progress", "a request with this Idempotency-Key is still running")
+)
+
+// IdempotencyService remembers the response to the first request sent with an
+// Idempotency-Key, so a client retrying after a lost response does not create
+// a second course or mark. Keys are per user and kept for ttl. Requests are
+// hashed whole, so their bodies may be at most the upload limit plus 1 MB for
+// the other form fields.
+type IdempotencyService struct {
+	repo    *repository.IdempotencyRepo
+	ttl     time.Duration
+	maxBody int64
+}
+
+func NewIdempotencyService(repo *repository.IdempotencyRepo, ttl time.Duration, maxUploadMB int) *IdempotencyService {
+	return &IdempotencyService{repo: repo, ttl: ttl, maxBody: int64(maxUploadMB+1) << 20}
+}
+
+// MaxRequestBody is the largest request body sent with an Idempotency-Key.
+func (s *IdempotencyService) MaxRequestBody() int64 { return s.maxBody }
+
+// ErrRequestTooLarge answers a body over MaxRequestBody.
+func (s *IdempotencyService) ErrRequestTooLarge() error {
+	return apperr.TooLarge("request_too_large", fmt.Sprintf("request body is larger than %d MB", s.maxBody>>20))
+}
+
+// Begin claims key for the request with the given hash. It returns nil when
+// the request should run and then be passed to Finish, or the stored response
+// of an earlier run to replay.
+func (s *IdempotencyService) Begin(ctx context.Context, userID int, key, hash string) (*model.IdempotentResponse, error) {
+	if !validIdempotencyKey(key) {
+		return nil, ErrIdempotencyKeyInvalid
+	}
+	stored, reserved, err := s.repo.Reserve(ctx, userID, key, hash, s.ttl)
+	if err != nil || reserved {
+		return nil, err
+	}
+	if stored.RequestHash != hash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if stored.Status == 0 {
+		return nil, ErrIdempotencyInProgress
+	}
+	return &stored, nil
+}
+
+// Finish stores the response of a request begun with Begin. Server errors are
+// not stored; the key is released so a retry runs again. The body must be at
+// most MaxIdempotentBody.
+func (s *IdempotencyService) Finish(ctx context.Context, resp model.IdempotentResponse) error {
+	if resp.Status >= 500 {
+		return s.Release(ctx, resp.UserID, resp.Key, resp.RequestHash)
+	}
+	return s.repo.Complete(ctx, resp)
+}
+
+// Release frees a key claimed by Begin without storing a response.
+func (s *IdempotencyService) Release(ctx context.Context, userID int, key, hash string) error {
+	return s.repo.Release(ctx, userID, key, hash)
+}
+
+// RunPurge deletes expired keys every interval until ctx is done.
+func (s *IdempotencyService) RunPurge(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if _, err := s.repo.DeleteExpired(ctx); err != nil {
+				log.Println("idempotency: purge error:", err)
+			}
+		}
+	}
+}
+
+func validIdempotencyKey(key string) bool {
+	if key == "" || len(key) > 255 {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < '!' || key[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
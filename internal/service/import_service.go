@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"log"
+	"net/mail"
+	"sort"
+	"strconv"
+	"strings"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/notify"
+	"lms-backend/internal/repository"
+	"lms-backend/internal/sheet"
+)
+
+// ErrImportRejected is returned when the roster has invalid rows; the report
+// lists them and nothing is saved.
+var ErrImportRejected = apperr.Unprocessable("import_rejected", "some rows are invalid, nothing was imported")
+
+const maxImportRows = 5000
+
+// importColumns maps accepted header names to the canonical column.
+var importColumns = map[string]string{
+	"email":      "email",
+	"e-mail":     "email",
+	"full_name":  "full_name",
+	"name":       "full_name",
+	"role":       "role",
+	"password":   "password",
+	"courses":    "courses",
+	"course_ids": "courses",
+	"course_id":  "courses",
+}
+
+type ImportService struct {
+	imports  *repository.ImportRepo
+	users    *repository.UserRepo
+	roles    *repository.RoleRepo
+	courses  *repository.CourseRepo
+	reqs     *repository.RequirementRepo
+	auth     *AuthService
+	mailer   *notify.Mailer
+	maxBytes int64
+}
+
+func NewImportService(
+	imports *repository.ImportRepo,
+	users *repository.UserRepo,
+	roles *repository.RoleRepo,
+	courses *repository.CourseRepo,
+	reqs *repository.RequirementRepo,
+	auth *AuthService,
+	mailer *notify.Mailer,
+	maxUploadMB int,
+) *ImportService {
+	return &ImportService{
+		imports: imports, users: users, roles: roles, courses: courses, reqs: reqs,
+		auth: auth, mailer: mailer, maxBytes: int64(maxUploadMB) << 20,
+	}
+}
+
+type ImportOptions struct {
+	DryRun      bool
+	SendInvites bool
+	Override    bool // enroll even when course requirements are not met
+	ActorID     int  // the importing admin
+}
+
+// ImportRoster reads a CSV/XLSX roster with the columns email, full_name,
+// role, password and courses (course ids separated by ";" or spaces), and
+// creates or updates the users and their enrollments in one transaction.
+// Only email is required; new users default to the student role and get a
+// generated password when none is given. Unless opts.Override, students must
+// meet the requirements of their courses; failures are row errors.
+func (s *ImportService) ImportRoster(ctx context.Context, filename string, r io.Reader, opts ImportOptions) (model.ImportReport, error) {
+	report := model.ImportReport{DryRun: opts.DryRun, Errors: []model.ImportError{}, Results: []model.ImportResult{}}
+
+	data, err := io.ReadAll(io.LimitReader(r, s.maxBytes+1))
+	if err != nil {
+		return report, err
+	}
+	if int64(len(data)) > s.maxBytes {
+		return report, apperr.New(apperr.KindInvalid, "file_too_large", fmt.Sprintf("file is larger than %d MB", s.maxBytes>>20))
+	}
+	table, err := sheet.Read(filename, data)
+	if err != nil {
+		return report, apperr.New(apperr.KindInvalid, "invalid_file", err.Error())
+	}
+	if len(table) < 2 {
+		return report, apperr.Invalid("file has no data rows")
+	}
+	if len(table)-1 > maxImportRows {
+		return report, apperr.Invalid(fmt.Sprintf("at most %d rows per import", maxImportRows))
+	}
+
+	cols := map[string]int{}
+	for i, h := range table[0] {
+		if name, ok := importColumns[strings.ToLower(strings.TrimSpace(h))]; ok {
+			cols[name] = i
+		}
+	}
+	if _, ok := cols["email"]; !ok {
+		return report, apperr.Invalid("header row must contain an email column")
+	}
+
+	rows, errs, total, err := s.parseRoster(ctx, table, cols, !opts.Override)
+	if err != nil {
+		return report, err
+	}
+	report.Rows = total
+	report.Errors = errs
+	if len(errs) > 0 && !opts.DryRun {
+		return report, ErrImportRejected
+	}
+
+	for i := range rows {
+		if opts.DryRun {
+			// rolled back anyway, skip the bcrypt cost
+			if rows[i].PasswordHash != "" || !rows[i].Exists {
+				rows[i].PasswordHash = "dry-run"
+			}
+			continue
+		}
+		if rows[i].PasswordHash == "" && !rows[i].Exists {
+			rows[i].GeneratedPassword = randomPassword()
+			rows[i].PasswordHash = rows[i].GeneratedPassword
+		}
+		if rows[i].PasswordHash != "" {
+			if rows[i].PasswordHash, err = s.auth.HashPassword(rows[i].PasswordHash); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	results, err := s.imports.Apply(ctx, rows, opts.DryRun, opts.ActorID)
+	if err != nil {
+		return report, err
+	}
+	report.Results = results
+	for _, res := range results {
+		switch res.Action {
+		case "created":
+			report.Created++
+		case "updated":
+			report.Updated++
+		default:
+			report.Unchanged++
+		}
+		report.Enrollments += len(res.Enrolled)
+	}
+
+	if opts.SendInvites && !opts.DryRun {
+		report.Invited = s.invite(rows, results)
+	}
+	return report, nil
+}
+
+// parseRoster validates every line and resolves roles, courses and existing
+// users; with checkReqs it also checks the course requirements of each
+// student. Rows with errors are left out of the returned slice.
+func (s *ImportService) parseRoster(ctx context.Context, table [][]string, cols map[string]int, checkReqs bool) ([]model.ImportRow, []model.ImportError, int, error) {
+	roles, err := s.roles.List(ctx)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	roleIDs := map[string]int{}
+	for _, r := range roles {
+		roleIDs[r.Name] = r.ID
+	}
+
+	cell := func(row []string, name string) string {
+		i, ok := cols[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	type parsed struct {
+		row   model.ImportRow
+		role  string
+		valid bool
+	}
+	var (
+		lines     []parsed
+		errs      = []model.ImportError{}
+		emails    []string
+		courseIDs []int
+		seen      = map[string]int{}
+	)
+	for i, raw := range table[1:] {
+		line := i + 2 // 1-based, after the header
+		if isBlankRow(raw) {
+			continue
+		}
+		bad := func(field, msg string) {
+			errs = append(errs, model.ImportError{Line: line, Field: field, Message: msg})
+		}
+		before := len(errs)
+
+		p := parsed{row: model.ImportRow{
+			Line:     line,
+			Email:    strings.ToLower(cell(raw, "email")),
+			FullName: cell(raw, "full_name"),
+		}}
+		if a, err := mail.ParseAddress(p.row.Email); err != nil || a.Address != p.row.Email {
+			bad("email", "invalid email")
+		} else if first, dup := seen[p.row.Email]; dup {
+			bad("email", fmt.Sprintf("duplicate of line %d", first))
+		} else {
+			seen[p.row.Email] = line
+		}
+
+		if p.role = strings.ToLower(cell(raw, "role")); p.role != "" {
+			if id, ok := roleIDs[p.role]; ok {
+				p.row.RoleID = id
+			} else {
+				bad("role", "unknown role "+strconv.Quote(p.role))
+			}
+		}
+
+		if pw := cell(raw, "password"); pw != "" {
+			if len(pw) < 6 {
+				bad("password", "password must be at least 6 characters")
+			}
+			p.row.PasswordHash = pw // hashed once the whole file is valid
+		}
+
+		for _, tok := range strings.FieldsFunc(cell(raw, "courses"), func(r rune) bool { return r == ';' || r == ',' || r == ' ' }) {
+			id, err := strconv.Atoi(tok)
+			if err != nil || id <= 0 {
+				bad("courses", "invalid course id "+strconv.Quote(tok))
+				continue
+			}
+			p.row.CourseIDs = append(p.row.CourseIDs, id)
+			courseIDs = append(courseIDs, id)
+		}
+
+		if p.valid = len(errs) == before; p.valid {
+			emails = append(emails, p.row.Email)
+		}
+		lines = append(lines, p)
+	}
+
+	existing, err := s.users.ListByEmails(ctx, emails)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	users := map[string]model.User{}
+	for _, u := range existing {
+		users[u.Email] = u
+	}
+	courses, err := s.courses.ExistingIDs(ctx, courseIDs)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	out := make([]model.ImportRow, 0, len(lines))
+	for _, p := range lines {
+		if !p.valid {
+			continue
+		}
+		line := p.row.Line
+		before := len(errs)
+		bad := func(field, msg string) {
+			errs = append(errs, model.ImportError{Line: line, Field: field, Message: msg})
+		}
+
+		u, exists := users[p.row.Email]
+		p.row.Exists = exists
+		if !exists && p.row.FullName == "" {
+			bad("full_name", "full_name is required for new users")
+		}
+		role := p.role
+		if role == "" {
+			role = "student"
+			if exists {
+				for name, id := range roleIDs {
+					if id == u.RoleID {
+						role = name
+					}
+				}
+			}
+		}
+		for _, id := range p.row.CourseIDs {
+			if !courses[id] {
+				bad("courses", fmt.Sprintf("course %d does not exist", id))
+			}
+		}
+		if len(p.row.CourseIDs) > 0 && role != "student" {
+			bad("courses", "only students can be enrolled")
+		}
+		if checkReqs && len(errs) == before {
+			// u is the zero user for new emails: no history, year or group
+			for _, id := range p.row.CourseIDs {
+				unmet, err := unmetRules(ctx, s.reqs, id, u.ID)
+				if err != nil {
+					return nil, nil, 0, err
+				}
+				if len(unmet) > 0 {
+					msgs := make([]string, 0, len(unmet))
+					for _, x := range unmet {
+						msgs = append(msgs, x.Message)
+					}
+					bad("courses", fmt.Sprintf("course %d: %s", id, strings.Join(msgs, "; ")))
+				}
+			}
+		}
+		if len(errs) == before {
+			out = append(out, p.row)
+		}
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Line < errs[j].Line })
+	return out, errs, len(lines), nil
+}
+
+// invite mails the newly created users in the background and returns how
+// many messages were queued.
+func (s *ImportService) invite(rows []model.ImportRow, results []model.ImportResult) int {
+	byLine := map[int]model.ImportRow{}
+	for _, r := range rows {
+		byLine[r.Line] = r
+	}
+	var queue []model.ImportRow
+	for _, res := range results {
+		if res.Action == "created" {
+			queue = append(queue, byLine[res.Line])
+		}
+	}
+	go func() {
+		for _, r := range queue {
+			body := fmt.Sprintf("Hello %s,\n\nan account has been created for you.\nLogin: %s\n", r.FullName, r.Email)
+			if r.GeneratedPassword != "" {
+				body += "Temporary password: " + r.GeneratedPassword + "\n"
+			} else {
+				body += "Use the password given to you by the administrator.\n"
+			}
+			if err := s.mailer.Send(context.Background(), []string{r.Email}, "Your LMS account", body); err != nil {
+				log.Println("Error sending invitation to", r.Email, ":", err)
+			}
+		}
+	}()
+	return len(queue)
+}
+
+func randomPassword() string {
+	const alphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	for i := range b {
+		b[i] = alphabet[int(b[i])%len(alphabet)]
+	}
+	return string(b)
+}
+
+func isBlankRow(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
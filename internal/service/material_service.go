@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrMaterialNotFound = apperr.NotFound("material_not_found", "material not found")
+	ErrNotInCourse      = apperr.Forbidden("not_enrolled_in_course", "student is not enrolled in this course")
+)
+
+// MaterialService manages course materials. Staff see every course's
+// materials; students only those of courses they are active in.
+type MaterialService struct {
+	materials   *repository.MaterialRepo
+	courses     *repository.CourseRepo
+	enrollments *repository.EnrollmentRepo
+}
+
+func NewMaterialService(materials *repository.MaterialRepo, courses *repository.CourseRepo, enrollments *repository.EnrollmentRepo) *MaterialService {
+	return &MaterialService{materials: materials, courses: courses, enrollments: enrollments}
+}
+
+func (s *MaterialService) List(ctx context.Context, courseID, viewerID int, role string) ([]model.CourseMaterial, error) {
+	if err := s.canRead(ctx, courseID, viewerID, role); err != nil {
+		return nil, err
+	}
+	return s.materials.ListByCourse(ctx, courseID)
+}
+
+func (s *MaterialService) Get(ctx context.Context, courseID, id, viewerID int, role string) (model.CourseMaterial, error) {
+	if err := s.canRead(ctx, courseID, viewerID, role); err != nil {
+		return model.CourseMaterial{}, err
+	}
+	m, err := s.materials.Get(ctx, courseID, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return m, ErrMaterialNotFound
+	}
+	return m, err
+}
+
+func (s *MaterialService) Create(ctx context.Context, m model.CourseMaterial) (int, error) {
+	m.Title = strings.TrimSpace(m.Title)
+	if m.Title == "" {
+		return 0, apperr.Field("title", "is required")
+	}
+	if len(m.Body) > 200_000 {
+		return 0, apperr.Field("body", "must be at most 200000 bytes")
+	}
+	if err := s.courseExists(ctx, m.CourseID); err != nil {
+		return 0, err
+	}
+	return s.materials.Create(ctx, m)
+}
+
+func (s *MaterialService) Delete(ctx context.Context, courseID, id int) error {
+	err := s.materials.Delete(ctx, courseID, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrMaterialNotFound
+	}
+	return err
+}
+
+func (s *MaterialService) canRead(ctx context.Context, courseID, viewerID int, role string) error {
+	if err := s.courseExists(ctx, courseID); err != nil {
+		return err
+	}
+	if role != "student" {
+		return nil
+	}
+	enrolled, err := s.enrollments.IsEnrolled(ctx, courseID, viewerID)
+	if err != nil {
+		return err
+	}
+	if !enrolled {
+		return ErrNotInCourse
+	}
+	return nil
+}
+
+func (s *MaterialService) courseExists(ctx context.Context, courseID int) error {
+	if courseID <= 0 {
+		return apperr.Field("course_id", "must be > 0")
+	}
+	_, err := s.courses.GetByID(ctx, courseID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCourseNotFound
+	}
+	return err
+}
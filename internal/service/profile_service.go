@@ -0,0 +1,283 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png" // decoder for uploaded avatars
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/repository"
+	"lms-backend/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/image/draw"
+)
+
+// avatarSize is the edge of the square JPEG every avatar is converted to.
+const (
+	avatarSize      = 256
+	maxAvatarPixels = 40_000_000
+)
+
+var (
+	studentNumberRe = regexp.MustCompile(`^[A-Za-z0-9-]{3,32}$`)
+	phoneRe         = regexp.MustCompile(`^\+?[0-9]{6,15}$`)
+	languageRe      = regexp.MustCompile(`^[a-z]{2}(-[A-Z]{2})?$`)
+
+	ErrStudentNumberTaken = repository.ErrStudentNumberTaken
+	ErrNoAvatar           = apperr.NotFound("no_avatar", "user has no avatar")
+)
+
+type ProfileService struct {
+	profiles *repository.ProfileRepo
+	groups   *repository.GroupRepo
+	users    *repository.UserRepo
+	roles    *repository.RoleRepo
+	reqs     *repository.RequirementRepo
+	files    *storage.LocalStore
+	maxBytes int64
+}
+
+func NewProfileService(profiles *repository.ProfileRepo, groups *repository.GroupRepo, users *repository.UserRepo, roles *repository.RoleRepo, reqs *repository.RequirementRepo, files *storage.LocalStore, maxUploadMB int) *ProfileService {
+	return &ProfileService{profiles: profiles, groups: groups, users: users, roles: roles, reqs: reqs, files: files, maxBytes: int64(maxUploadMB) << 20}
+}
+
+func (s *ProfileService) Get(ctx context.Context, userID int) (model.UserProfile, error) {
+	if _, err := s.role(ctx, userID); err != nil {
+		return model.UserProfile{}, err
+	}
+	return s.profiles.Get(ctx, userID)
+}
+
+// selfEditable are the fields users may change on their own profile; the
+// rest is registrar data edited by admins.
+func selfEditable(p model.ProfilePatch) bool {
+	return p.StudentNumber == nil && p.Group == nil && p.Year == nil && p.Faculty == nil &&
+		p.Department == nil && p.Title == nil
+}
+
+// Update applies patch to the profile of userID on behalf of actorID. Unless
+// asAdmin, only phone and language may change. Student fields are refused
+// for staff and the other way round. A new group must exist and the student
+// must meet the requirements of its courses (a *RequirementsError).
+func (s *ProfileService) Update(ctx context.Context, userID, actorID int, patch model.ProfilePatch, asAdmin bool) (model.UserProfile, error) {
+	if !asAdmin && !selfEditable(patch) {
+		return model.UserProfile{}, apperr.Forbidden("field_not_editable", "only phone and language can be changed here")
+	}
+	role, err := s.role(ctx, userID)
+	if err != nil {
+		return model.UserProfile{}, err
+	}
+	p, err := s.profiles.Get(ctx, userID)
+	if err != nil {
+		return model.UserProfile{}, err
+	}
+
+	oldGroup := p.Group
+	set := func(dst *string, v *string) {
+		if v != nil {
+			*dst = strings.TrimSpace(*v)
+		}
+	}
+	set(&p.StudentNumber, patch.StudentNumber)
+	set(&p.Group, patch.Group)
+	set(&p.Faculty, patch.Faculty)
+	set(&p.Phone, patch.Phone)
+	set(&p.Language, patch.Language)
+	set(&p.Department, patch.Department)
+	set(&p.Title, patch.Title)
+	if patch.Year != nil {
+		p.Year = *patch.Year
+	}
+
+	p.Phone = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(p.Phone)
+	if err := validateProfile(p, role); err != nil {
+		return model.UserProfile{}, err
+	}
+	// resolve and check the groups before anything is written, so a refused
+	// move leaves both the profile and the memberships alone
+	move := p.Group != oldGroup
+	var from, to int
+	if move {
+		if from, err = s.groupID(ctx, oldGroup); err != nil {
+			return model.UserProfile{}, err
+		}
+		if to, err = s.groupID(ctx, p.Group); err != nil {
+			return model.UserProfile{}, err
+		}
+		if p.Group != "" && to == 0 {
+			return model.UserProfile{}, apperr.Field("group", fmt.Sprintf("%q does not exist", p.Group))
+		}
+		if to != 0 {
+			courseIDs, err := s.groups.CourseIDs(ctx, to)
+			if err != nil {
+				return model.UserProfile{}, err
+			}
+			if err := checkGroupRequirements(ctx, s.reqs, to, courseIDs, []int{userID}); err != nil {
+				return model.UserProfile{}, err
+			}
+		}
+	}
+	if err := s.profiles.Save(ctx, p); err != nil {
+		return model.UserProfile{}, err
+	}
+	if move {
+		if err := s.moveToGroup(ctx, userID, actorID, from, to); err != nil {
+			return model.UserProfile{}, err
+		}
+	}
+	return s.profiles.Get(ctx, userID)
+}
+
+// groupID resolves a profile group name; no name and unknown names are 0.
+func (s *ProfileService) groupID(ctx context.Context, name string) (int, error) {
+	if name == "" {
+		return 0, nil
+	}
+	g, err := s.groups.GetByName(ctx, name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return g.ID, err
+}
+
+// moveToGroup changes the student's group membership (and with it their
+// group course enrollments) from group from to group to (0 = none).
+func (s *ProfileService) moveToGroup(ctx context.Context, userID, actorID, from, to int) error {
+	if to != 0 {
+		_, err := s.groups.AddMembers(ctx, to, []int{userID}, actorID)
+		return err
+	}
+	if from == 0 {
+		return nil
+	}
+	_, err := s.groups.RemoveMember(ctx, from, userID, actorID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	return err
+}
+
+func validateProfile(p model.UserProfile, role string) error {
+	if p.StudentNumber != "" && !studentNumberRe.MatchString(p.StudentNumber) {
+		return apperr.Field("student_number", "must be 3-32 letters, digits or dashes")
+	}
+	if p.Year < 0 || p.Year > 10 {
+		return apperr.Field("year", "must be between 1 and 10")
+	}
+	if p.Phone != "" && !phoneRe.MatchString(p.Phone) {
+		return apperr.Field("phone", "must be 6-15 digits, optionally starting with +")
+	}
+	if p.Language != "" && !languageRe.MatchString(p.Language) {
+		return apperr.Field("language", "must be a code like en or en-GB")
+	}
+	for _, v := range []string{p.Group, p.Faculty, p.Department, p.Title} {
+		if len(v) > 100 {
+			return apperr.Invalid("text fields are limited to 100 characters")
+		}
+	}
+
+	student := p.StudentNumber != "" || p.Group != "" || p.Year != 0 || p.Faculty != ""
+	staff := p.Department != "" || p.Title != ""
+	if role == "student" && staff {
+		return apperr.Invalid("department and title are for staff only")
+	}
+	if role != "student" && student {
+		return apperr.Invalid("student_number, group, year and faculty are for students only")
+	}
+	return nil
+}
+
+// SetAvatar decodes a JPEG or PNG upload, crops it to a centered square,
+// scales it to avatarSize and stores it as JPEG, replacing the old avatar.
+func (s *ProfileService) SetAvatar(ctx context.Context, userID int, r io.Reader) error {
+	if _, err := s.role(ctx, userID); err != nil {
+		return err
+	}
+	data, err := io.ReadAll(io.LimitReader(r, s.maxBytes+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > s.maxBytes {
+		return apperr.New(apperr.KindInvalid, "file_too_large", "file is too large")
+	}
+	// refuse huge dimensions before allocating the decoded image
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return apperr.Field("avatar", "must be a JPEG or PNG image")
+	}
+	if cfg.Width*cfg.Height > maxAvatarPixels {
+		return apperr.Field("avatar", "dimensions are too large")
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return apperr.Field("avatar", "must be a JPEG or PNG image")
+	}
+
+	b := src.Bounds()
+	side := min(b.Dx(), b.Dy())
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(b.Min.X+(b.Dx()-side)/2, b.Min.Y+(b.Dy()-side)/2))
+	dst := image.NewRGBA(image.Rect(0, 0, avatarSize, avatarSize))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+		return err
+	}
+	path, err := s.files.Save("avatars", ".jpg", &buf, s.maxBytes)
+	if err != nil {
+		return err
+	}
+	old, err := s.profiles.SetAvatar(ctx, userID, path)
+	if err != nil {
+		_ = s.files.Remove(path)
+		return err
+	}
+	if old != "" {
+		_ = s.files.Remove(old)
+	}
+	return nil
+}
+
+func (s *ProfileService) DeleteAvatar(ctx context.Context, userID int) error {
+	old, err := s.profiles.SetAvatar(ctx, userID, "")
+	if err != nil {
+		return err
+	}
+	if old != "" {
+		_ = s.files.Remove(old)
+	}
+	return nil
+}
+
+// Avatar opens the stored avatar of a user.
+func (s *ProfileService) Avatar(ctx context.Context, userID int) (*os.File, error) {
+	p, err := s.profiles.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if p.AvatarPath == "" {
+		return nil, ErrNoAvatar
+	}
+	return s.files.Open(p.AvatarPath)
+}
+
+func (s *ProfileService) role(ctx context.Context, userID int) (string, error) {
+	u, err := s.users.GetByID(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && u.DeletedAt != nil) {
+		return "", ErrUserNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return s.roles.GetNameByID(ctx, u.RoleID)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/export"
+	"lms-backend/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ReportService struct {
+	attendance *repository.AttendanceRepo
+	courses    *repository.CourseRepo
+	statuses   *repository.AttendanceStatusRepo
+}
+
+func NewReportService(attendance *repository.AttendanceRepo, courses *repository.CourseRepo, statuses *repository.AttendanceStatusRepo) *ReportService {
+	return &ReportService{attendance: attendance, courses: courses, statuses: statuses}
+}
+
+var ErrCourseNotFound = apperr.NotFound("course_not_found", "course not found")
+
+// RegisterMeta loads everything a register needs before its first row: the
+// course, the lesson dates within [from, to] and the status legend. Zero
+// from/to mean an open range.
+func (s *ReportService) RegisterMeta(ctx context.Context, courseID int, from, to time.Time) (model.RegisterMeta, error) {
+	if courseID <= 0 {
+		return model.RegisterMeta{}, apperr.Field("course_id", "must be > 0")
+	}
+	if from.IsZero() {
+		from = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if to.IsZero() {
+		to = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	}
+	if to.Before(from) {
+		return model.RegisterMeta{}, apperr.Field("to", "must not be before from")
+	}
+
+	course, err := s.courses.GetByID(ctx, courseID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.RegisterMeta{}, ErrCourseNotFound
+	}
+	if err != nil {
+		return model.RegisterMeta{}, err
+	}
+	dates, err := s.attendance.LessonDates(ctx, courseID, from, to)
+	if err != nil {
+		return model.RegisterMeta{}, err
+	}
+	statuses, err := s.statuses.List(ctx)
+	if err != nil {
+		return model.RegisterMeta{}, err
+	}
+
+	return model.RegisterMeta{
+		CourseID: course.ID, CourseTitle: course.Title,
+		From: from, To: to, Dates: dates, Statuses: statuses,
+		GeneratedAt: time.Now(),
+	}, nil
+}
+
+// WriteRegister streams the register described by meta into w, one student
+// at a time.
+func (s *ReportService) WriteRegister(ctx context.Context, meta model.RegisterMeta, w export.RegisterWriter) error {
+	if err := w.Begin(meta); err != nil {
+		return err
+	}
+	if err := s.attendance.StreamRegister(ctx, meta.CourseID, meta.From, meta.To, w.Row); err != nil {
+		return err
+	}
+	return w.End()
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrRequirementsNotMet   = apperr.Unprocessable("requirements_not_met", "enrollment requirements not met")
+	ErrRequirementNotFound  = apperr.NotFound("requirement_not_found", "requirement not found")
+	ErrRequirementCycle     = repository.ErrRequirementCycle
+	ErrRequirementTarget    = repository.ErrRequirementTarget
+	ErrOverrideNotPermitted = apperr.Forbidden("override_not_permitted", "only admins can override enrollment requirements")
+)
+
+// RequirementsError lists the rules a student fails for a course, or for a
+// group enrollment every student and course that failed. It matches
+// ErrRequirementsNotMet with errors.Is.
+type RequirementsError struct {
+	Unmet    []model.UnmetRule
+	Students []model.StudentUnmet
+}
+
+func (e *RequirementsError) Error() string { return ErrRequirementsNotMet.Error() }
+
+func (e *RequirementsError) Unwrap() error { return ErrRequirementsNotMet }
+
+var weekdays = [...]string{"", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// RequirementService manages prerequisites, co-requisites and course
+// timetables, and checks them against a student.
+type RequirementService struct {
+	reqs    *repository.RequirementRepo
+	courses *repository.CourseRepo
+}
+
+func NewRequirementService(reqs *repository.RequirementRepo, courses *repository.CourseRepo) *RequirementService {
+	return &RequirementService{reqs: reqs, courses: courses}
+}
+
+func (s *RequirementService) List(ctx context.Context, courseID int) ([]model.CourseRequirement, []model.ScheduleSlot, error) {
+	if err := s.courseExists(ctx, courseID); err != nil {
+		return nil, nil, err
+	}
+	reqs, err := s.reqs.List(ctx, courseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	slots, err := s.reqs.Schedule(ctx, courseID)
+	return reqs, slots, err
+}
+
+func (s *RequirementService) Add(ctx context.Context, q model.CourseRequirement) (int, error) {
+	if err := s.courseExists(ctx, q.CourseID); err != nil {
+		return 0, err
+	}
+	switch q.Kind {
+	case "prerequisite", "corequisite":
+		if q.RequiredCourseID <= 0 {
+			return 0, apperr.Field("required_course_id", "is required")
+		}
+		if q.RequiredCourseID == q.CourseID {
+			return 0, apperr.Invalid("a course cannot require itself")
+		}
+		if q.MinGrade != nil && (q.Kind != "prerequisite" || *q.MinGrade < 0 || *q.MinGrade > 100) {
+			return 0, apperr.Field("min_grade", "must be 0..100 and only applies to prerequisites")
+		}
+		q.MinYear, q.GroupID = 0, 0
+	case "year":
+		if q.MinYear < 1 || q.MinYear > 10 {
+			return 0, apperr.Field("min_year", "must be 1..10")
+		}
+		q.RequiredCourseID, q.MinGrade, q.GroupID = 0, nil, 0
+	case "group":
+		if q.GroupID <= 0 {
+			return 0, apperr.Field("group_id", "is required")
+		}
+		q.RequiredCourseID, q.MinGrade, q.MinYear = 0, nil, 0
+	default:
+		return 0, apperr.Field("kind", "must be prerequisite|corequisite|year|group")
+	}
+	return s.reqs.Add(ctx, q)
+}
+
+func (s *RequirementService) Delete(ctx context.Context, courseID, id int) error {
+	err := s.reqs.Delete(ctx, courseID, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrRequirementNotFound
+	}
+	return err
+}
+
+// SetSchedule replaces the timetable; slots of one course must not overlap.
+func (s *RequirementService) SetSchedule(ctx context.Context, courseID int, slots []model.ScheduleSlot) error {
+	if err := s.courseExists(ctx, courseID); err != nil {
+		return err
+	}
+	type span struct{ from, to time.Time }
+	seen := map[int][]span{}
+	for i, sl := range slots {
+		if sl.Weekday < 1 || sl.Weekday > 7 {
+			return apperr.Invalid(fmt.Sprintf("slot %d: weekday must be 1..7", i+1))
+		}
+		from, err1 := time.Parse("15:04", sl.StartsAt)
+		to, err2 := time.Parse("15:04", sl.EndsAt)
+		if err1 != nil || err2 != nil {
+			return apperr.Invalid(fmt.Sprintf("slot %d: times must be HH:MM", i+1))
+		}
+		if !to.After(from) {
+			return apperr.Invalid(fmt.Sprintf("slot %d: ends_at must be after starts_at", i+1))
+		}
+		for _, o := range seen[sl.Weekday] {
+			if from.Before(o.to) && o.from.Before(to) {
+				return apperr.Conflict("schedule_overlap", fmt.Sprintf("slot %d overlaps another slot of the course", i+1))
+			}
+		}
+		seen[sl.Weekday] = append(seen[sl.Weekday], span{from, to})
+	}
+	return s.reqs.SetSchedule(ctx, courseID, slots)
+}
+
+// Check returns the rules the student does not meet for the course; an empty
+// list means they may be enrolled.
+func (s *RequirementService) Check(ctx context.Context, courseID, studentID int) ([]model.UnmetRule, error) {
+	if err := s.courseExists(ctx, courseID); err != nil {
+		return nil, err
+	}
+	return unmetRules(ctx, s.reqs, courseID, studentID)
+}
+
+func (s *RequirementService) courseExists(ctx context.Context, courseID int) error {
+	if courseID <= 0 {
+		return apperr.Field("course_id", "must be > 0")
+	}
+	_, err := s.courses.GetByID(ctx, courseID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCourseNotFound
+	}
+	return err
+}
+
+// checkRequirements fails with a *RequirementsError when the student does
+// not satisfy every rule of the course.
+func checkRequirements(ctx context.Context, reqs *repository.RequirementRepo, courseID, studentID int) error {
+	unmet, err := unmetRules(ctx, reqs, courseID, studentID)
+	if err != nil {
+		return err
+	}
+	if len(unmet) > 0 {
+		return &RequirementsError{Unmet: unmet}
+	}
+	return nil
+}
+
+// checkGroupRequirements runs the checks for every student joining the
+// courses of groupID and reports all failures at once. A rule asking for
+// groupID itself holds, since the students are in or about to join it.
+func checkGroupRequirements(ctx context.Context, reqs *repository.RequirementRepo, groupID int, courseIDs, studentIDs []int) error {
+	failed := make([]model.StudentUnmet, 0)
+	for _, courseID := range courseIDs {
+		for _, studentID := range studentIDs {
+			unmet, err := unmetRules(ctx, reqs, courseID, studentID)
+			if err != nil {
+				return err
+			}
+			unmet = slices.DeleteFunc(unmet, func(u model.UnmetRule) bool {
+				return u.Rule == "group" && u.GroupID == groupID
+			})
+			if len(unmet) > 0 {
+				failed = append(failed, model.StudentUnmet{StudentID: studentID, CourseID: courseID, Unmet: unmet})
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return &RequirementsError{Students: failed}
+	}
+	return nil
+}
+
+func unmetRules(ctx context.Context, reqs *repository.RequirementRepo, courseID, studentID int) ([]model.UnmetRule, error) {
+	rules, err := reqs.List(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	st, err := reqs.Standing(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	unmet := make([]model.UnmetRule, 0)
+	for _, q := range rules {
+		u := model.UnmetRule{Rule: q.Kind, RequirementID: q.ID}
+		cs := st.Courses[q.RequiredCourseID]
+		switch q.Kind {
+		case "prerequisite":
+			u.CourseID, u.CourseTitle = q.RequiredCourseID, q.RequiredCourse
+			switch {
+			case !cs.Completed:
+				u.Message = fmt.Sprintf("course %q must be completed first", q.RequiredCourse)
+			case q.MinGrade != nil && (cs.Grade == nil || *cs.Grade < *q.MinGrade):
+				u.Rule, u.MinGrade, u.Grade = "min_grade", q.MinGrade, cs.Grade
+				u.Message = fmt.Sprintf("course %q must be completed with at least %g", q.RequiredCourse, *q.MinGrade)
+			default:
+				continue
+			}
+		case "corequisite":
+			if cs.Enrolled || cs.Completed {
+				continue
+			}
+			u.CourseID, u.CourseTitle = q.RequiredCourseID, q.RequiredCourse
+			u.Message = fmt.Sprintf("course %q must be taken at the same time or before", q.RequiredCourse)
+		case "year":
+			if st.Year >= q.MinYear {
+				continue
+			}
+			u.MinYear, u.Year = q.MinYear, st.Year
+			u.Message = fmt.Sprintf("year of study %d or higher required", q.MinYear)
+		case "group":
+			if st.GroupID == q.GroupID {
+				continue
+			}
+			u.GroupID, u.GroupName = q.GroupID, q.GroupName
+			u.Message = fmt.Sprintf("only for group %s", q.GroupName)
+		}
+		unmet = append(unmet, u)
+	}
+
+	conflicts, err := reqs.Conflicts(ctx, courseID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, x := range conflicts {
+		unmet = append(unmet, model.UnmetRule{
+			Rule: "schedule_conflict", CourseID: x.CourseID, CourseTitle: x.CourseTitle,
+			Weekday: x.Weekday, StartsAt: x.StartsAt, EndsAt: x.EndsAt,
+			Message: fmt.Sprintf("clashes with %q on %s %s-%s", x.CourseTitle, weekdays[x.Weekday], x.StartsAt, x.EndsAt),
+		})
+	}
+	return unmet, nil
+}
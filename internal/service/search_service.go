@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/repository"
+)
+
+const (
+	searchDefaultLimit = 10
+	searchMaxLimit     = 50
+)
+
+var searchKinds = map[string]bool{"users": true, "courses": true, "materials": true}
+
+var searchLikeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchService answers GET /search. What a caller finds depends on the
+// role: admins search everything, teachers the students of their courses
+// and their own courses' materials, students courses and the materials of
+// courses they are active in (never other users).
+type SearchService struct {
+	search *repository.SearchRepo
+}
+
+func NewSearchService(search *repository.SearchRepo) *SearchService {
+	return &SearchService{search: search}
+}
+
+// Search looks q up in the given kinds (all allowed ones when empty) and
+// returns at most limit hits per kind.
+func (s *SearchService) Search(ctx context.Context, q string, kinds []string, limit, viewerID int, role string) (model.SearchResults, error) {
+	var res model.SearchResults
+	q = strings.Join(strings.Fields(q), " ")
+	if n := utf8.RuneCountInString(q); n < 2 || n > 200 {
+		return res, apperr.Field("q", "must be 2..200 characters")
+	}
+	if limit == 0 {
+		limit = searchDefaultLimit
+	}
+	if limit < 1 || limit > searchMaxLimit {
+		return res, apperr.Field("limit", "must be 1..50")
+	}
+
+	want := map[string]bool{}
+	for _, k := range kinds {
+		if !searchKinds[k] {
+			return res, apperr.Field("type", "must be users, courses or materials")
+		}
+		want[k] = true
+	}
+	if len(kinds) == 0 {
+		want = searchKinds
+	}
+	like := "%" + searchLikeEscaper.Replace(q) + "%"
+
+	var err error
+	if want["users"] && role != "student" {
+		teacherID := 0
+		if role == "teacher" {
+			teacherID = viewerID
+		}
+		if res.Users, err = s.search.Users(ctx, q, like, teacherID, limit); err != nil {
+			return res, err
+		}
+	}
+	if want["courses"] {
+		if res.Courses, err = s.search.Courses(ctx, q, like, limit); err != nil {
+			return res, err
+		}
+	}
+	if want["materials"] {
+		studentID, teacherID := 0, 0
+		switch role {
+		case "student":
+			studentID = viewerID
+		case "teacher":
+			teacherID = viewerID
+		}
+		if res.Materials, err = s.search.Materials(ctx, q, like, studentID, teacherID, limit); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
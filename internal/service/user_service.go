@@ -3,20 +3,43 @@ package service
 import (
 	"context"
 	"errors"
+	"net/mail"
 	"strings"
 
+	"lms-backend/internal/apperr"
 	"lms-backend/internal/domain/model"
+	"lms-backend/internal/pagination"
 	"lms-backend/internal/repository"
+	"lms-backend/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrUserNotFound = apperr.NotFound("user_not_found", "user not found")
+
+var ErrEmailTaken = repository.ErrEmailTaken
+
+// Versions of conditional writes; see repository.AnyVersion.
+const (
+	AnyVersion = repository.AnyVersion
+	NewOnly    = repository.NewOnly
+)
+
+var (
+	ErrVersionMismatch = repository.ErrVersionMismatch
+	ErrVersionRequired = repository.ErrVersionRequired
 )
 
 type UserService struct {
 	repo  *repository.UserRepo
 	roles *repository.RoleRepo
 	auth  *AuthService
+	files *storage.LocalStore
 }
 
-func NewUserService(repo *repository.UserRepo, roles *repository.RoleRepo, auth *AuthService) *UserService {
-	return &UserService{repo: repo, roles: roles, auth: auth}
+func NewUserService(repo *repository.UserRepo, roles *repository.RoleRepo, auth *AuthService, files *storage.LocalStore) *UserService {
+	return &UserService{repo: repo, roles: roles, auth: auth, files: files}
 }
 
 func (s *UserService) Create(ctx context.Context, u model.User, rawPassword string) (int, error) {
@@ -24,10 +47,10 @@ func (s *UserService) Create(ctx context.Context, u model.User, rawPassword stri
 	u.FullName = strings.TrimSpace(u.FullName)
 
 	if u.Email == "" || u.FullName == "" {
-		return 0, errors.New("email and full_name are required")
+		return 0, apperr.Invalid("email and full_name are required")
 	}
 	if u.RoleID <= 0 {
-		return 0, errors.New("role_id must be > 0")
+		return 0, apperr.Field("role_id", "must be > 0")
 	}
 
 	hash, err := s.auth.HashPassword(rawPassword)
@@ -39,12 +62,15 @@ func (s *UserService) Create(ctx context.Context, u model.User, rawPassword stri
 	return s.repo.Create(ctx, u)
 }
 
-func (s *UserService) List(ctx context.Context) ([]model.User, error) {
-	return s.repo.List(ctx)
+func (s *UserService) List(ctx context.Context, req pagination.Request) ([]model.User, string, error) {
+	return s.repo.List(ctx, req)
 }
 
 func (s *UserService) Me(ctx context.Context, userID int) (model.User, string, error) {
 	u, err := s.repo.GetByID(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.User{}, "", ErrUserNotFound
+	}
 	if err != nil {
 		return model.User{}, "", err
 	}
@@ -55,24 +81,192 @@ func (s *UserService) Me(ctx context.Context, userID int) (model.User, string, e
 	return u, roleName, nil
 }
 
-// Admin only: change role by role name
-func (s *UserService) ChangeRole(ctx context.Context, userID int, roleName string) error {
+// Admin only: change role by role name. version is the one the change is
+// based on (AnyVersion to skip the check); the new one is returned.
+func (s *UserService) ChangeRole(ctx context.Context, userID int, roleName string, version int) (int, error) {
 	roleName = strings.TrimSpace(strings.ToLower(roleName))
 	if roleName == "" {
-		return errors.New("role is required")
+		return 0, apperr.Field("role", "is required")
 	}
 	switch roleName {
 	case "admin", "teacher", "student":
 	default:
-		return errors.New("role must be admin|teacher|student")
+		return 0, apperr.Field("role", "must be admin|teacher|student")
 	}
 	roleID, err := s.roles.GetIDByName(ctx, roleName)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	return s.repo.UpdateRole(ctx, userID, roleID)
+	version, err = s.repo.UpdateRole(ctx, userID, roleID, version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrUserNotFound
+	}
+	return version, err
 }
 
 func (s *UserService) ListRoles(ctx context.Context) ([]struct{ ID int; Name string }, error) {
 	return s.roles.List(ctx)
 }
+
+// Get returns a user by id; deleted users are still returned (anonymized).
+func (s *UserService) Get(ctx context.Context, id int) (model.User, string, error) {
+	return s.Me(ctx, id)
+}
+
+// GetMany returns the users among ids by id, deleted ones included.
+func (s *UserService) GetMany(ctx context.Context, ids []int) (map[int]model.User, error) {
+	users, err := s.repo.ListByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int]model.User, len(users))
+	for _, u := range users {
+		out[u.ID] = u
+	}
+	return out, nil
+}
+
+// RoleNames maps role ids to names.
+func (s *UserService) RoleNames(ctx context.Context) (map[int]string, error) {
+	roles, err := s.roles.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int]string, len(roles))
+	for _, r := range roles {
+		out[r.ID] = r.Name
+	}
+	return out, nil
+}
+
+// UserUpdate is an admin edit; nil fields are left unchanged. Version is the
+// version of the user the edit is based on, AnyVersion to skip the check.
+type UserUpdate struct {
+	Email    *string
+	FullName *string
+	Role     *string
+	Active   *bool
+	Password *string
+	Version  int
+}
+
+// Update applies an admin edit and returns the new version. Admins cannot
+// deactivate or demote themselves, so there is always someone left to undo
+// mistakes.
+func (s *UserService) Update(ctx context.Context, actorID, userID int, in UserUpdate) (int, error) {
+	if userID <= 0 {
+		return 0, apperr.Field("user_id", "must be > 0")
+	}
+	if userID == actorID {
+		if in.Active != nil && !*in.Active {
+			return 0, apperr.Forbidden("self_change", "you cannot deactivate yourself")
+		}
+		if in.Role != nil && strings.TrimSpace(strings.ToLower(*in.Role)) != "admin" {
+			return 0, apperr.Forbidden("self_change", "you cannot change your own role")
+		}
+	}
+
+	p, err := s.patch(in.Email, in.FullName, in.Password)
+	if err != nil {
+		return 0, err
+	}
+	p.Active = in.Active
+	if in.Role != nil {
+		name := strings.TrimSpace(strings.ToLower(*in.Role))
+		roleID, err := s.roles.GetIDByName(ctx, name)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, apperr.Field("role", "is unknown")
+		}
+		if err != nil {
+			return 0, err
+		}
+		p.RoleID = &roleID
+	}
+	return s.update(ctx, userID, p, in.Version)
+}
+
+// ProfileUpdate is what users may change about themselves. Changing the
+// email or password requires the current password. Version is as in
+// UserUpdate.
+type ProfileUpdate struct {
+	Email           *string
+	FullName        *string
+	Password        *string
+	CurrentPassword string
+	Version         int
+}
+
+func (s *UserService) UpdateMe(ctx context.Context, userID int, in ProfileUpdate) (int, error) {
+	if in.Email != nil || in.Password != nil {
+		u, err := s.repo.GetByID(ctx, userID)
+		if err != nil {
+			return 0, err
+		}
+		if bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(in.CurrentPassword)) != nil {
+			return 0, apperr.Field("current_password", "is incorrect")
+		}
+	}
+	p, err := s.patch(in.Email, in.FullName, in.Password)
+	if err != nil {
+		return 0, err
+	}
+	return s.update(ctx, userID, p, in.Version)
+}
+
+// Delete soft-deletes a user: personal data is anonymized or removed,
+// attendance stays.
+func (s *UserService) Delete(ctx context.Context, actorID, userID int) error {
+	if userID <= 0 {
+		return apperr.Field("user_id", "must be > 0")
+	}
+	if userID == actorID {
+		return apperr.Forbidden("self_change", "you cannot delete yourself")
+	}
+	avatar, err := s.repo.SoftDelete(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	if err == nil && avatar != "" {
+		_ = s.files.Remove(avatar)
+	}
+	return err
+}
+
+// patch validates and normalizes the profile fields shared by admin and
+// self-service edits.
+func (s *UserService) patch(email, fullName, password *string) (model.UserPatch, error) {
+	var p model.UserPatch
+	if email != nil {
+		e := strings.TrimSpace(strings.ToLower(*email))
+		if a, err := mail.ParseAddress(e); err != nil || a.Address != e {
+			return p, apperr.Field("email", "is invalid")
+		}
+		p.Email = &e
+	}
+	if fullName != nil {
+		n := strings.TrimSpace(*fullName)
+		if n == "" {
+			return p, apperr.Field("full_name", "must not be empty")
+		}
+		p.FullName = &n
+	}
+	if password != nil {
+		hash, err := s.auth.HashPassword(*password)
+		if err != nil {
+			return p, err
+		}
+		p.PasswordHash = &hash
+	}
+	return p, nil
+}
+
+func (s *UserService) update(ctx context.Context, userID int, p model.UserPatch, version int) (int, error) {
+	if p == (model.UserPatch{}) {
+		return 0, apperr.Invalid("nothing to update")
+	}
+	version, err := s.repo.Update(ctx, userID, p, version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrUserNotFound
+	}
+	return version, err
+}
@@ -0,0 +1,272 @@
+// Package sheet reads uploaded tables (CSV or the first worksheet of an XLSX
+// workbook) into rows of strings.
+package sheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var ErrUnsupported = errors.New("file must be .csv or .xlsx")
+
+// Read parses data according to the extension of filename. Trailing empty
+// rows are dropped; rows may be shorter than the header.
+func Read(filename string, data []byte) ([][]string, error) {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return readCSV(data)
+	case ".xlsx":
+		return readXLSX(data)
+	default:
+		return nil, ErrUnsupported
+	}
+}
+
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // Excel's UTF-8 BOM
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	// Excel in many locales saves CSV with semicolons.
+	if first, _, _ := bytes.Cut(data, []byte("\n")); bytes.Count(first, []byte(";")) > bytes.Count(first, []byte(",")) {
+		r.Comma = ';'
+	}
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+	return trimEmpty(rows), nil
+}
+
+func readXLSX(data []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx: %w", err)
+	}
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var shared []string
+	if f := files["xl/sharedStrings.xml"]; f != nil {
+		if shared, err = readSharedStrings(f); err != nil {
+			return nil, fmt.Errorf("invalid xlsx: %w", err)
+		}
+	}
+	name, err := firstSheet(files)
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx: %w", err)
+	}
+	f := files[name]
+	if f == nil {
+		return nil, errors.New("invalid xlsx: worksheet not found")
+	}
+	rows, err := readWorksheet(f, shared)
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx: %w", err)
+	}
+	return trimEmpty(rows), nil
+}
+
+// firstSheet resolves the part name of the first sheet listed in the
+// workbook, falling back to the conventional name.
+func firstSheet(files map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+	wb, rels := files["xl/workbook.xml"], files["xl/_rels/workbook.xml.rels"]
+	if wb == nil || rels == nil {
+		return fallback, nil
+	}
+
+	var workbook struct {
+		Sheets []struct {
+			ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodePart(wb, &workbook); err != nil {
+		return "", err
+	}
+	var relations struct {
+		Items []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodePart(rels, &relations); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("workbook has no sheets")
+	}
+	for _, rel := range relations.Items {
+		if rel.ID != workbook.Sheets[0].ID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return fallback, nil
+}
+
+func decodePart(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+func readSharedStrings(f *zip.File) ([]string, error) {
+	var sst struct {
+		Items []struct {
+			T    string `xml:"t"`
+			Runs []struct {
+				T string `xml:"t"`
+			} `xml:"r"`
+		} `xml:"si"`
+	}
+	if err := decodePart(f, &sst); err != nil {
+		return nil, err
+	}
+	out := make([]string, len(sst.Items))
+	for i, si := range sst.Items {
+		s := si.T
+		for _, r := range si.Runs {
+			s += r.T
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+// readWorksheet walks the sheet with a token decoder so large sheets are not
+// unmarshalled into one big structure.
+func readWorksheet(f *zip.File, shared []string) ([][]string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	type cell struct {
+		R  string `xml:"r,attr"`
+		T  string `xml:"t,attr"`
+		V  string `xml:"v"`
+		IS struct {
+			T    string `xml:"t"`
+			Runs []struct {
+				T string `xml:"t"`
+			} `xml:"r"`
+		} `xml:"is"`
+	}
+
+	var (
+		rows [][]string
+		cur  []string
+	)
+	dec := xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "row":
+				// rows may skip numbers; keep line numbers aligned with Excel
+				if n, err := strconv.Atoi(attr(t, "r")); err == nil {
+					for len(rows) < n-1 {
+						rows = append(rows, nil)
+					}
+				}
+				cur = []string{}
+			case "c":
+				var c cell
+				if err := dec.DecodeElement(&c, &t); err != nil {
+					return nil, err
+				}
+				col := len(cur)
+				if c.R != "" {
+					col = columnIndex(c.R)
+				}
+				for len(cur) < col {
+					cur = append(cur, "")
+				}
+				var v string
+				switch c.T {
+				case "s":
+					i, err := strconv.Atoi(c.V)
+					if err != nil || i < 0 || i >= len(shared) {
+						return nil, fmt.Errorf("bad shared string index in %s", c.R)
+					}
+					v = shared[i]
+				case "inlineStr":
+					v = c.IS.T
+					for _, r := range c.IS.Runs {
+						v += r.T
+					}
+				default:
+					v = c.V
+				}
+				cur = append(cur, v)
+			}
+		case xml.EndElement:
+			if t.Name.Local == "row" {
+				rows = append(rows, cur)
+			}
+		}
+	}
+	return rows, nil
+}
+
+func attr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// columnIndex turns a cell reference into a 0-based column: "C7" -> 2.
+func columnIndex(ref string) int {
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		n = n*26 + int(r-'A'+1)
+	}
+	return n - 1
+}
+
+func trimEmpty(rows [][]string) [][]string {
+	for len(rows) > 0 && isEmpty(rows[len(rows)-1]) {
+		rows = rows[:len(rows)-1]
+	}
+	return rows
+}
+
+func isEmpty(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
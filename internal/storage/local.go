@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore keeps uploaded files on the local disk under a base directory.
+// Paths handed out and accepted by it are relative to that directory.
+type LocalStore struct {
+	dir string
+}
+
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// Save writes r to a new randomly named file in the sub directory and returns
+// its relative path. At most maxBytes are accepted.
+func (s *LocalStore) Save(sub, ext string, r io.Reader, maxBytes int64) (string, error) {
+	if err := os.MkdirAll(filepath.Join(s.dir, sub), 0o755); err != nil {
+		return "", err
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	rel := filepath.Join(sub, hex.EncodeToString(b)+ext)
+
+	f, err := os.OpenFile(filepath.Join(s.dir, rel), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", err
+	}
+	n, err := io.Copy(f, io.LimitReader(r, maxBytes+1))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && n > maxBytes {
+		err = errors.New("file is too large")
+	}
+	if err != nil {
+		_ = os.Remove(filepath.Join(s.dir, rel))
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func (s *LocalStore) Open(rel string) (*os.File, error) {
+	p, err := s.path(rel)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(p)
+}
+
+func (s *LocalStore) Remove(rel string) error {
+	p, err := s.path(rel)
+	if err != nil {
+		return err
+	}
+	return os.Remove(p)
+}
+
+func (s *LocalStore) path(rel string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(rel))
+	if rel == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", errors.New("invalid file path")
+	}
+	return filepath.Join(s.dir, clean), nil
+}
@@ -0,0 +1,191 @@
+package graphqlapi
+
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"lms-backend/internal/domain/model"
+)
+
+const (
+	// batchWait is how long a loader collects keys before fetching them.
+	// Sibling fields of a list resolve concurrently, so this is enough to
+	// gather one level of the query.
+	batchWait = 2 * time.Millisecond
+	maxBatch  = 500
+)
+
+// loader turns the Load calls made while a query resolves into one fetch per
+// batch, so 50 courses cost one teacher query instead of 50. Results are kept
+// for the rest of the request.
+type loader[K comparable, V any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	entries map[K]*entry[V]
+	pending []K
+}
+
+type entry[V any] struct {
+	ready chan struct{}
+	val   V
+	err   error
+}
+
+func newLoader[K comparable, V any](ctx context.Context, fetch func(context.Context, []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{ctx: ctx, fetch: fetch, entries: map[K]*entry[V]{}}
+}
+
+// Load returns the value of key, the zero value when the fetch had none.
+func (l *loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	e, ok := l.entries[key]
+	if !ok {
+		e = &entry[V]{ready: make(chan struct{})}
+		l.entries[key] = e
+		l.pending = append(l.pending, key)
+		switch len(l.pending) {
+		case 1:
+			time.AfterFunc(batchWait, l.dispatch)
+		case maxBatch:
+			go l.dispatch()
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-e.ready:
+		return e.val, e.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+func (l *loader[K, V]) dispatch() {
+	l.mu.Lock()
+	keys := l.pending
+	l.pending = nil
+	entries := make([]*entry[V], len(keys))
+	for i, k := range keys {
+		entries[i] = l.entries[k]
+	}
+	l.mu.Unlock()
+	if len(keys) == 0 {
+		return
+	}
+
+	vals, err := l.safeFetch(keys)
+	for i, k := range keys {
+		entries[i].val, entries[i].err = vals[k], err
+		close(entries[i].ready)
+	}
+}
+
+// safeFetch turns a panic into an error: the fetch runs outside the
+// resolver goroutines, so nothing else would recover it.
+func (l *loader[K, V]) safeFetch(keys []K) (vals map[K]V, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			log.Printf("graphql loader panic: %v\n%s", v, debug.Stack())
+			err = errors.New("loader panicked")
+		}
+	}()
+	return l.fetch(l.ctx, keys)
+}
+
+// enrollmentKey asks for the enrollments of a course or a student.
+type enrollmentKey struct {
+	ID     int
+	Status string
+}
+
+// attendanceKey asks for the marks of a course; StudentID > 0 narrows them
+// to one student.
+type attendanceKey struct {
+	CourseID  int
+	StudentID int
+	From, To  time.Time
+}
+
+// loaders are the batch loaders of one request.
+type loaders struct {
+	users              *loader[int, model.User]
+	courses            *loader[int, model.Course]
+	courseEnrollments  *loader[enrollmentKey, []model.Enrollment]
+	studentEnrollments *loader[enrollmentKey, []model.Enrollment]
+	attendance         *loader[attendanceKey, []model.Attendance]
+	roleNames          func() (map[int]string, error)
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, r *Resolver) context.Context {
+	l := &loaders{
+		users:   newLoader(ctx, r.users.GetMany),
+		courses: newLoader(ctx, r.courses.GetMany),
+		courseEnrollments: newLoader(ctx, func(ctx context.Context, keys []enrollmentKey) (map[enrollmentKey][]model.Enrollment, error) {
+			return byStatus(ctx, keys, r.courses.EnrollmentsByCourses)
+		}),
+		studentEnrollments: newLoader(ctx, func(ctx context.Context, keys []enrollmentKey) (map[enrollmentKey][]model.Enrollment, error) {
+			return byStatus(ctx, keys, r.courses.EnrollmentsByStudents)
+		}),
+		attendance: newLoader(ctx, func(ctx context.Context, keys []attendanceKey) (map[attendanceKey][]model.Attendance, error) {
+			return attendanceByCourses(ctx, keys, r)
+		}),
+		roleNames: sync.OnceValues(func() (map[int]string, error) { return r.users.RoleNames(ctx) }),
+	}
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// byStatus makes one call of fetch per status among keys.
+func byStatus(ctx context.Context, keys []enrollmentKey, fetch func(context.Context, []int, string) (map[int][]model.Enrollment, error)) (map[enrollmentKey][]model.Enrollment, error) {
+	ids := map[string][]int{}
+	for _, k := range keys {
+		ids[k.Status] = append(ids[k.Status], k.ID)
+	}
+	out := make(map[enrollmentKey][]model.Enrollment, len(keys))
+	for status, group := range ids {
+		found, err := fetch(ctx, group, status)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range group {
+			out[enrollmentKey{ID: id, Status: status}] = found[id]
+		}
+	}
+	return out, nil
+}
+
+// attendanceByCourses makes one query per student and date range among keys.
+func attendanceByCourses(ctx context.Context, keys []attendanceKey, r *Resolver) (map[attendanceKey][]model.Attendance, error) {
+	type scope struct {
+		StudentID int
+		From, To  time.Time
+	}
+	ids := map[scope][]int{}
+	for _, k := range keys {
+		s := scope{k.StudentID, k.From, k.To}
+		ids[s] = append(ids[s], k.CourseID)
+	}
+	out := make(map[attendanceKey][]model.Attendance, len(keys))
+	for s, group := range ids {
+		found, err := r.attendance.ListByCourses(ctx, group, s.StudentID, s.From, s.To)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range group {
+			out[attendanceKey{CourseID: id, StudentID: s.StudentID, From: s.From, To: s.To}] = found[id]
+		}
+	}
+	return out, nil
+}
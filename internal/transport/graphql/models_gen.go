@@ -0,0 +1,39 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package graphqlapi
+
+import (
+	"lms-backend/internal/domain/model"
+)
+
+type CourseConnection struct {
+	Items      []model.Course `json:"items"`
+	NextCursor *string        `json:"nextCursor,omitempty"`
+}
+
+type FilterInput struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// One page of a list, as limit, cursor, sort and filters of the REST query string.
+type PageInput struct {
+	// 1..200, default 50.
+	Limit *int `json:"limit,omitempty"`
+	// nextCursor of the previous page.
+	Cursor *string `json:"cursor,omitempty"`
+	// A field name, prefixed with - for descending order.
+	Sort    *string       `json:"sort,omitempty"`
+	Filters []FilterInput `json:"filters,omitempty"`
+}
+
+// Read API for the dashboard. Roles are checked like on the REST routes:
+// fields a caller may not see fail with a FORBIDDEN error in `errors` while
+// the rest of the response is still returned.
+type Query struct {
+}
+
+type UserConnection struct {
+	Items      []model.User `json:"items"`
+	NextCursor *string      `json:"nextCursor,omitempty"`
+}
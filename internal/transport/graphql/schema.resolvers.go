@@ -0,0 +1,211 @@
+package graphqlapi
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.78
+
+import (
+	"context"
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/service"
+	"time"
+)
+
+func (r *attendanceResolver) LessonDate(ctx context.Context, obj *model.Attendance) (string, error) {
+	return obj.LessonDate.Format(dateLayout), nil
+}
+
+func (r *attendanceResolver) Course(ctx context.Context, obj *model.Attendance) (*model.Course, error) {
+	return mustCourse(ctx, obj.CourseID)
+}
+
+func (r *attendanceResolver) Student(ctx context.Context, obj *model.Attendance) (*model.User, error) {
+	return mustUser(ctx, obj.StudentID)
+}
+
+func (r *courseResolver) Teacher(ctx context.Context, obj *model.Course) (*model.User, error) {
+	return loadUser(ctx, obj.TeacherID)
+}
+
+func (r *courseResolver) Enrollments(ctx context.Context, obj *model.Course, status *string) ([]model.Enrollment, error) {
+	items, err := loadersFrom(ctx).courseEnrollments.Load(ctx, enrollmentKey{ID: obj.ID, Status: deref(status)})
+	if err != nil {
+		return nil, err
+	}
+	if me := callerFrom(ctx); !me.staff() {
+		own := []model.Enrollment{}
+		for _, e := range items {
+			if e.StudentID == me.UserID {
+				own = append(own, e)
+			}
+		}
+		return own, nil
+	}
+	return orEmpty(items), nil
+}
+
+func (r *courseResolver) Attendance(ctx context.Context, obj *model.Course, from *string, to *string) ([]model.Attendance, error) {
+	key := attendanceKey{CourseID: obj.ID}
+	var err error
+	if key.From, err = optionalDate("from", from); err != nil {
+		return nil, err
+	}
+	if key.To, err = optionalDate("to", to); err != nil {
+		return nil, err
+	}
+	if me := callerFrom(ctx); !me.staff() {
+		key.StudentID = me.UserID
+	}
+	items, err := loadersFrom(ctx).attendance.Load(ctx, key)
+	return orEmpty(items), err
+}
+
+func (r *enrollmentResolver) Course(ctx context.Context, obj *model.Enrollment) (*model.Course, error) {
+	return mustCourse(ctx, obj.CourseID)
+}
+
+func (r *enrollmentResolver) Student(ctx context.Context, obj *model.Enrollment) (*model.User, error) {
+	return mustUser(ctx, obj.StudentID)
+}
+
+func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
+	u, err := loadUser(ctx, callerFrom(ctx).UserID)
+	if err == nil && u == nil {
+		return nil, service.ErrUserNotFound
+	}
+	return u, err
+}
+
+func (r *queryResolver) User(ctx context.Context, id int) (*model.User, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return loadUser(ctx, id)
+}
+
+func (r *queryResolver) Users(ctx context.Context, page *PageInput) (*UserConnection, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	req, err := pageRequest(page)
+	if err != nil {
+		return nil, err
+	}
+	items, next, err := r.users.List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &UserConnection{Items: orEmpty(items), NextCursor: cursor(next)}, nil
+}
+
+func (r *queryResolver) Course(ctx context.Context, id int) (*model.Course, error) {
+	c, err := loadersFrom(ctx).courses.Load(ctx, id)
+	if err != nil || c.ID == 0 {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *queryResolver) Courses(ctx context.Context, page *PageInput) (*CourseConnection, error) {
+	req, err := pageRequest(page)
+	if err != nil {
+		return nil, err
+	}
+	items, next, err := r.courses.List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &CourseConnection{Items: orEmpty(items), NextCursor: cursor(next)}, nil
+}
+
+func (r *queryResolver) MyCourses(ctx context.Context, status *string, page *PageInput) (*CourseConnection, error) {
+	req, err := pageRequest(page)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		items []model.Course
+		next  string
+	)
+	switch me := callerFrom(ctx); me.Role {
+	case "student":
+		items, next, err = r.courses.ListByStudent(ctx, me.UserID, deref(status), req)
+	case "teacher":
+		items, next, err = r.courses.ListByTeacher(ctx, me.UserID, req)
+	default:
+		items, next, err = r.courses.List(ctx, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &CourseConnection{Items: orEmpty(items), NextCursor: cursor(next)}, nil
+}
+
+func (r *queryResolver) Transcript(ctx context.Context, studentID *int, status *string) ([]model.TranscriptEntry, error) {
+	me := callerFrom(ctx)
+	id := 0
+	if studentID != nil {
+		id = *studentID
+	}
+	switch {
+	case me.Role == "student" && id == 0:
+		id = me.UserID
+	case me.Role == "student" && id != me.UserID:
+		return nil, apperr.ErrForbidden
+	case id <= 0:
+		return nil, apperr.Field("studentId", "is required")
+	}
+	items, err := r.courses.Transcript(ctx, id, deref(status))
+	return orEmpty(items), err
+}
+
+func (r *transcriptEntryResolver) Course(ctx context.Context, obj *model.TranscriptEntry) (*model.Course, error) {
+	return mustCourse(ctx, obj.CourseID)
+}
+
+func (r *userResolver) Email(ctx context.Context, obj *model.User) (*string, error) {
+	if me := callerFrom(ctx); !me.staff() && me.UserID != obj.ID {
+		return nil, nil
+	}
+	return &obj.Email, nil
+}
+
+func (r *userResolver) Role(ctx context.Context, obj *model.User) (string, error) {
+	names, err := loadersFrom(ctx).roleNames()
+	return names[obj.RoleID], err
+}
+
+func (r *userResolver) CreatedAt(ctx context.Context, obj *model.User) (*time.Time, error) {
+	if obj.CreatedAt.IsZero() {
+		return nil, nil
+	}
+	return &obj.CreatedAt, nil
+}
+
+func (r *userResolver) Enrollments(ctx context.Context, obj *model.User, status *string) ([]model.Enrollment, error) {
+	if me := callerFrom(ctx); !me.staff() && me.UserID != obj.ID {
+		return nil, apperr.ErrForbidden
+	}
+	items, err := loadersFrom(ctx).studentEnrollments.Load(ctx, enrollmentKey{ID: obj.ID, Status: deref(status)})
+	return orEmpty(items), err
+}
+
+func (r *Resolver) Attendance() AttendanceResolver { return &attendanceResolver{r} }
+
+func (r *Resolver) Course() CourseResolver { return &courseResolver{r} }
+
+func (r *Resolver) Enrollment() EnrollmentResolver { return &enrollmentResolver{r} }
+
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+func (r *Resolver) TranscriptEntry() TranscriptEntryResolver { return &transcriptEntryResolver{r} }
+
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
+type attendanceResolver struct{ *Resolver }
+type courseResolver struct{ *Resolver }
+type enrollmentResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type transcriptEntryResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
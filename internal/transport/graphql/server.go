@@ -0,0 +1,163 @@
+package graphqlapi
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/pagination"
+	"lms-backend/internal/service"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/jackc/pgx/v5"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Limits bound the cost of a single query.
+type Limits struct {
+	MaxDepth      int // nesting of fields, introspection not counted
+	MaxComplexity int // see complexity
+}
+
+const (
+	// nestedListCost is the assumed length of lists without a page limit,
+	// e.g. Course.enrollments, when estimating complexity.
+	nestedListCost = 10
+
+	cachedQueries = 1000
+)
+
+// NewHandler serves GraphQL over GET and POST for the caller stored with
+// WithCaller. Clients may send the sha256 of a query instead of its text
+// (automatic persisted queries): the first request carries both, later ones
+// only the hash.
+func NewHandler(users *service.UserService, courses *service.CourseService, attendance *service.AttendanceService, limits Limits) http.Handler {
+	r := &Resolver{users: users, courses: courses, attendance: attendance}
+	cfg := Config{Resolvers: r}
+	complexity(&cfg.Complexity)
+
+	srv := handler.New(NewExecutableSchema(cfg))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](cachedQueries))
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{Cache: lru.New[string](cachedQueries)})
+	srv.Use(depthLimit(limits.MaxDepth))
+	srv.Use(extension.FixedComplexityLimit(limits.MaxComplexity))
+	srv.SetErrorPresenter(presentError)
+	srv.SetRecoverFunc(func(ctx context.Context, v any) error {
+		log.Printf("graphql panic %v: %v\n%s", graphql.GetPath(ctx), v, debug.Stack())
+		return internalError(ctx)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		srv.ServeHTTP(w, req.WithContext(withLoaders(req.Context(), r)))
+	})
+}
+
+// complexity counts each field as 1 plus its selections, times the page
+// limit for connections and times nestedListCost for other lists.
+func complexity(c *ComplexityRoot) {
+	page := func(p *PageInput) int {
+		if p != nil && p.Limit != nil && *p.Limit > 0 {
+			return *p.Limit
+		}
+		return pagination.DefaultLimit
+	}
+	c.Query.Users = func(child int, p *PageInput) int { return 1 + child*page(p) }
+	c.Query.Courses = func(child int, p *PageInput) int { return 1 + child*page(p) }
+	c.Query.MyCourses = func(child int, _ *string, p *PageInput) int { return 1 + child*page(p) }
+	c.Query.Transcript = func(child int, _ *int, _ *string) int { return 1 + child*nestedListCost }
+	c.Course.Enrollments = func(child int, _ *string) int { return 1 + child*nestedListCost }
+	c.Course.Attendance = func(child int, _, _ *string) int { return 1 + child*nestedListCost }
+	c.User.Enrollments = func(child int, _ *string) int { return 1 + child*nestedListCost }
+}
+
+// depthLimit rejects queries nested deeper than its value.
+type depthLimit int
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = depthLimit(0)
+
+func (depthLimit) ExtensionName() string                          { return "DepthLimit" }
+func (depthLimit) Validate(schema graphql.ExecutableSchema) error { return nil }
+
+func (d depthLimit) MutateOperationContext(ctx context.Context, oc *graphql.OperationContext) *gqlerror.Error {
+	if depth := selectionDepth(oc.Operation.SelectionSet); depth > int(d) {
+		err := gqlerror.Errorf("query is %d levels deep, the limit is %d", depth, d)
+		errcode.Set(err, "QUERY_TOO_DEEP")
+		return err
+	}
+	return nil
+}
+
+// selectionDepth is the deepest chain of fields in set. Fragment cycles are
+// rejected by validation before this runs.
+func selectionDepth(set ast.SelectionSet) int {
+	deepest := 0
+	for _, s := range set {
+		depth := 0
+		switch s := s.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue // introspection queries are deep by design
+			}
+			depth = 1 + selectionDepth(s.SelectionSet)
+		case *ast.InlineFragment:
+			depth = selectionDepth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				depth = selectionDepth(s.Definition.SelectionSet)
+			}
+		}
+		deepest = max(deepest, depth)
+	}
+	return deepest
+}
+
+// presentError puts the apperr code and field errors in the extensions of a
+// resolver error, as responder.Fail does in the REST body. Errors that are
+// not an *apperr.Error are logged and reported as "internal".
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	gerr := graphql.DefaultErrorPresenter(ctx, err)
+	if gerr.Err == nil {
+		return gerr // parsing, validation and limits
+	}
+	if e, ok := apperr.As(err); ok && e.Kind != apperr.KindInternal {
+		gerr.Extensions = map[string]any{"code": e.Code}
+		if len(e.Fields) > 0 {
+			gerr.Extensions["fields"] = e.Fields
+		}
+		return gerr
+	}
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, os.ErrNotExist) {
+		gerr.Message, gerr.Extensions = "not found", map[string]any{"code": "not_found"}
+		return gerr
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		gerr.Extensions = map[string]any{"code": "canceled"}
+		return gerr
+	}
+
+	log.Printf("graphql internal error %v: %v", graphql.GetPath(ctx), err)
+	return internalError(ctx)
+}
+
+func internalError(ctx context.Context) *gqlerror.Error {
+	err := gqlerror.ErrorPathf(graphql.GetPath(ctx), "internal server error")
+	errcode.Set(err, "internal")
+	return err
+}
@@ -0,0 +1,6 @@
+//go:build tools
+
+package graphqlapi
+
+// keeps the generator in go.mod for go:generate
+import _ "github.com/99designs/gqlgen"
@@ -0,0 +1,169 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/pagination"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/grpc/lmsv1"
+
+	"google.golang.org/grpc"
+)
+
+const dateLayout = "2006-01-02"
+
+type attendanceServer struct {
+	lmsv1.UnimplementedAttendanceServiceServer
+	svc *service.AttendanceService
+}
+
+func (s *attendanceServer) MarkAttendance(ctx context.Context, req *lmsv1.MarkAttendanceRequest) (*lmsv1.MarkAttendanceResponse, error) {
+	date, err := lessonDate(req.GetLessonDate())
+	if err != nil {
+		return nil, err
+	}
+	// like If-Match on REST: without a version only a new mark is created
+	version := service.NewOnly
+	if req.Version != nil {
+		if req.GetVersion() <= 0 {
+			return nil, apperr.Field("version", "must be > 0")
+		}
+		version = int(req.GetVersion())
+	}
+	version, err = s.svc.Mark(ctx, model.Attendance{
+		CourseID: int(req.GetCourseId()), StudentID: int(req.GetStudentId()),
+		LessonDate: date, Status: req.GetStatus(), Note: req.GetNote(),
+	}, callerFrom(ctx).UserID, version)
+	if err != nil {
+		return nil, err
+	}
+	return &lmsv1.MarkAttendanceResponse{Version: int32(version)}, nil
+}
+
+func (s *attendanceServer) MarkLesson(ctx context.Context, req *lmsv1.MarkLessonRequest) (*lmsv1.MarkLessonResponse, error) {
+	date, err := lessonDate(req.GetLessonDate())
+	if err != nil {
+		return nil, err
+	}
+	items := make([]model.Attendance, 0, len(req.GetItems()))
+	for _, x := range req.GetItems() {
+		items = append(items, model.Attendance{StudentID: int(x.GetStudentId()), Status: x.GetStatus(), Note: x.GetNote(), Version: int(x.GetVersion())})
+	}
+
+	results, err := s.svc.MarkLesson(ctx, int(req.GetCourseId()), date, req.GetDefaultStatus(), items, callerFrom(ctx).UserID)
+	if errors.Is(err, service.ErrRollCallRejected) || errors.Is(err, service.ErrRollCallConflict) {
+		return nil, rollCallStatus(err, results)
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := &lmsv1.MarkLessonResponse{}
+	for _, x := range results {
+		out.Results = append(out.Results, &lmsv1.MarkLessonResponse_Result{StudentId: int64(x.StudentID), Status: x.Status, Changed: x.Changed, Version: int32(x.Version)})
+		if x.Changed {
+			out.Changed++
+		}
+	}
+	return out, nil
+}
+
+func (s *attendanceServer) ListCourseAttendance(ctx context.Context, req *lmsv1.ListCourseAttendanceRequest) (*lmsv1.ListCourseAttendanceResponse, error) {
+	page, err := pageRequest(req.GetPage())
+	if err != nil {
+		return nil, err
+	}
+	items, next, err := s.svc.ListByCourse(ctx, int(req.GetCourseId()), page)
+	if err != nil {
+		return nil, err
+	}
+	return &lmsv1.ListCourseAttendanceResponse{Records: recordsPB(items), NextCursor: next}, nil
+}
+
+func (s *attendanceServer) ListMyAttendance(ctx context.Context, req *lmsv1.ListMyAttendanceRequest) (*lmsv1.ListMyAttendanceResponse, error) {
+	page, err := pageRequest(req.GetPage())
+	if err != nil {
+		return nil, err
+	}
+	items, next, err := s.svc.ListByStudent(ctx, callerFrom(ctx).UserID, page)
+	if err != nil {
+		return nil, err
+	}
+	return &lmsv1.ListMyAttendanceResponse{Records: recordsPB(items), NextCursor: next}, nil
+}
+
+// StreamCourseAttendance walks every page of the course's records and sends
+// them one by one, so callers need not handle cursors.
+func (s *attendanceServer) StreamCourseAttendance(req *lmsv1.StreamCourseAttendanceRequest, stream grpc.ServerStreamingServer[lmsv1.StreamCourseAttendanceResponse]) error {
+	ctx := stream.Context()
+	q := url.Values{}
+	for k, v := range req.GetFilters() {
+		q.Set(k, v)
+	}
+	page, err := pagination.FromQuery(q)
+	if err != nil {
+		return err
+	}
+	page.Sort, page.Limit = req.GetSort(), pagination.MaxLimit
+
+	for {
+		items, next, err := s.svc.ListByCourse(ctx, int(req.GetCourseId()), page)
+		if err != nil {
+			return err
+		}
+		for _, r := range recordsPB(items) {
+			if err := stream.Send(&lmsv1.StreamCourseAttendanceResponse{Record: r}); err != nil {
+				return err
+			}
+		}
+		if next == "" {
+			return nil
+		}
+		page.Cursor = next
+	}
+}
+
+// GetHistory lists the changes of a student's marks in a course. Students
+// may only see their own.
+func (s *attendanceServer) GetHistory(ctx context.Context, req *lmsv1.GetHistoryRequest) (*lmsv1.GetHistoryResponse, error) {
+	if me := callerFrom(ctx); me.Role == "student" && int(req.GetStudentId()) != me.UserID {
+		return nil, apperr.ErrForbidden
+	}
+	items, err := s.svc.History(ctx, int(req.GetCourseId()), int(req.GetStudentId()))
+	if err != nil {
+		return nil, err
+	}
+	out := &lmsv1.GetHistoryResponse{}
+	for _, x := range items {
+		out.Changes = append(out.Changes, &lmsv1.AttendanceChange{
+			Id: int64(x.ID), AttendanceId: int64(x.AttendanceID), LessonDate: x.LessonDate.Format(dateLayout),
+			OldStatus: x.OldStatus, OldNote: x.OldNote, NewStatus: x.NewStatus, NewNote: x.NewNote,
+			ChangedBy: int64(x.ChangedBy), ChangedAt: timestamp(x.ChangedAt),
+		})
+	}
+	return out, nil
+}
+
+func lessonDate(s string) (time.Time, error) {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return t, apperr.Field("lesson_date", "must be YYYY-MM-DD")
+	}
+	return t, nil
+}
+
+func recordsPB(items []model.Attendance) []*lmsv1.AttendanceRecord {
+	out := make([]*lmsv1.AttendanceRecord, 0, len(items))
+	for _, a := range items {
+		out = append(out, &lmsv1.AttendanceRecord{
+			Id: int64(a.ID), CourseId: int64(a.CourseID), StudentId: int64(a.StudentID),
+			LessonDate: a.LessonDate.Format(dateLayout), Status: a.Status, Note: a.Note,
+			Version: int32(a.Version),
+		})
+	}
+	return out
+}
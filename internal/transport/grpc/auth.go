@@ -0,0 +1,159 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/grpc/lmsv1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	anyRole   = []string{"admin", "teacher", "student"}
+	staff     = []string{"admin", "teacher"}
+	adminOnly = []string{"admin"}
+)
+
+// methodRoles is the RPC counterpart of RequireRoles on the REST routes.
+// Methods missing here are refused.
+var methodRoles = map[string][]string{
+	lmsv1.UserService_GetMe_FullMethodName:      anyRole,
+	lmsv1.UserService_GetUser_FullMethodName:    adminOnly,
+	lmsv1.UserService_ListUsers_FullMethodName:  adminOnly,
+	lmsv1.UserService_CreateUser_FullMethodName: adminOnly,
+	lmsv1.UserService_ChangeRole_FullMethodName: adminOnly,
+
+	lmsv1.CourseService_ListCourses_FullMethodName:   anyRole,
+	lmsv1.CourseService_ListMyCourses_FullMethodName: anyRole,
+	lmsv1.CourseService_CreateCourse_FullMethodName:  staff,
+	lmsv1.CourseService_ListStudents_FullMethodName:  staff,
+
+	lmsv1.EnrollmentService_Enroll_FullMethodName:              staff,
+	lmsv1.EnrollmentService_Unenroll_FullMethodName:            staff,
+	lmsv1.EnrollmentService_SetEnrollmentStatus_FullMethodName: staff,
+	lmsv1.EnrollmentService_GetTranscript_FullMethodName:       anyRole,
+
+	lmsv1.AttendanceService_MarkAttendance_FullMethodName:         staff,
+	lmsv1.AttendanceService_MarkLesson_FullMethodName:             staff,
+	lmsv1.AttendanceService_ListCourseAttendance_FullMethodName:   staff,
+	lmsv1.AttendanceService_ListMyAttendance_FullMethodName:       anyRole,
+	lmsv1.AttendanceService_StreamCourseAttendance_FullMethodName: staff,
+	lmsv1.AttendanceService_GetHistory_FullMethodName:             anyRole,
+}
+
+// publicServices need no token.
+var publicServices = []string{"/grpc.health.v1.Health/", "/grpc.reflection."}
+
+type callerKey struct{}
+
+// caller is the authenticated user of a call.
+type caller struct {
+	UserID int
+	Role   string
+}
+
+func callerFrom(ctx context.Context) caller {
+	c, _ := ctx.Value(callerKey{}).(caller)
+	return c
+}
+
+// authorize checks the bearer token like middleware.AuthJWT and the role
+// like middleware.RequireRoles, and stores the caller in the context.
+func authorize(ctx context.Context, auth *service.AuthService, method string) (context.Context, error) {
+	for _, prefix := range publicServices {
+		if strings.HasPrefix(method, prefix) {
+			return ctx, nil
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	h := ""
+	if v := md.Get("authorization"); len(v) > 0 {
+		h = v[0]
+	}
+	if !strings.HasPrefix(h, "Bearer ") {
+		return ctx, apperr.Unauthorized("missing_token", "missing bearer token")
+	}
+	claims, err := auth.Authenticate(ctx, strings.TrimPrefix(h, "Bearer "))
+	if errors.Is(err, service.ErrAccountDisabled) {
+		return ctx, err
+	}
+	if err != nil {
+		return ctx, apperr.Unauthorized("invalid_token", "invalid token")
+	}
+
+	allowed := false
+	for _, r := range methodRoles[method] {
+		allowed = allowed || r == claims.Role
+	}
+	if !allowed {
+		return ctx, apperr.ErrForbidden
+	}
+	return context.WithValue(ctx, callerKey{}, caller{UserID: claims.UserID, Role: claims.Role}), nil
+}
+
+func unaryInterceptor(auth *service.AuthService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		start := time.Now()
+		defer func() {
+			err = finish(ctx, info.FullMethod, start, recover(), err)
+		}()
+
+		ctx, err = authorize(ctx, auth, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamInterceptor(auth *service.AuthService) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		start := time.Now()
+		defer func() {
+			err = finish(ss.Context(), info.FullMethod, start, recover(), err)
+		}()
+
+		ctx, err := authorize(ss.Context(), auth, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authedStream carries the caller to stream handlers.
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authedStream) Context() context.Context { return s.ctx }
+
+// finish turns a panic or service error into a status and logs the call, as
+// gin.Recovery and middleware.RequestLogger do for REST.
+func finish(ctx context.Context, method string, start time.Time, panicked any, err error) error {
+	if panicked != nil {
+		log.Printf("grpc panic %s: %v\n%s", method, panicked, debug.Stack())
+		err = status.Error(codes.Internal, "internal server error")
+	}
+	err = toStatus(method, err)
+
+	ip := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		ip = p.Addr.String()
+	}
+	log.Printf("grpc %s %s ip=%s latency=%s", status.Code(err), method, ip, time.Since(start))
+	return err
+}
@@ -0,0 +1,174 @@
+package grpcapi
+
+import (
+	"context"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/grpc/lmsv1"
+)
+
+type courseServer struct {
+	lmsv1.UnimplementedCourseServiceServer
+	svc *service.CourseService
+}
+
+func (s *courseServer) ListCourses(ctx context.Context, req *lmsv1.ListCoursesRequest) (*lmsv1.ListCoursesResponse, error) {
+	page, err := pageRequest(req.GetPage())
+	if err != nil {
+		return nil, err
+	}
+	items, next, err := s.svc.List(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	return &lmsv1.ListCoursesResponse{Courses: coursesPB(items), NextCursor: next}, nil
+}
+
+// ListMyCourses answers with the courses a student takes, a teacher teaches
+// or, for admins, every course.
+func (s *courseServer) ListMyCourses(ctx context.Context, req *lmsv1.ListMyCoursesRequest) (*lmsv1.ListMyCoursesResponse, error) {
+	page, err := pageRequest(req.GetPage())
+	if err != nil {
+		return nil, err
+	}
+	var (
+		items []model.Course
+		next  string
+	)
+	switch me := callerFrom(ctx); me.Role {
+	case "student":
+		items, next, err = s.svc.ListByStudent(ctx, me.UserID, req.GetStatus(), page)
+	case "teacher":
+		items, next, err = s.svc.ListByTeacher(ctx, me.UserID, page)
+	default:
+		items, next, err = s.svc.List(ctx, page)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lmsv1.ListMyCoursesResponse{Courses: coursesPB(items), NextCursor: next}, nil
+}
+
+func (s *courseServer) CreateCourse(ctx context.Context, req *lmsv1.CreateCourseRequest) (*lmsv1.CreateCourseResponse, error) {
+	teacherID := int(req.GetTeacherId())
+	if teacherID == 0 {
+		teacherID = callerFrom(ctx).UserID
+	}
+	id, err := s.svc.Create(ctx, model.Course{
+		Title: req.GetTitle(), TeacherID: teacherID, Term: req.GetTerm(), Credits: req.GetCredits(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &lmsv1.CreateCourseResponse{Id: int64(id)}, nil
+}
+
+func (s *courseServer) ListStudents(ctx context.Context, req *lmsv1.ListStudentsRequest) (*lmsv1.ListStudentsResponse, error) {
+	page, err := pageRequest(req.GetPage())
+	if err != nil {
+		return nil, err
+	}
+	items, next, err := s.svc.GetStudents(ctx, int(req.GetCourseId()), req.GetStatus(), page)
+	if err != nil {
+		return nil, err
+	}
+	out := &lmsv1.ListStudentsResponse{NextCursor: next}
+	for _, u := range items {
+		out.Students = append(out.Students, &lmsv1.Student{Id: int64(u.ID), FullName: u.FullName, Email: u.Email})
+	}
+	return out, nil
+}
+
+func coursesPB(items []model.Course) []*lmsv1.Course {
+	out := make([]*lmsv1.Course, 0, len(items))
+	for _, x := range items {
+		out = append(out, &lmsv1.Course{
+			Id: int64(x.ID), Title: x.Title, TeacherId: int64(x.TeacherID), Term: x.Term,
+			Credits: x.Credits, CreatedAt: timestamp(x.CreatedAt),
+		})
+	}
+	return out
+}
+
+// enrollmentServer exposes the enrollment part of CourseService.
+type enrollmentServer struct {
+	lmsv1.UnimplementedEnrollmentServiceServer
+	svc *service.CourseService
+}
+
+func (s *enrollmentServer) Enroll(ctx context.Context, req *lmsv1.EnrollRequest) (*lmsv1.EnrollResponse, error) {
+	if req.GetOverride() && callerFrom(ctx).Role != "admin" {
+		return nil, service.ErrOverrideNotPermitted
+	}
+	err := s.svc.Enroll(ctx, int(req.GetCourseId()), int(req.GetStudentId()), callerFrom(ctx).UserID, req.GetOverride())
+	if st, ok := requirementsStatus(err); ok {
+		return nil, st
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lmsv1.EnrollResponse{}, nil
+}
+
+func (s *enrollmentServer) Unenroll(ctx context.Context, req *lmsv1.UnenrollRequest) (*lmsv1.UnenrollResponse, error) {
+	promoted, err := s.svc.Unenroll(ctx, int(req.GetCourseId()), int(req.GetStudentId()), callerFrom(ctx).UserID, req.GetReason())
+	if err != nil {
+		return nil, err
+	}
+	return &lmsv1.UnenrollResponse{Promoted: ids(promoted)}, nil
+}
+
+func (s *enrollmentServer) SetEnrollmentStatus(ctx context.Context, req *lmsv1.SetEnrollmentStatusRequest) (*lmsv1.SetEnrollmentStatusResponse, error) {
+	promoted, err := s.svc.SetEnrollmentStatus(ctx, int(req.GetCourseId()), int(req.GetStudentId()), model.EnrollmentChange{
+		Status: req.GetStatus(), Reason: req.GetReason(), Grade: req.Grade, ChangedBy: callerFrom(ctx).UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &lmsv1.SetEnrollmentStatusResponse{Promoted: ids(promoted)}, nil
+}
+
+// GetTranscript answers with the caller's own transcript for students and
+// with the transcript of student_id for staff.
+func (s *enrollmentServer) GetTranscript(ctx context.Context, req *lmsv1.GetTranscriptRequest) (*lmsv1.GetTranscriptResponse, error) {
+	me := callerFrom(ctx)
+	studentID := int(req.GetStudentId())
+	switch {
+	case me.Role == "student" && studentID == 0:
+		studentID = me.UserID
+	case me.Role == "student" && studentID != me.UserID:
+		return nil, apperr.ErrForbidden
+	}
+
+	items, err := s.svc.Transcript(ctx, studentID, req.GetStatus())
+	if err != nil {
+		return nil, err
+	}
+	out := &lmsv1.GetTranscriptResponse{StudentId: int64(studentID)}
+	for _, x := range items {
+		e := &lmsv1.TranscriptEntry{
+			CourseId: int64(x.CourseID), CourseTitle: x.CourseTitle, Teacher: x.TeacherName,
+			Term: x.Term, Credits: x.Credits, Status: x.Status, Grade: x.Grade,
+			AttendanceRate: x.AttendanceRate, Reason: x.Reason, Group: x.GroupName,
+			EnrolledAt: timestamp(x.EnrolledAt), StatusChangedAt: timestamp(x.StatusChangedAt),
+		}
+		for _, ev := range x.History {
+			e.History = append(e.History, &lmsv1.EnrollmentEvent{
+				From: ev.OldStatus, To: ev.Status, Reason: ev.Reason,
+				ChangedBy: int64(ev.ChangedBy), ChangedByName: ev.ChangedByName, ChangedAt: timestamp(ev.ChangedAt),
+			})
+		}
+		out.Entries = append(out.Entries, e)
+	}
+	return out, nil
+}
+
+func ids(in []int) []int64 {
+	out := make([]int64, len(in))
+	for i, id := range in {
+		out[i] = int64(id)
+	}
+	return out
+}
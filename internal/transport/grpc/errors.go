@@ -0,0 +1,122 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/service"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// kindCode maps apperr kinds like responder maps them to HTTP statuses.
+var kindCode = map[apperr.Kind]codes.Code{
+	apperr.KindInvalid:              codes.InvalidArgument,
+	apperr.KindUnauthorized:         codes.Unauthenticated,
+	apperr.KindForbidden:            codes.PermissionDenied,
+	apperr.KindNotFound:             codes.NotFound,
+	apperr.KindConflict:             codes.FailedPrecondition,
+	apperr.KindUnprocessable:        codes.FailedPrecondition,
+	apperr.KindPrecondition:         codes.Aborted,
+	apperr.KindPreconditionRequired: codes.FailedPrecondition,
+	apperr.KindRateLimited:          codes.ResourceExhausted,
+	apperr.KindTooLarge:             codes.InvalidArgument,
+}
+
+// toStatus turns a service error into a status. The apperr code travels as
+// the ErrorInfo reason and field errors as BadRequest details; errors that
+// are not an *apperr.Error are logged and answered with a generic Internal,
+// so database details never reach the client.
+func toStatus(method string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	if e, ok := apperr.As(err); ok {
+		if code, known := kindCode[e.Kind]; known {
+			var fields []*errdetails.BadRequest_FieldViolation
+			for field, msg := range e.Fields {
+				fields = append(fields, &errdetails.BadRequest_FieldViolation{Field: field, Description: msg})
+			}
+			sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+			return withDetails(status.New(code, err.Error()), e.Code, fields, nil)
+		}
+	}
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, os.ErrNotExist) {
+		return withDetails(status.New(codes.NotFound, "not found"), "not_found", nil, nil)
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	log.Printf("grpc internal error %s: %v", method, err)
+	return status.Error(codes.Internal, "internal server error")
+}
+
+// requirementsStatus lists the unmet rules of a failed enrollment as
+// PreconditionFailure violations; ok is false for other errors.
+func requirementsStatus(err error) (error, bool) {
+	var re *service.RequirementsError
+	if !errors.As(err, &re) {
+		return nil, false
+	}
+	violations := make([]*errdetails.PreconditionFailure_Violation, 0, len(re.Unmet))
+	for _, u := range re.Unmet {
+		subject := ""
+		if u.CourseID > 0 {
+			subject = fmt.Sprintf("course:%d", u.CourseID)
+		}
+		violations = append(violations, &errdetails.PreconditionFailure_Violation{Type: u.Rule, Subject: subject, Description: u.Message})
+	}
+	st := status.New(codes.FailedPrecondition, err.Error())
+	return withDetails(st, service.ErrRequirementsNotMet.Code, nil, violations), true
+}
+
+// rollCallStatus names the students whose rows made a roll call fail:
+// invalid rows as field violations, version conflicts as precondition ones.
+func rollCallStatus(err error, results []model.AttendanceMarkResult) error {
+	var (
+		fields    []*errdetails.BadRequest_FieldViolation
+		conflicts []*errdetails.PreconditionFailure_Violation
+	)
+	for _, x := range results {
+		switch {
+		case x.Code != "":
+			conflicts = append(conflicts, &errdetails.PreconditionFailure_Violation{Type: x.Code, Subject: fmt.Sprintf("student:%d", x.StudentID), Description: x.Error})
+		case x.Error != "":
+			fields = append(fields, &errdetails.BadRequest_FieldViolation{Field: "items", Description: fmt.Sprintf("student %d: %s", x.StudentID, x.Error)})
+		}
+	}
+	if errors.Is(err, service.ErrRollCallConflict) {
+		return withDetails(status.New(codes.Aborted, err.Error()), service.ErrRollCallConflict.Code, fields, conflicts)
+	}
+	return withDetails(status.New(codes.FailedPrecondition, err.Error()), service.ErrRollCallRejected.Code, fields, conflicts)
+}
+
+func withDetails(st *status.Status, reason string, fields []*errdetails.BadRequest_FieldViolation, unmet []*errdetails.PreconditionFailure_Violation) error {
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: reason, Domain: "lms"}}
+	if len(fields) > 0 {
+		details = append(details, &errdetails.BadRequest{FieldViolations: fields})
+	}
+	if len(unmet) > 0 {
+		details = append(details, &errdetails.PreconditionFailure{Violations: unmet})
+	}
+	full, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return full.Err()
+}
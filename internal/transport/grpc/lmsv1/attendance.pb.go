@@ -0,0 +1,1207 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: lms/v1/attendance.proto
+
+package lmsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AttendanceRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CourseId      int64                  `protobuf:"varint,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	StudentId     int64                  `protobuf:"varint,3,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	LessonDate    string                 `protobuf:"bytes,4,opt,name=lesson_date,json=lessonDate,proto3" json:"lesson_date,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Note          string                 `protobuf:"bytes,6,opt,name=note,proto3" json:"note,omitempty"`
+	Version       int32                  `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"` // goes up with every change
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttendanceRecord) Reset() {
+	*x = AttendanceRecord{}
+	mi := &file_lms_v1_attendance_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttendanceRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttendanceRecord) ProtoMessage() {}
+
+func (x *AttendanceRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_attendance_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttendanceRecord.ProtoReflect.Descriptor instead.
+func (*AttendanceRecord) Descriptor() ([]byte, []int) {
+	return file_lms_v1_attendance_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AttendanceRecord) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AttendanceRecord) GetCourseId() int64 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *AttendanceRecord) GetStudentId() int64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *AttendanceRecord) GetLessonDate() string {
+	if x != nil {
+		return x.LessonDate
+	}
+	return ""
+}
+
+func (x *AttendanceRecord) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AttendanceRecord) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *AttendanceRecord) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type MarkAttendanceRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CourseId   int64                  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	StudentId  int64                  `protobuf:"varint,2,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	LessonDate string                 `protobuf:"bytes,3,opt,name=lesson_date,json=lessonDate,proto3" json:"lesson_date,omitempty"`
+	Status     string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Note       string                 `protobuf:"bytes,5,opt,name=note,proto3" json:"note,omitempty"`
+	// version of the mark being changed; unset only creates a mark, so an
+	// existing one answers FAILED_PRECONDITION (version_required)
+	Version       *int32 `protobuf:"varint,6,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkAttendanceRequest) Reset() {
+	*x = MarkAttendanceRequest{}
+	mi := &file_lms_v1_attendance_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkAttendanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkAttendanceRequest) ProtoMessage() {}
+
+func (x *MarkAttendanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_attendance_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkAttendanceRequest.ProtoReflect.Descriptor instead.
+func (*MarkAttendanceRequest) Descriptor() ([]byte, []int) {
+	return file_lms_v1_attendance_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MarkAttendanceRequest) GetCourseId() int64 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *MarkAttendanceRequest) GetStudentId() int64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *MarkAttendanceRequest) GetLessonDate() string {
+	if x != nil {
+		return x.LessonDate
+	}
+	return ""
+}
+
+func (x *MarkAttendanceRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *MarkAttendanceRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *MarkAttendanceRequest) GetVersion() int32 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type MarkAttendanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       int32                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkAttendanceResponse) Reset() {
+	*x = MarkAttendanceResponse{}
+	mi := &file_lms_v1_attendance_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkAttendanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkAttendanceResponse) ProtoMessage() {}
+
+func (x *MarkAttendanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_attendance_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkAttendanceResponse.ProtoReflect.Descriptor instead.
+func (*MarkAttendanceResponse) Descriptor() ([]byte, []int) {
+	return file_lms_v1_attendance_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MarkAttendanceResponse) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type MarkLessonRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CourseId   int64                  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	LessonDate string                 `protobuf:"bytes,2,opt,name=lesson_date,json=lessonDate,proto3" json:"lesson_date,omitempty"`
+	// every enrolled student not in items and not marked yet gets this status, if set
+	DefaultStatus string                    `protobuf:"bytes,3,opt,name=default_status,json=defaultStatus,proto3" json:"default_status,omitempty"`
+	Items         []*MarkLessonRequest_Item `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkLessonRequest) Reset() {
+	*x = MarkLessonRequest{}
+	mi := &file_lms_v1_attendance_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkLessonRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkLessonRequest) ProtoMessage() {}
+
+func (x *MarkLessonRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_attendance_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkLessonRequest.ProtoReflect.Descriptor instead.
+func (*MarkLessonRequest) Descriptor() ([]byte, []int) {
+	return file_lms_v1_attendance_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MarkLessonRequest) GetCourseId() int64 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *MarkLessonRequest) GetLessonDate() string {
+	if x != nil {
+		return x.LessonDate
+	}
+	return ""
+}
+
+func (x *MarkLessonRequest) GetDefaultStatus() string {
+	if x != nil {
+		return x.DefaultStatus
+	}
+	return ""
+}
+
+func (x *MarkLessonRequest) GetItems() []*MarkLessonRequest_Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type MarkLessonResponse struct {
+	state         protoimpl.MessageState       `protogen:"open.v1"`
+	Results       []*MarkLessonResponse_Result `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Changed       int32                        `protobuf:"varint,2,opt,name=changed,proto3" json:"changed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkLessonResponse) Reset() {
+	*x = MarkLessonResponse{}
+	mi := &file_lms_v1_attendance_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkLessonResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkLessonResponse) ProtoMessage() {}
+
+func (x *MarkLessonResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_attendance_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkLessonResponse.ProtoReflect.Descriptor instead.
+func (*MarkLessonResponse) Descriptor() ([]byte, []int) {
+	return file_lms_v1_attendance_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MarkLessonResponse) GetResults() []*MarkLessonResponse_Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *MarkLessonResponse) GetChanged() int32 {
+	if x != nil {
+		return x.Changed
+	}
+	return 0
+}
+
+type ListCourseAttendanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CourseId      int64                  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	Page          *PageRequest           `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCourseAttendanceRequest) Reset() {
+	*x = ListCourseAttendanceRequest{}
+	mi := &file_lms_v1_attendance_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCourseAttendanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCourseAttendanceRequest) ProtoMessage() {}
+
+func (x *ListCourseAttendanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_attendance_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCourseAttendanceRequest.ProtoReflect.Descriptor instead.
+func (*ListCourseAttendanceRequest) Descriptor() ([]byte, []int) {
+	return file_lms_v1_attendance_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListCourseAttendanceRequest) GetCourseId() int64 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *ListCourseAttendanceRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+type ListCourseAttendanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*AttendanceRecord    `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCourseAttendanceResponse) Reset() {
+	*x = ListCourseAttendanceResponse{}
+	mi := &file_lms_v1_attendance_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCourseAttendanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCourseAttendanceResponse) ProtoMessage() {}
+
+func (x *ListCourseAttendanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_attendance_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCourseAttendanceResponse.ProtoReflect.Descriptor instead.
+func (*ListCourseAttendanceResponse) Descriptor() ([]byte, []int) {
+	return file_lms_v1_attendance_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListCourseAttendanceResponse) GetRecords() []*AttendanceRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *ListCourseAttendanceResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type ListMyAttendanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          *PageRequest           `protobuf:"bytes,1,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMyAttendanceRequest) Reset() {
+	*x = ListMyAttendanceRequest{}
+	mi := &file_lms_v1_attendance_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMyAttendanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMyAttendanceRequest) ProtoMessage() {}
+
+func (x *ListMyAttendanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_attendance_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMyAttendanceRequest.ProtoReflect.Descriptor instead.
+func (*ListMyAttendanceRequest) Descriptor() ([]byte, []int) {
+	return file_lms_v1_attendance_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListMyAttendanceRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+type ListMyAttendanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*AttendanceRecord    `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMyAttendanceResponse) Reset() {
+	*x = ListMyAttendanceResponse{}
+	mi := &file_lms_v1_attendance_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMyAttendanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMyAttendanceResponse) ProtoMessage() {}
+
+func (x *ListMyAttendanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_attendance_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMyAttendanceResponse.ProtoReflect.Descriptor instead.
+func (*ListMyAttendanceResponse) Descriptor() ([]byte, []int) {
+	return file_lms_v1_attendance_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListMyAttendanceResponse) GetRecords() []*AttendanceRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *ListMyAttendanceResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type StreamCourseAttendanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CourseId      int64                  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	Sort          string                 `protobuf:"bytes,2,opt,name=sort,proto3" json:"sort,omitempty"`
+	Filters       map[string]string      `protobuf:"bytes,3,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamCourseAttendanceRequest) Reset() {
+	*x = StreamCourseAttendanceRequest{}
+	mi := &file_lms_v1_attendance_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamCourseAttendanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamCourseAttendanceRequest) ProtoMessage() {}
+
+func (x *StreamCourseAttendanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_attendance_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamCourseAttendanceRequest.ProtoReflect.Descriptor instead.
+func (*StreamCourseAttendanceRequest) Descriptor() ([]byte, []int) {
+	return file_lms_v1_attendance_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StreamCourseAttendanceRequest) GetCourseId() int64 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *StreamCourseAttendanceRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *StreamCourseAttendanceRequest) GetFilters() map[string]string {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+type StreamCourseAttendanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Record        *AttendanceRecord      `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamCourseAttendanceResponse) Reset() {
+	*x = StreamCourseAttendanceResponse{}
+	mi := &file_lms_v1_attendance_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamCourseAttendanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamCourseAttendanceResponse) ProtoMessage() {}
+
+func (x *StreamCourseAttendanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_attendance_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamCourseAttendanceResponse.ProtoReflect.Descriptor instead.
+func (*StreamCourseAttendanceResponse) Descriptor() ([]byte, []int) {
+	return file_lms_v1_attendance_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StreamCourseAttendanceResponse) GetRecord() *AttendanceRecord {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+type GetHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CourseId      int64                  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	StudentId     int64                  `protobuf:"varint,2,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"` // students may only ask for themselves
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoryRequest) Reset() {
+	*x = GetHistoryRequest{}
+	mi := &file_lms_v1_attendance_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryRequest) ProtoMessage() {}
+
+func (x *GetHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_attendance_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_lms_v1_attendance_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetHistoryRequest) GetCourseId() int64 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *GetHistoryRequest) GetStudentId() int64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+type AttendanceChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	AttendanceId  int64                  `protobuf:"varint,2,opt,name=attendance_id,json=attendanceId,proto3" json:"attendance_id,omitempty"`
+	LessonDate    string                 `protobuf:"bytes,3,opt,name=lesson_date,json=lessonDate,proto3" json:"lesson_date,omitempty"`
+	OldStatus     string                 `protobuf:"bytes,4,opt,name=old_status,json=oldStatus,proto3" json:"old_status,omitempty"`
+	OldNote       string                 `protobuf:"bytes,5,opt,name=old_note,json=oldNote,proto3" json:"old_note,omitempty"`
+	NewStatus     string                 `protobuf:"bytes,6,opt,name=new_status,json=newStatus,proto3" json:"new_status,omitempty"`
+	NewNote       string                 `protobuf:"bytes,7,opt,name=new_note,json=newNote,proto3" json:"new_note,omitempty"`
+	ChangedBy     int64                  `protobuf:"varint,8,opt,name=changed_by,json=changedBy,proto3" json:"changed_by,omitempty"`
+	ChangedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttendanceChange) Reset() {
+	*x = AttendanceChange{}
+	mi := &file_lms_v1_attendance_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttendanceChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttendanceChange) ProtoMessage() {}
+
+func (x *AttendanceChange) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_attendance_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttendanceChange.ProtoReflect.Descriptor instead.
+func (*AttendanceChange) Descriptor() ([]byte, []int) {
+	return file_lms_v1_attendance_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AttendanceChange) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AttendanceChange) GetAttendanceId() int64 {
+	if x != nil {
+		return x.AttendanceId
+	}
+	return 0
+}
+
+func (x *AttendanceChange) GetLessonDate() string {
+	if x != nil {
+		return x.LessonDate
+	}
+	return ""
+}
+
+func (x *AttendanceChange) GetOldStatus() string {
+	if x != nil {
+		return x.OldStatus
+	}
+	return ""
+}
+
+func (x *AttendanceChange) GetOldNote() string {
+	if x != nil {
+		return x.OldNote
+	}
+	return ""
+}
+
+func (x *AttendanceChange) GetNewStatus() string {
+	if x != nil {
+		return x.NewStatus
+	}
+	return ""
+}
+
+func (x *AttendanceChange) GetNewNote() string {
+	if x != nil {
+		return x.NewNote
+	}
+	return ""
+}
+
+func (x *AttendanceChange) GetChangedBy() int64 {
+	if x != nil {
+		return x.ChangedBy
+	}
+	return 0
+}
+
+func (x *AttendanceChange) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+type GetHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*AttendanceChange    `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoryResponse) Reset() {
+	*x = GetHistoryResponse{}
+	mi := &file_lms_v1_attendance_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryResponse) ProtoMessage() {}
+
+func (x *GetHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_attendance_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_lms_v1_attendance_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetHistoryResponse) GetChanges() []*AttendanceChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+type MarkLessonRequest_Item struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	StudentId int64                  `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	Status    string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Note      string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	// version of the mark being changed, as in MarkAttendanceRequest; a
+	// roll call with a wrong or missing one fails with roll_call_conflict
+	Version       *int32 `protobuf:"varint,4,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkLessonRequest_Item) Reset() {
+	*x = MarkLessonRequest_Item{}
+	mi := &file_lms_v1_attendance_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkLessonRequest_Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkLessonRequest_Item) ProtoMessage() {}
+
+func (x *MarkLessonRequest_Item) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_attendance_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkLessonRequest_Item.ProtoReflect.Descriptor instead.
+func (*MarkLessonRequest_Item) Descriptor() ([]byte, []int) {
+	return file_lms_v1_attendance_proto_rawDescGZIP(), []int{3, 0}
+}
+
+func (x *MarkLessonRequest_Item) GetStudentId() int64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *MarkLessonRequest_Item) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *MarkLessonRequest_Item) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *MarkLessonRequest_Item) GetVersion() int32 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type MarkLessonResponse_Result struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StudentId     int64                  `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Changed       bool                   `protobuf:"varint,3,opt,name=changed,proto3" json:"changed,omitempty"`
+	Version       int32                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkLessonResponse_Result) Reset() {
+	*x = MarkLessonResponse_Result{}
+	mi := &file_lms_v1_attendance_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkLessonResponse_Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkLessonResponse_Result) ProtoMessage() {}
+
+func (x *MarkLessonResponse_Result) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_attendance_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkLessonResponse_Result.ProtoReflect.Descriptor instead.
+func (*MarkLessonResponse_Result) Descriptor() ([]byte, []int) {
+	return file_lms_v1_attendance_proto_rawDescGZIP(), []int{4, 0}
+}
+
+func (x *MarkLessonResponse_Result) GetStudentId() int64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *MarkLessonResponse_Result) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *MarkLessonResponse_Result) GetChanged() bool {
+	if x != nil {
+		return x.Changed
+	}
+	return false
+}
+
+func (x *MarkLessonResponse_Result) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+var File_lms_v1_attendance_proto protoreflect.FileDescriptor
+
+const file_lms_v1_attendance_proto_rawDesc = "" +
+	"\n" +
+	"\x17lms/v1/attendance.proto\x12\x06lms.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x13lms/v1/common.proto\"\xc5\x01\n" +
+	"\x10AttendanceRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
+	"\tcourse_id\x18\x02 \x01(\x03R\bcourseId\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x03 \x01(\x03R\tstudentId\x12\x1f\n" +
+	"\vlesson_date\x18\x04 \x01(\tR\n" +
+	"lessonDate\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x12\n" +
+	"\x04note\x18\x06 \x01(\tR\x04note\x12\x18\n" +
+	"\aversion\x18\a \x01(\x05R\aversion\"\xcb\x01\n" +
+	"\x15MarkAttendanceRequest\x12\x1b\n" +
+	"\tcourse_id\x18\x01 \x01(\x03R\bcourseId\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x02 \x01(\x03R\tstudentId\x12\x1f\n" +
+	"\vlesson_date\x18\x03 \x01(\tR\n" +
+	"lessonDate\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x12\n" +
+	"\x04note\x18\x05 \x01(\tR\x04note\x12\x1d\n" +
+	"\aversion\x18\x06 \x01(\x05H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"2\n" +
+	"\x16MarkAttendanceResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x05R\aversion\"\xac\x02\n" +
+	"\x11MarkLessonRequest\x12\x1b\n" +
+	"\tcourse_id\x18\x01 \x01(\x03R\bcourseId\x12\x1f\n" +
+	"\vlesson_date\x18\x02 \x01(\tR\n" +
+	"lessonDate\x12%\n" +
+	"\x0edefault_status\x18\x03 \x01(\tR\rdefaultStatus\x124\n" +
+	"\x05items\x18\x04 \x03(\v2\x1e.lms.v1.MarkLessonRequest.ItemR\x05items\x1a|\n" +
+	"\x04Item\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x01 \x01(\x03R\tstudentId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\x12\x1d\n" +
+	"\aversion\x18\x04 \x01(\x05H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"\xe0\x01\n" +
+	"\x12MarkLessonResponse\x12;\n" +
+	"\aresults\x18\x01 \x03(\v2!.lms.v1.MarkLessonResponse.ResultR\aresults\x12\x18\n" +
+	"\achanged\x18\x02 \x01(\x05R\achanged\x1as\n" +
+	"\x06Result\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x01 \x01(\x03R\tstudentId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x18\n" +
+	"\achanged\x18\x03 \x01(\bR\achanged\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x05R\aversion\"c\n" +
+	"\x1bListCourseAttendanceRequest\x12\x1b\n" +
+	"\tcourse_id\x18\x01 \x01(\x03R\bcourseId\x12'\n" +
+	"\x04page\x18\x02 \x01(\v2\x13.lms.v1.PageRequestR\x04page\"s\n" +
+	"\x1cListCourseAttendanceResponse\x122\n" +
+	"\arecords\x18\x01 \x03(\v2\x18.lms.v1.AttendanceRecordR\arecords\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"B\n" +
+	"\x17ListMyAttendanceRequest\x12'\n" +
+	"\x04page\x18\x01 \x01(\v2\x13.lms.v1.PageRequestR\x04page\"o\n" +
+	"\x18ListMyAttendanceResponse\x122\n" +
+	"\arecords\x18\x01 \x03(\v2\x18.lms.v1.AttendanceRecordR\arecords\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\xda\x01\n" +
+	"\x1dStreamCourseAttendanceRequest\x12\x1b\n" +
+	"\tcourse_id\x18\x01 \x01(\x03R\bcourseId\x12\x12\n" +
+	"\x04sort\x18\x02 \x01(\tR\x04sort\x12L\n" +
+	"\afilters\x18\x03 \x03(\v22.lms.v1.StreamCourseAttendanceRequest.FiltersEntryR\afilters\x1a:\n" +
+	"\fFiltersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"R\n" +
+	"\x1eStreamCourseAttendanceResponse\x120\n" +
+	"\x06record\x18\x01 \x01(\v2\x18.lms.v1.AttendanceRecordR\x06record\"O\n" +
+	"\x11GetHistoryRequest\x12\x1b\n" +
+	"\tcourse_id\x18\x01 \x01(\x03R\bcourseId\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x02 \x01(\x03R\tstudentId\"\xb6\x02\n" +
+	"\x10AttendanceChange\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12#\n" +
+	"\rattendance_id\x18\x02 \x01(\x03R\fattendanceId\x12\x1f\n" +
+	"\vlesson_date\x18\x03 \x01(\tR\n" +
+	"lessonDate\x12\x1d\n" +
+	"\n" +
+	"old_status\x18\x04 \x01(\tR\toldStatus\x12\x19\n" +
+	"\bold_note\x18\x05 \x01(\tR\aoldNote\x12\x1d\n" +
+	"\n" +
+	"new_status\x18\x06 \x01(\tR\tnewStatus\x12\x19\n" +
+	"\bnew_note\x18\a \x01(\tR\anewNote\x12\x1d\n" +
+	"\n" +
+	"changed_by\x18\b \x01(\x03R\tchangedBy\x129\n" +
+	"\n" +
+	"changed_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt\"H\n" +
+	"\x12GetHistoryResponse\x122\n" +
+	"\achanges\x18\x01 \x03(\v2\x18.lms.v1.AttendanceChangeR\achanges2\x93\x04\n" +
+	"\x11AttendanceService\x12O\n" +
+	"\x0eMarkAttendance\x12\x1d.lms.v1.MarkAttendanceRequest\x1a\x1e.lms.v1.MarkAttendanceResponse\x12C\n" +
+	"\n" +
+	"MarkLesson\x12\x19.lms.v1.MarkLessonRequest\x1a\x1a.lms.v1.MarkLessonResponse\x12a\n" +
+	"\x14ListCourseAttendance\x12#.lms.v1.ListCourseAttendanceRequest\x1a$.lms.v1.ListCourseAttendanceResponse\x12U\n" +
+	"\x10ListMyAttendance\x12\x1f.lms.v1.ListMyAttendanceRequest\x1a .lms.v1.ListMyAttendanceResponse\x12i\n" +
+	"\x16StreamCourseAttendance\x12%.lms.v1.StreamCourseAttendanceRequest\x1a&.lms.v1.StreamCourseAttendanceResponse0\x01\x12C\n" +
+	"\n" +
+	"GetHistory\x12\x19.lms.v1.GetHistoryRequest\x1a\x1a.lms.v1.GetHistoryResponseB+Z)lms-backend/internal/transport/grpc/lmsv1b\x06proto3"
+
+var (
+	file_lms_v1_attendance_proto_rawDescOnce sync.Once
+	file_lms_v1_attendance_proto_rawDescData []byte
+)
+
+func file_lms_v1_attendance_proto_rawDescGZIP() []byte {
+	file_lms_v1_attendance_proto_rawDescOnce.Do(func() {
+		file_lms_v1_attendance_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_lms_v1_attendance_proto_rawDesc), len(file_lms_v1_attendance_proto_rawDesc)))
+	})
+	return file_lms_v1_attendance_proto_rawDescData
+}
+
+var file_lms_v1_attendance_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_lms_v1_attendance_proto_goTypes = []any{
+	(*AttendanceRecord)(nil),               // 0: lms.v1.AttendanceRecord
+	(*MarkAttendanceRequest)(nil),          // 1: lms.v1.MarkAttendanceRequest
+	(*MarkAttendanceResponse)(nil),         // 2: lms.v1.MarkAttendanceResponse
+	(*MarkLessonRequest)(nil),              // 3: lms.v1.MarkLessonRequest
+	(*MarkLessonResponse)(nil),             // 4: lms.v1.MarkLessonResponse
+	(*ListCourseAttendanceRequest)(nil),    // 5: lms.v1.ListCourseAttendanceRequest
+	(*ListCourseAttendanceResponse)(nil),   // 6: lms.v1.ListCourseAttendanceResponse
+	(*ListMyAttendanceRequest)(nil),        // 7: lms.v1.ListMyAttendanceRequest
+	(*ListMyAttendanceResponse)(nil),       // 8: lms.v1.ListMyAttendanceResponse
+	(*StreamCourseAttendanceRequest)(nil),  // 9: lms.v1.StreamCourseAttendanceRequest
+	(*StreamCourseAttendanceResponse)(nil), // 10: lms.v1.StreamCourseAttendanceResponse
+	(*GetHistoryRequest)(nil),              // 11: lms.v1.GetHistoryRequest
+	(*AttendanceChange)(nil),               // 12: lms.v1.AttendanceChange
+	(*GetHistoryResponse)(nil),             // 13: lms.v1.GetHistoryResponse
+	(*MarkLessonRequest_Item)(nil),         // 14: lms.v1.MarkLessonRequest.Item
+	(*MarkLessonResponse_Result)(nil),      // 15: lms.v1.MarkLessonResponse.Result
+	nil,                                    // 16: lms.v1.StreamCourseAttendanceRequest.FiltersEntry
+	(*PageRequest)(nil),                    // 17: lms.v1.PageRequest
+	(*timestamppb.Timestamp)(nil),          // 18: google.protobuf.Timestamp
+}
+var file_lms_v1_attendance_proto_depIdxs = []int32{
+	14, // 0: lms.v1.MarkLessonRequest.items:type_name -> lms.v1.MarkLessonRequest.Item
+	15, // 1: lms.v1.MarkLessonResponse.results:type_name -> lms.v1.MarkLessonResponse.Result
+	17, // 2: lms.v1.ListCourseAttendanceRequest.page:type_name -> lms.v1.PageRequest
+	0,  // 3: lms.v1.ListCourseAttendanceResponse.records:type_name -> lms.v1.AttendanceRecord
+	17, // 4: lms.v1.ListMyAttendanceRequest.page:type_name -> lms.v1.PageRequest
+	0,  // 5: lms.v1.ListMyAttendanceResponse.records:type_name -> lms.v1.AttendanceRecord
+	16, // 6: lms.v1.StreamCourseAttendanceRequest.filters:type_name -> lms.v1.StreamCourseAttendanceRequest.FiltersEntry
+	0,  // 7: lms.v1.StreamCourseAttendanceResponse.record:type_name -> lms.v1.AttendanceRecord
+	18, // 8: lms.v1.AttendanceChange.changed_at:type_name -> google.protobuf.Timestamp
+	12, // 9: lms.v1.GetHistoryResponse.changes:type_name -> lms.v1.AttendanceChange
+	1,  // 10: lms.v1.AttendanceService.MarkAttendance:input_type -> lms.v1.MarkAttendanceRequest
+	3,  // 11: lms.v1.AttendanceService.MarkLesson:input_type -> lms.v1.MarkLessonRequest
+	5,  // 12: lms.v1.AttendanceService.ListCourseAttendance:input_type -> lms.v1.ListCourseAttendanceRequest
+	7,  // 13: lms.v1.AttendanceService.ListMyAttendance:input_type -> lms.v1.ListMyAttendanceRequest
+	9,  // 14: lms.v1.AttendanceService.StreamCourseAttendance:input_type -> lms.v1.StreamCourseAttendanceRequest
+	11, // 15: lms.v1.AttendanceService.GetHistory:input_type -> lms.v1.GetHistoryRequest
+	2,  // 16: lms.v1.AttendanceService.MarkAttendance:output_type -> lms.v1.MarkAttendanceResponse
+	4,  // 17: lms.v1.AttendanceService.MarkLesson:output_type -> lms.v1.MarkLessonResponse
+	6,  // 18: lms.v1.AttendanceService.ListCourseAttendance:output_type -> lms.v1.ListCourseAttendanceResponse
+	8,  // 19: lms.v1.AttendanceService.ListMyAttendance:output_type -> lms.v1.ListMyAttendanceResponse
+	10, // 20: lms.v1.AttendanceService.StreamCourseAttendance:output_type -> lms.v1.StreamCourseAttendanceResponse
+	13, // 21: lms.v1.AttendanceService.GetHistory:output_type -> lms.v1.GetHistoryResponse
+	16, // [16:22] is the sub-list for method output_type
+	10, // [10:16] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_lms_v1_attendance_proto_init() }
+func file_lms_v1_attendance_proto_init() {
+	if File_lms_v1_attendance_proto != nil {
+		return
+	}
+	file_lms_v1_common_proto_init()
+	file_lms_v1_attendance_proto_msgTypes[1].OneofWrappers = []any{}
+	file_lms_v1_attendance_proto_msgTypes[14].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_lms_v1_attendance_proto_rawDesc), len(file_lms_v1_attendance_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_lms_v1_attendance_proto_goTypes,
+		DependencyIndexes: file_lms_v1_attendance_proto_depIdxs,
+		MessageInfos:      file_lms_v1_attendance_proto_msgTypes,
+	}.Build()
+	File_lms_v1_attendance_proto = out.File
+	file_lms_v1_attendance_proto_goTypes = nil
+	file_lms_v1_attendance_proto_depIdxs = nil
+}
@@ -0,0 +1,327 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: lms/v1/attendance.proto
+
+package lmsv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AttendanceService_MarkAttendance_FullMethodName         = "/lms.v1.AttendanceService/MarkAttendance"
+	AttendanceService_MarkLesson_FullMethodName             = "/lms.v1.AttendanceService/MarkLesson"
+	AttendanceService_ListCourseAttendance_FullMethodName   = "/lms.v1.AttendanceService/ListCourseAttendance"
+	AttendanceService_ListMyAttendance_FullMethodName       = "/lms.v1.AttendanceService/ListMyAttendance"
+	AttendanceService_StreamCourseAttendance_FullMethodName = "/lms.v1.AttendanceService/StreamCourseAttendance"
+	AttendanceService_GetHistory_FullMethodName             = "/lms.v1.AttendanceService/GetHistory"
+)
+
+// AttendanceServiceClient is the client API for AttendanceService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AttendanceService marks and reads attendance. Lesson dates are YYYY-MM-DD.
+type AttendanceServiceClient interface {
+	MarkAttendance(ctx context.Context, in *MarkAttendanceRequest, opts ...grpc.CallOption) (*MarkAttendanceResponse, error)
+	// MarkLesson is a roll call: all rows are saved or, when one is invalid,
+	// none; the rejection carries a BadRequest detail per invalid row.
+	MarkLesson(ctx context.Context, in *MarkLessonRequest, opts ...grpc.CallOption) (*MarkLessonResponse, error)
+	ListCourseAttendance(ctx context.Context, in *ListCourseAttendanceRequest, opts ...grpc.CallOption) (*ListCourseAttendanceResponse, error)
+	ListMyAttendance(ctx context.Context, in *ListMyAttendanceRequest, opts ...grpc.CallOption) (*ListMyAttendanceResponse, error)
+	// StreamCourseAttendance sends every record of a course matching the
+	// filters, without paging.
+	StreamCourseAttendance(ctx context.Context, in *StreamCourseAttendanceRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamCourseAttendanceResponse], error)
+	GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error)
+}
+
+type attendanceServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAttendanceServiceClient(cc grpc.ClientConnInterface) AttendanceServiceClient {
+	return &attendanceServiceClient{cc}
+}
+
+func (c *attendanceServiceClient) MarkAttendance(ctx context.Context, in *MarkAttendanceRequest, opts ...grpc.CallOption) (*MarkAttendanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MarkAttendanceResponse)
+	err := c.cc.Invoke(ctx, AttendanceService_MarkAttendance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *attendanceServiceClient) MarkLesson(ctx context.Context, in *MarkLessonRequest, opts ...grpc.CallOption) (*MarkLessonResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MarkLessonResponse)
+	err := c.cc.Invoke(ctx, AttendanceService_MarkLesson_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *attendanceServiceClient) ListCourseAttendance(ctx context.Context, in *ListCourseAttendanceRequest, opts ...grpc.CallOption) (*ListCourseAttendanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCourseAttendanceResponse)
+	err := c.cc.Invoke(ctx, AttendanceService_ListCourseAttendance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *attendanceServiceClient) ListMyAttendance(ctx context.Context, in *ListMyAttendanceRequest, opts ...grpc.CallOption) (*ListMyAttendanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMyAttendanceResponse)
+	err := c.cc.Invoke(ctx, AttendanceService_ListMyAttendance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *attendanceServiceClient) StreamCourseAttendance(ctx context.Context, in *StreamCourseAttendanceRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamCourseAttendanceResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AttendanceService_ServiceDesc.Streams[0], AttendanceService_StreamCourseAttendance_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamCourseAttendanceRequest, StreamCourseAttendanceResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AttendanceService_StreamCourseAttendanceClient = grpc.ServerStreamingClient[StreamCourseAttendanceResponse]
+
+func (c *attendanceServiceClient) GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetHistoryResponse)
+	err := c.cc.Invoke(ctx, AttendanceService_GetHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AttendanceServiceServer is the server API for AttendanceService service.
+// All implementations must embed UnimplementedAttendanceServiceServer
+// for forward compatibility.
+//
+// AttendanceService marks and reads attendance. Lesson dates are YYYY-MM-DD.
+type AttendanceServiceServer interface {
+	MarkAttendance(context.Context, *MarkAttendanceRequest) (*MarkAttendanceResponse, error)
+	// MarkLesson is a roll call: all rows are saved or, when one is invalid,
+	// none; the rejection carries a BadRequest detail per invalid row.
+	MarkLesson(context.Context, *MarkLessonRequest) (*MarkLessonResponse, error)
+	ListCourseAttendance(context.Context, *ListCourseAttendanceRequest) (*ListCourseAttendanceResponse, error)
+	ListMyAttendance(context.Context, *ListMyAttendanceRequest) (*ListMyAttendanceResponse, error)
+	// StreamCourseAttendance sends every record of a course matching the
+	// filters, without paging.
+	StreamCourseAttendance(*StreamCourseAttendanceRequest, grpc.ServerStreamingServer[StreamCourseAttendanceResponse]) error
+	GetHistory(context.Context, *GetHistoryRequest) (*GetHistoryResponse, error)
+	mustEmbedUnimplementedAttendanceServiceServer()
+}
+
+// UnimplementedAttendanceServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAttendanceServiceServer struct{}
+
+func (UnimplementedAttendanceServiceServer) MarkAttendance(context.Context, *MarkAttendanceRequest) (*MarkAttendanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkAttendance not implemented")
+}
+func (UnimplementedAttendanceServiceServer) MarkLesson(context.Context, *MarkLessonRequest) (*MarkLessonResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkLesson not implemented")
+}
+func (UnimplementedAttendanceServiceServer) ListCourseAttendance(context.Context, *ListCourseAttendanceRequest) (*ListCourseAttendanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCourseAttendance not implemented")
+}
+func (UnimplementedAttendanceServiceServer) ListMyAttendance(context.Context, *ListMyAttendanceRequest) (*ListMyAttendanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMyAttendance not implemented")
+}
+func (UnimplementedAttendanceServiceServer) StreamCourseAttendance(*StreamCourseAttendanceRequest, grpc.ServerStreamingServer[StreamCourseAttendanceResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamCourseAttendance not implemented")
+}
+func (UnimplementedAttendanceServiceServer) GetHistory(context.Context, *GetHistoryRequest) (*GetHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistory not implemented")
+}
+func (UnimplementedAttendanceServiceServer) mustEmbedUnimplementedAttendanceServiceServer() {}
+func (UnimplementedAttendanceServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeAttendanceServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AttendanceServiceServer will
+// result in compilation errors.
+type UnsafeAttendanceServiceServer interface {
+	mustEmbedUnimplementedAttendanceServiceServer()
+}
+
+func RegisterAttendanceServiceServer(s grpc.ServiceRegistrar, srv AttendanceServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAttendanceServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AttendanceService_ServiceDesc, srv)
+}
+
+func _AttendanceService_MarkAttendance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkAttendanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AttendanceServiceServer).MarkAttendance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AttendanceService_MarkAttendance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AttendanceServiceServer).MarkAttendance(ctx, req.(*MarkAttendanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AttendanceService_MarkLesson_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkLessonRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AttendanceServiceServer).MarkLesson(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AttendanceService_MarkLesson_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AttendanceServiceServer).MarkLesson(ctx, req.(*MarkLessonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AttendanceService_ListCourseAttendance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCourseAttendanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AttendanceServiceServer).ListCourseAttendance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AttendanceService_ListCourseAttendance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AttendanceServiceServer).ListCourseAttendance(ctx, req.(*ListCourseAttendanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AttendanceService_ListMyAttendance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMyAttendanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AttendanceServiceServer).ListMyAttendance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AttendanceService_ListMyAttendance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AttendanceServiceServer).ListMyAttendance(ctx, req.(*ListMyAttendanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AttendanceService_StreamCourseAttendance_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamCourseAttendanceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AttendanceServiceServer).StreamCourseAttendance(m, &grpc.GenericServerStream[StreamCourseAttendanceRequest, StreamCourseAttendanceResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AttendanceService_StreamCourseAttendanceServer = grpc.ServerStreamingServer[StreamCourseAttendanceResponse]
+
+func _AttendanceService_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AttendanceServiceServer).GetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AttendanceService_GetHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AttendanceServiceServer).GetHistory(ctx, req.(*GetHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AttendanceService_ServiceDesc is the grpc.ServiceDesc for AttendanceService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AttendanceService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "lms.v1.AttendanceService",
+	HandlerType: (*AttendanceServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "MarkAttendance",
+			Handler:    _AttendanceService_MarkAttendance_Handler,
+		},
+		{
+			MethodName: "MarkLesson",
+			Handler:    _AttendanceService_MarkLesson_Handler,
+		},
+		{
+			MethodName: "ListCourseAttendance",
+			Handler:    _AttendanceService_ListCourseAttendance_Handler,
+		},
+		{
+			MethodName: "ListMyAttendance",
+			Handler:    _AttendanceService_ListMyAttendance_Handler,
+		},
+		{
+			MethodName: "GetHistory",
+			Handler:    _AttendanceService_GetHistory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamCourseAttendance",
+			Handler:       _AttendanceService_StreamCourseAttendance_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "lms/v1/attendance.proto",
+}
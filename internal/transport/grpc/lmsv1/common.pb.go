@@ -0,0 +1,156 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: lms/v1/common.proto
+
+package lmsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PageRequest asks for one page of a list, like ?limit=&cursor=&sort= and
+// the filter parameters of the REST API.
+type PageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`  // 1..200, 0 = 50
+	Cursor        string                 `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"` // next_cursor of the previous page
+	Sort          string                 `protobuf:"bytes,3,opt,name=sort,proto3" json:"sort,omitempty"`     // "field" ascending, "-field" descending
+	Filters       map[string]string      `protobuf:"bytes,4,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PageRequest) Reset() {
+	*x = PageRequest{}
+	mi := &file_lms_v1_common_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PageRequest) ProtoMessage() {}
+
+func (x *PageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_common_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PageRequest.ProtoReflect.Descriptor instead.
+func (*PageRequest) Descriptor() ([]byte, []int) {
+	return file_lms_v1_common_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PageRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *PageRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *PageRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *PageRequest) GetFilters() map[string]string {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+var File_lms_v1_common_proto protoreflect.FileDescriptor
+
+const file_lms_v1_common_proto_rawDesc = "" +
+	"\n" +
+	"\x13lms/v1/common.proto\x12\x06lms.v1\"\xc7\x01\n" +
+	"\vPageRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\x12\x12\n" +
+	"\x04sort\x18\x03 \x01(\tR\x04sort\x12:\n" +
+	"\afilters\x18\x04 \x03(\v2 .lms.v1.PageRequest.FiltersEntryR\afilters\x1a:\n" +
+	"\fFiltersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B+Z)lms-backend/internal/transport/grpc/lmsv1b\x06proto3"
+
+var (
+	file_lms_v1_common_proto_rawDescOnce sync.Once
+	file_lms_v1_common_proto_rawDescData []byte
+)
+
+func file_lms_v1_common_proto_rawDescGZIP() []byte {
+	file_lms_v1_common_proto_rawDescOnce.Do(func() {
+		file_lms_v1_common_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_lms_v1_common_proto_rawDesc), len(file_lms_v1_common_proto_rawDesc)))
+	})
+	return file_lms_v1_common_proto_rawDescData
+}
+
+var file_lms_v1_common_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_lms_v1_common_proto_goTypes = []any{
+	(*PageRequest)(nil), // 0: lms.v1.PageRequest
+	nil,                 // 1: lms.v1.PageRequest.FiltersEntry
+}
+var file_lms_v1_common_proto_depIdxs = []int32{
+	1, // 0: lms.v1.PageRequest.filters:type_name -> lms.v1.PageRequest.FiltersEntry
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_lms_v1_common_proto_init() }
+func file_lms_v1_common_proto_init() {
+	if File_lms_v1_common_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_lms_v1_common_proto_rawDesc), len(file_lms_v1_common_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_lms_v1_common_proto_goTypes,
+		DependencyIndexes: file_lms_v1_common_proto_depIdxs,
+		MessageInfos:      file_lms_v1_common_proto_msgTypes,
+	}.Build()
+	File_lms_v1_common_proto = out.File
+	file_lms_v1_common_proto_goTypes = nil
+	file_lms_v1_common_proto_depIdxs = nil
+}
@@ -0,0 +1,719 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: lms/v1/courses.proto
+
+package lmsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Course struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	TeacherId     int64                  `protobuf:"varint,3,opt,name=teacher_id,json=teacherId,proto3" json:"teacher_id,omitempty"`
+	Term          string                 `protobuf:"bytes,4,opt,name=term,proto3" json:"term,omitempty"`
+	Credits       float64                `protobuf:"fixed64,5,opt,name=credits,proto3" json:"credits,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Course) Reset() {
+	*x = Course{}
+	mi := &file_lms_v1_courses_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Course) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Course) ProtoMessage() {}
+
+func (x *Course) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_courses_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Course.ProtoReflect.Descriptor instead.
+func (*Course) Descriptor() ([]byte, []int) {
+	return file_lms_v1_courses_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Course) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Course) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Course) GetTeacherId() int64 {
+	if x != nil {
+		return x.TeacherId
+	}
+	return 0
+}
+
+func (x *Course) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *Course) GetCredits() float64 {
+	if x != nil {
+		return x.Credits
+	}
+	return 0
+}
+
+func (x *Course) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListCoursesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          *PageRequest           `protobuf:"bytes,1,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCoursesRequest) Reset() {
+	*x = ListCoursesRequest{}
+	mi := &file_lms_v1_courses_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCoursesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCoursesRequest) ProtoMessage() {}
+
+func (x *ListCoursesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_courses_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCoursesRequest.ProtoReflect.Descriptor instead.
+func (*ListCoursesRequest) Descriptor() ([]byte, []int) {
+	return file_lms_v1_courses_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListCoursesRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+type ListMyCoursesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          *PageRequest           `protobuf:"bytes,1,opt,name=page,proto3" json:"page,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // students: enrollment status, default active
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMyCoursesRequest) Reset() {
+	*x = ListMyCoursesRequest{}
+	mi := &file_lms_v1_courses_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMyCoursesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMyCoursesRequest) ProtoMessage() {}
+
+func (x *ListMyCoursesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_courses_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMyCoursesRequest.ProtoReflect.Descriptor instead.
+func (*ListMyCoursesRequest) Descriptor() ([]byte, []int) {
+	return file_lms_v1_courses_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListMyCoursesRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+func (x *ListMyCoursesRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ListCoursesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Courses       []*Course              `protobuf:"bytes,1,rep,name=courses,proto3" json:"courses,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCoursesResponse) Reset() {
+	*x = ListCoursesResponse{}
+	mi := &file_lms_v1_courses_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCoursesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCoursesResponse) ProtoMessage() {}
+
+func (x *ListCoursesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_courses_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCoursesResponse.ProtoReflect.Descriptor instead.
+func (*ListCoursesResponse) Descriptor() ([]byte, []int) {
+	return file_lms_v1_courses_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListCoursesResponse) GetCourses() []*Course {
+	if x != nil {
+		return x.Courses
+	}
+	return nil
+}
+
+func (x *ListCoursesResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type ListMyCoursesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Courses       []*Course              `protobuf:"bytes,1,rep,name=courses,proto3" json:"courses,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMyCoursesResponse) Reset() {
+	*x = ListMyCoursesResponse{}
+	mi := &file_lms_v1_courses_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMyCoursesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMyCoursesResponse) ProtoMessage() {}
+
+func (x *ListMyCoursesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_courses_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMyCoursesResponse.ProtoReflect.Descriptor instead.
+func (*ListMyCoursesResponse) Descriptor() ([]byte, []int) {
+	return file_lms_v1_courses_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListMyCoursesResponse) GetCourses() []*Course {
+	if x != nil {
+		return x.Courses
+	}
+	return nil
+}
+
+func (x *ListMyCoursesResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type CreateCourseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	TeacherId     int64                  `protobuf:"varint,2,opt,name=teacher_id,json=teacherId,proto3" json:"teacher_id,omitempty"` // 0 = the caller
+	Term          string                 `protobuf:"bytes,3,opt,name=term,proto3" json:"term,omitempty"`
+	Credits       float64                `protobuf:"fixed64,4,opt,name=credits,proto3" json:"credits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCourseRequest) Reset() {
+	*x = CreateCourseRequest{}
+	mi := &file_lms_v1_courses_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCourseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCourseRequest) ProtoMessage() {}
+
+func (x *CreateCourseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_courses_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCourseRequest.ProtoReflect.Descriptor instead.
+func (*CreateCourseRequest) Descriptor() ([]byte, []int) {
+	return file_lms_v1_courses_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateCourseRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateCourseRequest) GetTeacherId() int64 {
+	if x != nil {
+		return x.TeacherId
+	}
+	return 0
+}
+
+func (x *CreateCourseRequest) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *CreateCourseRequest) GetCredits() float64 {
+	if x != nil {
+		return x.Credits
+	}
+	return 0
+}
+
+type CreateCourseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCourseResponse) Reset() {
+	*x = CreateCourseResponse{}
+	mi := &file_lms_v1_courses_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCourseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCourseResponse) ProtoMessage() {}
+
+func (x *CreateCourseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_courses_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCourseResponse.ProtoReflect.Descriptor instead.
+func (*CreateCourseResponse) Descriptor() ([]byte, []int) {
+	return file_lms_v1_courses_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateCourseResponse) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListStudentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CourseId      int64                  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // enrollment status, default active
+	Page          *PageRequest           `protobuf:"bytes,3,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStudentsRequest) Reset() {
+	*x = ListStudentsRequest{}
+	mi := &file_lms_v1_courses_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStudentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStudentsRequest) ProtoMessage() {}
+
+func (x *ListStudentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_courses_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStudentsRequest.ProtoReflect.Descriptor instead.
+func (*ListStudentsRequest) Descriptor() ([]byte, []int) {
+	return file_lms_v1_courses_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListStudentsRequest) GetCourseId() int64 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *ListStudentsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListStudentsRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+type Student struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	FullName      string                 `protobuf:"bytes,2,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Student) Reset() {
+	*x = Student{}
+	mi := &file_lms_v1_courses_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Student) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Student) ProtoMessage() {}
+
+func (x *Student) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_courses_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Student.ProtoReflect.Descriptor instead.
+func (*Student) Descriptor() ([]byte, []int) {
+	return file_lms_v1_courses_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Student) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Student) GetFullName() string {
+	if x != nil {
+		return x.FullName
+	}
+	return ""
+}
+
+func (x *Student) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type ListStudentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Students      []*Student             `protobuf:"bytes,1,rep,name=students,proto3" json:"students,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStudentsResponse) Reset() {
+	*x = ListStudentsResponse{}
+	mi := &file_lms_v1_courses_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStudentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStudentsResponse) ProtoMessage() {}
+
+func (x *ListStudentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_courses_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStudentsResponse.ProtoReflect.Descriptor instead.
+func (*ListStudentsResponse) Descriptor() ([]byte, []int) {
+	return file_lms_v1_courses_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListStudentsResponse) GetStudents() []*Student {
+	if x != nil {
+		return x.Students
+	}
+	return nil
+}
+
+func (x *ListStudentsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+var File_lms_v1_courses_proto protoreflect.FileDescriptor
+
+const file_lms_v1_courses_proto_rawDesc = "" +
+	"\n" +
+	"\x14lms/v1/courses.proto\x12\x06lms.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x13lms/v1/common.proto\"\xb6\x01\n" +
+	"\x06Course\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1d\n" +
+	"\n" +
+	"teacher_id\x18\x03 \x01(\x03R\tteacherId\x12\x12\n" +
+	"\x04term\x18\x04 \x01(\tR\x04term\x12\x18\n" +
+	"\acredits\x18\x05 \x01(\x01R\acredits\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"=\n" +
+	"\x12ListCoursesRequest\x12'\n" +
+	"\x04page\x18\x01 \x01(\v2\x13.lms.v1.PageRequestR\x04page\"W\n" +
+	"\x14ListMyCoursesRequest\x12'\n" +
+	"\x04page\x18\x01 \x01(\v2\x13.lms.v1.PageRequestR\x04page\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"`\n" +
+	"\x13ListCoursesResponse\x12(\n" +
+	"\acourses\x18\x01 \x03(\v2\x0e.lms.v1.CourseR\acourses\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"b\n" +
+	"\x15ListMyCoursesResponse\x12(\n" +
+	"\acourses\x18\x01 \x03(\v2\x0e.lms.v1.CourseR\acourses\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"x\n" +
+	"\x13CreateCourseRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x1d\n" +
+	"\n" +
+	"teacher_id\x18\x02 \x01(\x03R\tteacherId\x12\x12\n" +
+	"\x04term\x18\x03 \x01(\tR\x04term\x12\x18\n" +
+	"\acredits\x18\x04 \x01(\x01R\acredits\"&\n" +
+	"\x14CreateCourseResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"s\n" +
+	"\x13ListStudentsRequest\x12\x1b\n" +
+	"\tcourse_id\x18\x01 \x01(\x03R\bcourseId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12'\n" +
+	"\x04page\x18\x03 \x01(\v2\x13.lms.v1.PageRequestR\x04page\"L\n" +
+	"\aStudent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
+	"\tfull_name\x18\x02 \x01(\tR\bfullName\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\"d\n" +
+	"\x14ListStudentsResponse\x12+\n" +
+	"\bstudents\x18\x01 \x03(\v2\x0f.lms.v1.StudentR\bstudents\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor2\xbb\x02\n" +
+	"\rCourseService\x12F\n" +
+	"\vListCourses\x12\x1a.lms.v1.ListCoursesRequest\x1a\x1b.lms.v1.ListCoursesResponse\x12L\n" +
+	"\rListMyCourses\x12\x1c.lms.v1.ListMyCoursesRequest\x1a\x1d.lms.v1.ListMyCoursesResponse\x12I\n" +
+	"\fCreateCourse\x12\x1b.lms.v1.CreateCourseRequest\x1a\x1c.lms.v1.CreateCourseResponse\x12I\n" +
+	"\fListStudents\x12\x1b.lms.v1.ListStudentsRequest\x1a\x1c.lms.v1.ListStudentsResponseB+Z)lms-backend/internal/transport/grpc/lmsv1b\x06proto3"
+
+var (
+	file_lms_v1_courses_proto_rawDescOnce sync.Once
+	file_lms_v1_courses_proto_rawDescData []byte
+)
+
+func file_lms_v1_courses_proto_rawDescGZIP() []byte {
+	file_lms_v1_courses_proto_rawDescOnce.Do(func() {
+		file_lms_v1_courses_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_lms_v1_courses_proto_rawDesc), len(file_lms_v1_courses_proto_rawDesc)))
+	})
+	return file_lms_v1_courses_proto_rawDescData
+}
+
+var file_lms_v1_courses_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_lms_v1_courses_proto_goTypes = []any{
+	(*Course)(nil),                // 0: lms.v1.Course
+	(*ListCoursesRequest)(nil),    // 1: lms.v1.ListCoursesRequest
+	(*ListMyCoursesRequest)(nil),  // 2: lms.v1.ListMyCoursesRequest
+	(*ListCoursesResponse)(nil),   // 3: lms.v1.ListCoursesResponse
+	(*ListMyCoursesResponse)(nil), // 4: lms.v1.ListMyCoursesResponse
+	(*CreateCourseRequest)(nil),   // 5: lms.v1.CreateCourseRequest
+	(*CreateCourseResponse)(nil),  // 6: lms.v1.CreateCourseResponse
+	(*ListStudentsRequest)(nil),   // 7: lms.v1.ListStudentsRequest
+	(*Student)(nil),               // 8: lms.v1.Student
+	(*ListStudentsResponse)(nil),  // 9: lms.v1.ListStudentsResponse
+	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
+	(*PageRequest)(nil),           // 11: lms.v1.PageRequest
+}
+var file_lms_v1_courses_proto_depIdxs = []int32{
+	10, // 0: lms.v1.Course.created_at:type_name -> google.protobuf.Timestamp
+	11, // 1: lms.v1.ListCoursesRequest.page:type_name -> lms.v1.PageRequest
+	11, // 2: lms.v1.ListMyCoursesRequest.page:type_name -> lms.v1.PageRequest
+	0,  // 3: lms.v1.ListCoursesResponse.courses:type_name -> lms.v1.Course
+	0,  // 4: lms.v1.ListMyCoursesResponse.courses:type_name -> lms.v1.Course
+	11, // 5: lms.v1.ListStudentsRequest.page:type_name -> lms.v1.PageRequest
+	8,  // 6: lms.v1.ListStudentsResponse.students:type_name -> lms.v1.Student
+	1,  // 7: lms.v1.CourseService.ListCourses:input_type -> lms.v1.ListCoursesRequest
+	2,  // 8: lms.v1.CourseService.ListMyCourses:input_type -> lms.v1.ListMyCoursesRequest
+	5,  // 9: lms.v1.CourseService.CreateCourse:input_type -> lms.v1.CreateCourseRequest
+	7,  // 10: lms.v1.CourseService.ListStudents:input_type -> lms.v1.ListStudentsRequest
+	3,  // 11: lms.v1.CourseService.ListCourses:output_type -> lms.v1.ListCoursesResponse
+	4,  // 12: lms.v1.CourseService.ListMyCourses:output_type -> lms.v1.ListMyCoursesResponse
+	6,  // 13: lms.v1.CourseService.CreateCourse:output_type -> lms.v1.CreateCourseResponse
+	9,  // 14: lms.v1.CourseService.ListStudents:output_type -> lms.v1.ListStudentsResponse
+	11, // [11:15] is the sub-list for method output_type
+	7,  // [7:11] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_lms_v1_courses_proto_init() }
+func file_lms_v1_courses_proto_init() {
+	if File_lms_v1_courses_proto != nil {
+		return
+	}
+	file_lms_v1_common_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_lms_v1_courses_proto_rawDesc), len(file_lms_v1_courses_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_lms_v1_courses_proto_goTypes,
+		DependencyIndexes: file_lms_v1_courses_proto_depIdxs,
+		MessageInfos:      file_lms_v1_courses_proto_msgTypes,
+	}.Build()
+	File_lms_v1_courses_proto = out.File
+	file_lms_v1_courses_proto_goTypes = nil
+	file_lms_v1_courses_proto_depIdxs = nil
+}
@@ -0,0 +1,813 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: lms/v1/enrollments.proto
+
+package lmsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EnrollRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CourseId      int64                  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	StudentId     int64                  `protobuf:"varint,2,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	Override      bool                   `protobuf:"varint,3,opt,name=override,proto3" json:"override,omitempty"` // admins only: skip requirement checks
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollRequest) Reset() {
+	*x = EnrollRequest{}
+	mi := &file_lms_v1_enrollments_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollRequest) ProtoMessage() {}
+
+func (x *EnrollRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_enrollments_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollRequest.ProtoReflect.Descriptor instead.
+func (*EnrollRequest) Descriptor() ([]byte, []int) {
+	return file_lms_v1_enrollments_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EnrollRequest) GetCourseId() int64 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *EnrollRequest) GetStudentId() int64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *EnrollRequest) GetOverride() bool {
+	if x != nil {
+		return x.Override
+	}
+	return false
+}
+
+type EnrollResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollResponse) Reset() {
+	*x = EnrollResponse{}
+	mi := &file_lms_v1_enrollments_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollResponse) ProtoMessage() {}
+
+func (x *EnrollResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_enrollments_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollResponse.ProtoReflect.Descriptor instead.
+func (*EnrollResponse) Descriptor() ([]byte, []int) {
+	return file_lms_v1_enrollments_proto_rawDescGZIP(), []int{1}
+}
+
+type UnenrollRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CourseId      int64                  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	StudentId     int64                  `protobuf:"varint,2,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnenrollRequest) Reset() {
+	*x = UnenrollRequest{}
+	mi := &file_lms_v1_enrollments_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnenrollRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnenrollRequest) ProtoMessage() {}
+
+func (x *UnenrollRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_enrollments_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnenrollRequest.ProtoReflect.Descriptor instead.
+func (*UnenrollRequest) Descriptor() ([]byte, []int) {
+	return file_lms_v1_enrollments_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UnenrollRequest) GetCourseId() int64 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *UnenrollRequest) GetStudentId() int64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *UnenrollRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type SetEnrollmentStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CourseId      int64                  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	StudentId     int64                  `protobuf:"varint,2,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // dropped, withdrawn, completed, failed
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Grade         *float64               `protobuf:"fixed64,5,opt,name=grade,proto3,oneof" json:"grade,omitempty"` // 0..100, required for completed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEnrollmentStatusRequest) Reset() {
+	*x = SetEnrollmentStatusRequest{}
+	mi := &file_lms_v1_enrollments_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEnrollmentStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEnrollmentStatusRequest) ProtoMessage() {}
+
+func (x *SetEnrollmentStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_enrollments_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEnrollmentStatusRequest.ProtoReflect.Descriptor instead.
+func (*SetEnrollmentStatusRequest) Descriptor() ([]byte, []int) {
+	return file_lms_v1_enrollments_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SetEnrollmentStatusRequest) GetCourseId() int64 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *SetEnrollmentStatusRequest) GetStudentId() int64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *SetEnrollmentStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SetEnrollmentStatusRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *SetEnrollmentStatusRequest) GetGrade() float64 {
+	if x != nil && x.Grade != nil {
+		return *x.Grade
+	}
+	return 0
+}
+
+type UnenrollResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Promoted      []int64                `protobuf:"varint,1,rep,packed,name=promoted,proto3" json:"promoted,omitempty"` // students moved up from the waitlist
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnenrollResponse) Reset() {
+	*x = UnenrollResponse{}
+	mi := &file_lms_v1_enrollments_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnenrollResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnenrollResponse) ProtoMessage() {}
+
+func (x *UnenrollResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_enrollments_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnenrollResponse.ProtoReflect.Descriptor instead.
+func (*UnenrollResponse) Descriptor() ([]byte, []int) {
+	return file_lms_v1_enrollments_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UnenrollResponse) GetPromoted() []int64 {
+	if x != nil {
+		return x.Promoted
+	}
+	return nil
+}
+
+type SetEnrollmentStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Promoted      []int64                `protobuf:"varint,1,rep,packed,name=promoted,proto3" json:"promoted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEnrollmentStatusResponse) Reset() {
+	*x = SetEnrollmentStatusResponse{}
+	mi := &file_lms_v1_enrollments_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEnrollmentStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEnrollmentStatusResponse) ProtoMessage() {}
+
+func (x *SetEnrollmentStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_enrollments_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEnrollmentStatusResponse.ProtoReflect.Descriptor instead.
+func (*SetEnrollmentStatusResponse) Descriptor() ([]byte, []int) {
+	return file_lms_v1_enrollments_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SetEnrollmentStatusResponse) GetPromoted() []int64 {
+	if x != nil {
+		return x.Promoted
+	}
+	return nil
+}
+
+type GetTranscriptRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StudentId     int64                  `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"` // staff only; 0 = the caller
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`                         // e.g. completed for the official view
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTranscriptRequest) Reset() {
+	*x = GetTranscriptRequest{}
+	mi := &file_lms_v1_enrollments_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTranscriptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTranscriptRequest) ProtoMessage() {}
+
+func (x *GetTranscriptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_enrollments_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTranscriptRequest.ProtoReflect.Descriptor instead.
+func (*GetTranscriptRequest) Descriptor() ([]byte, []int) {
+	return file_lms_v1_enrollments_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetTranscriptRequest) GetStudentId() int64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *GetTranscriptRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type GetTranscriptResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StudentId     int64                  `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	Entries       []*TranscriptEntry     `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTranscriptResponse) Reset() {
+	*x = GetTranscriptResponse{}
+	mi := &file_lms_v1_enrollments_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTranscriptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTranscriptResponse) ProtoMessage() {}
+
+func (x *GetTranscriptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_enrollments_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTranscriptResponse.ProtoReflect.Descriptor instead.
+func (*GetTranscriptResponse) Descriptor() ([]byte, []int) {
+	return file_lms_v1_enrollments_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetTranscriptResponse) GetStudentId() int64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *GetTranscriptResponse) GetEntries() []*TranscriptEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type TranscriptEntry struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	CourseId        int64                  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	CourseTitle     string                 `protobuf:"bytes,2,opt,name=course_title,json=courseTitle,proto3" json:"course_title,omitempty"`
+	Teacher         string                 `protobuf:"bytes,3,opt,name=teacher,proto3" json:"teacher,omitempty"`
+	Term            string                 `protobuf:"bytes,4,opt,name=term,proto3" json:"term,omitempty"`
+	Credits         float64                `protobuf:"fixed64,5,opt,name=credits,proto3" json:"credits,omitempty"`
+	Status          string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	Grade           *float64               `protobuf:"fixed64,7,opt,name=grade,proto3,oneof" json:"grade,omitempty"`
+	AttendanceRate  *float64               `protobuf:"fixed64,8,opt,name=attendance_rate,json=attendanceRate,proto3,oneof" json:"attendance_rate,omitempty"`
+	Reason          string                 `protobuf:"bytes,9,opt,name=reason,proto3" json:"reason,omitempty"`
+	Group           string                 `protobuf:"bytes,10,opt,name=group,proto3" json:"group,omitempty"`
+	EnrolledAt      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=enrolled_at,json=enrolledAt,proto3" json:"enrolled_at,omitempty"`
+	StatusChangedAt *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=status_changed_at,json=statusChangedAt,proto3" json:"status_changed_at,omitempty"`
+	History         []*EnrollmentEvent     `protobuf:"bytes,13,rep,name=history,proto3" json:"history,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *TranscriptEntry) Reset() {
+	*x = TranscriptEntry{}
+	mi := &file_lms_v1_enrollments_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranscriptEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranscriptEntry) ProtoMessage() {}
+
+func (x *TranscriptEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_enrollments_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranscriptEntry.ProtoReflect.Descriptor instead.
+func (*TranscriptEntry) Descriptor() ([]byte, []int) {
+	return file_lms_v1_enrollments_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TranscriptEntry) GetCourseId() int64 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *TranscriptEntry) GetCourseTitle() string {
+	if x != nil {
+		return x.CourseTitle
+	}
+	return ""
+}
+
+func (x *TranscriptEntry) GetTeacher() string {
+	if x != nil {
+		return x.Teacher
+	}
+	return ""
+}
+
+func (x *TranscriptEntry) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *TranscriptEntry) GetCredits() float64 {
+	if x != nil {
+		return x.Credits
+	}
+	return 0
+}
+
+func (x *TranscriptEntry) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TranscriptEntry) GetGrade() float64 {
+	if x != nil && x.Grade != nil {
+		return *x.Grade
+	}
+	return 0
+}
+
+func (x *TranscriptEntry) GetAttendanceRate() float64 {
+	if x != nil && x.AttendanceRate != nil {
+		return *x.AttendanceRate
+	}
+	return 0
+}
+
+func (x *TranscriptEntry) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *TranscriptEntry) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *TranscriptEntry) GetEnrolledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EnrolledAt
+	}
+	return nil
+}
+
+func (x *TranscriptEntry) GetStatusChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StatusChangedAt
+	}
+	return nil
+}
+
+func (x *TranscriptEntry) GetHistory() []*EnrollmentEvent {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+type EnrollmentEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	ChangedBy     int64                  `protobuf:"varint,4,opt,name=changed_by,json=changedBy,proto3" json:"changed_by,omitempty"`
+	ChangedByName string                 `protobuf:"bytes,5,opt,name=changed_by_name,json=changedByName,proto3" json:"changed_by_name,omitempty"`
+	ChangedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollmentEvent) Reset() {
+	*x = EnrollmentEvent{}
+	mi := &file_lms_v1_enrollments_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollmentEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollmentEvent) ProtoMessage() {}
+
+func (x *EnrollmentEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_lms_v1_enrollments_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollmentEvent.ProtoReflect.Descriptor instead.
+func (*EnrollmentEvent) Descriptor() ([]byte, []int) {
+	return file_lms_v1_enrollments_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *EnrollmentEvent) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *EnrollmentEvent) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *EnrollmentEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *EnrollmentEvent) GetChangedBy() int64 {
+	if x != nil {
+		return x.ChangedBy
+	}
+	return 0
+}
+
+func (x *EnrollmentEvent) GetChangedByName() string {
+	if x != nil {
+		return x.ChangedByName
+	}
+	return ""
+}
+
+func (x *EnrollmentEvent) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+var File_lms_v1_enrollments_proto protoreflect.FileDescriptor
+
+const file_lms_v1_enrollments_proto_rawDesc = "" +
+	"\n" +
+	"\x18lms/v1/enrollments.proto\x12\x06lms.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"g\n" +
+	"\rEnrollRequest\x12\x1b\n" +
+	"\tcourse_id\x18\x01 \x01(\x03R\bcourseId\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x02 \x01(\x03R\tstudentId\x12\x1a\n" +
+	"\boverride\x18\x03 \x01(\bR\boverride\"\x10\n" +
+	"\x0eEnrollResponse\"e\n" +
+	"\x0fUnenrollRequest\x12\x1b\n" +
+	"\tcourse_id\x18\x01 \x01(\x03R\bcourseId\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x02 \x01(\x03R\tstudentId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\xad\x01\n" +
+	"\x1aSetEnrollmentStatusRequest\x12\x1b\n" +
+	"\tcourse_id\x18\x01 \x01(\x03R\bcourseId\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x02 \x01(\x03R\tstudentId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x19\n" +
+	"\x05grade\x18\x05 \x01(\x01H\x00R\x05grade\x88\x01\x01B\b\n" +
+	"\x06_grade\".\n" +
+	"\x10UnenrollResponse\x12\x1a\n" +
+	"\bpromoted\x18\x01 \x03(\x03R\bpromoted\"9\n" +
+	"\x1bSetEnrollmentStatusResponse\x12\x1a\n" +
+	"\bpromoted\x18\x01 \x03(\x03R\bpromoted\"M\n" +
+	"\x14GetTranscriptRequest\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x01 \x01(\x03R\tstudentId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"i\n" +
+	"\x15GetTranscriptResponse\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x01 \x01(\x03R\tstudentId\x121\n" +
+	"\aentries\x18\x02 \x03(\v2\x17.lms.v1.TranscriptEntryR\aentries\"\xfe\x03\n" +
+	"\x0fTranscriptEntry\x12\x1b\n" +
+	"\tcourse_id\x18\x01 \x01(\x03R\bcourseId\x12!\n" +
+	"\fcourse_title\x18\x02 \x01(\tR\vcourseTitle\x12\x18\n" +
+	"\ateacher\x18\x03 \x01(\tR\ateacher\x12\x12\n" +
+	"\x04term\x18\x04 \x01(\tR\x04term\x12\x18\n" +
+	"\acredits\x18\x05 \x01(\x01R\acredits\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x19\n" +
+	"\x05grade\x18\a \x01(\x01H\x00R\x05grade\x88\x01\x01\x12,\n" +
+	"\x0fattendance_rate\x18\b \x01(\x01H\x01R\x0eattendanceRate\x88\x01\x01\x12\x16\n" +
+	"\x06reason\x18\t \x01(\tR\x06reason\x12\x14\n" +
+	"\x05group\x18\n" +
+	" \x01(\tR\x05group\x12;\n" +
+	"\venrolled_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"enrolledAt\x12F\n" +
+	"\x11status_changed_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\x0fstatusChangedAt\x121\n" +
+	"\ahistory\x18\r \x03(\v2\x17.lms.v1.EnrollmentEventR\ahistoryB\b\n" +
+	"\x06_gradeB\x12\n" +
+	"\x10_attendance_rate\"\xcf\x01\n" +
+	"\x0fEnrollmentEvent\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"changed_by\x18\x04 \x01(\x03R\tchangedBy\x12&\n" +
+	"\x0fchanged_by_name\x18\x05 \x01(\tR\rchangedByName\x129\n" +
+	"\n" +
+	"changed_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt2\xb9\x02\n" +
+	"\x11EnrollmentService\x127\n" +
+	"\x06Enroll\x12\x15.lms.v1.EnrollRequest\x1a\x16.lms.v1.EnrollResponse\x12=\n" +
+	"\bUnenroll\x12\x17.lms.v1.UnenrollRequest\x1a\x18.lms.v1.UnenrollResponse\x12^\n" +
+	"\x13SetEnrollmentStatus\x12\".lms.v1.SetEnrollmentStatusRequest\x1a#.lms.v1.SetEnrollmentStatusResponse\x12L\n" +
+	"\rGetTranscript\x12\x1c.lms.v1.GetTranscriptRequest\x1a\x1d.lms.v1.GetTranscriptResponseB+Z)lms-backend/internal/transport/grpc/lmsv1b\x06proto3"
+
+var (
+	file_lms_v1_enrollments_proto_rawDescOnce sync.Once
+	file_lms_v1_enrollments_proto_rawDescData []byte
+)
+
+func file_lms_v1_enrollments_proto_rawDescGZIP() []byte {
+	file_lms_v1_enrollments_proto_rawDescOnce.Do(func() {
+		file_lms_v1_enrollments_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_lms_v1_enrollments_proto_rawDesc), len(file_lms_v1_enrollments_proto_rawDesc)))
+	})
+	return file_lms_v1_enrollments_proto_rawDescData
+}
+
+var file_lms_v1_enrollments_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_lms_v1_enrollments_proto_goTypes = []any{
+	(*EnrollRequest)(nil),               // 0: lms.v1.EnrollRequest
+	(*EnrollResponse)(nil),              // 1: lms.v1.EnrollResponse
+	(*UnenrollRequest)(nil),             // 2: lms.v1.UnenrollRequest
+	(*SetEnrollmentStatusRequest)(nil),  // 3: lms.v1.SetEnrollmentStatusRequest
+	(*UnenrollResponse)(nil),            // 4: lms.v1.UnenrollResponse
+	(*SetEnrollmentStatusResponse)(nil), // 5: lms.v1.SetEnrollmentStatusResponse
+	(*GetTranscriptRequest)(nil),        // 6: lms.v1.GetTranscriptRequest
+	(*GetTranscriptResponse)(nil),       // 7: lms.v1.GetTranscriptResponse
+	(*TranscriptEntry)(nil),             // 8: lms.v1.TranscriptEntry
+	(*EnrollmentEvent)(nil),             // 9: lms.v1.EnrollmentEvent
+	(*timestamppb.Timestamp)(nil),       // 10: google.protobuf.Timestamp
+}
+var file_lms_v1_enrollments_proto_depIdxs = []int32{
+	8,  // 0: lms.v1.GetTranscriptResponse.entries:type_name -> lms.v1.TranscriptEntry
+	10, // 1: lms.v1.TranscriptEntry.enrolled_at:type_name -> google.protobuf.Timestamp
+	10, // 2: lms.v1.TranscriptEntry.status_changed_at:type_name -> google.protobuf.Timestamp
+	9,  // 3: lms.v1.TranscriptEntry.history:type_name -> lms.v1.EnrollmentEvent
+	10, // 4: lms.v1.EnrollmentEvent.changed_at:type_name -> google.protobuf.Timestamp
+	0,  // 5: lms.v1.EnrollmentService.Enroll:input_type -> lms.v1.EnrollRequest
+	2,  // 6: lms.v1.EnrollmentService.Unenroll:input_type -> lms.v1.UnenrollRequest
+	3,  // 7: lms.v1.EnrollmentService.SetEnrollmentStatus:input_type -> lms.v1.SetEnrollmentStatusRequest
+	6,  // 8: lms.v1.EnrollmentService.GetTranscript:input_type -> lms.v1.GetTranscriptRequest
+	1,  // 9: lms.v1.EnrollmentService.Enroll:output_type -> lms.v1.EnrollResponse
+	4,  // 10: lms.v1.EnrollmentService.Unenroll:output_type -> lms.v1.UnenrollResponse
+	5,  // 11: lms.v1.EnrollmentService.SetEnrollmentStatus:output_type -> lms.v1.SetEnrollmentStatusResponse
+	7,  // 12: lms.v1.EnrollmentService.GetTranscript:output_type -> lms.v1.GetTranscriptResponse
+	9,  // [9:13] is the sub-list for method output_type
+	5,  // [5:9] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_lms_v1_enrollments_proto_init() }
+func file_lms_v1_enrollments_proto_init() {
+	if File_lms_v1_enrollments_proto != nil {
+		return
+	}
+	file_lms_v1_enrollments_proto_msgTypes[3].OneofWrappers = []any{}
+	file_lms_v1_enrollments_proto_msgTypes[8].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_lms_v1_enrollments_proto_rawDesc), len(file_lms_v1_enrollments_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_lms_v1_enrollments_proto_goTypes,
+		DependencyIndexes: file_lms_v1_enrollments_proto_depIdxs,
+		MessageInfos:      file_lms_v1_enrollments_proto_msgTypes,
+	}.Build()
+	File_lms_v1_enrollments_proto = out.File
+	file_lms_v1_enrollments_proto_goTypes = nil
+	file_lms_v1_enrollments_proto_depIdxs = nil
+}
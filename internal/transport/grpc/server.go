@@ -0,0 +1,40 @@
+// Package grpcapi serves the LMS over gRPC, next to the REST API and on top
+// of the same services. Callers authenticate with the JWT from
+// POST /api/v1/auth/login, sent as "authorization: Bearer <token>" metadata.
+//
+// The protobuf definitions live in proto/lms/v1; regenerate lmsv1 with
+// `buf generate` from the repository root.
+package grpcapi
+
+import (
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/grpc/lmsv1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+func NewServer(
+	authSvc *service.AuthService,
+	userSvc *service.UserService,
+	courseSvc *service.CourseService,
+	attSvc *service.AttendanceService,
+) *grpc.Server {
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryInterceptor(authSvc)),
+		grpc.ChainStreamInterceptor(streamInterceptor(authSvc)),
+	)
+
+	lmsv1.RegisterUserServiceServer(s, &userServer{svc: userSvc})
+	lmsv1.RegisterCourseServiceServer(s, &courseServer{svc: courseSvc})
+	lmsv1.RegisterEnrollmentServiceServer(s, &enrollmentServer{svc: courseSvc})
+	lmsv1.RegisterAttendanceServiceServer(s, &attendanceServer{svc: attSvc})
+
+	// public: load balancer checks and grpcurl/grpcui discovery
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	reflection.Register(s)
+
+	return s
+}
@@ -0,0 +1,127 @@
+package grpcapi
+
+import (
+	"context"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"time"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/pagination"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/grpc/lmsv1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type userServer struct {
+	lmsv1.UnimplementedUserServiceServer
+	svc *service.UserService
+}
+
+func (s *userServer) GetMe(ctx context.Context, _ *lmsv1.GetMeRequest) (*lmsv1.GetMeResponse, error) {
+	u, role, err := s.svc.Me(ctx, callerFrom(ctx).UserID)
+	if err != nil {
+		return nil, err
+	}
+	return &lmsv1.GetMeResponse{User: userPB(u, role)}, nil
+}
+
+// Admin: one user by id (deleted users come back anonymized)
+func (s *userServer) GetUser(ctx context.Context, req *lmsv1.GetUserRequest) (*lmsv1.GetUserResponse, error) {
+	if req.GetId() <= 0 {
+		return nil, apperr.Field("id", "must be > 0")
+	}
+	u, role, err := s.svc.Get(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, err
+	}
+	return &lmsv1.GetUserResponse{User: userPB(u, role)}, nil
+}
+
+func (s *userServer) ListUsers(ctx context.Context, req *lmsv1.ListUsersRequest) (*lmsv1.ListUsersResponse, error) {
+	page, err := pageRequest(req.GetPage())
+	if err != nil {
+		return nil, err
+	}
+	users, next, err := s.svc.List(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	out := &lmsv1.ListUsersResponse{NextCursor: next}
+	for _, u := range users {
+		out.Users = append(out.Users, userPB(u, ""))
+	}
+	return out, nil
+}
+
+func (s *userServer) CreateUser(ctx context.Context, req *lmsv1.CreateUserRequest) (*lmsv1.CreateUserResponse, error) {
+	if a, err := mail.ParseAddress(req.GetEmail()); err != nil || a.Address != req.GetEmail() {
+		return nil, apperr.Field("email", "must be an email address")
+	}
+	id, err := s.svc.Create(ctx, model.User{
+		Email:    req.GetEmail(),
+		FullName: req.GetFullName(),
+		RoleID:   int(req.GetRoleId()),
+	}, req.GetPassword())
+	if err != nil {
+		return nil, err
+	}
+	return &lmsv1.CreateUserResponse{Id: int64(id)}, nil
+}
+
+func (s *userServer) ChangeRole(ctx context.Context, req *lmsv1.ChangeRoleRequest) (*lmsv1.ChangeRoleResponse, error) {
+	if req.GetUserId() <= 0 {
+		return nil, apperr.Field("user_id", "must be > 0")
+	}
+	if req.Version == nil {
+		return nil, service.ErrVersionRequired
+	}
+	if req.GetVersion() <= 0 {
+		return nil, apperr.Field("version", "must be > 0")
+	}
+	version, err := s.svc.ChangeRole(ctx, int(req.GetUserId()), req.GetRole(), int(req.GetVersion()))
+	if err != nil {
+		return nil, err
+	}
+	return &lmsv1.ChangeRoleResponse{Version: int32(version)}, nil
+}
+
+func userPB(u model.User, role string) *lmsv1.User {
+	return &lmsv1.User{
+		Id: int64(u.ID), Email: u.Email, FullName: u.FullName, RoleId: int64(u.RoleID), Role: role,
+		Active: u.Active, CreatedAt: timestamp(u.CreatedAt), DeletedAt: timestampPtr(u.DeletedAt),
+		Version: int32(u.Version),
+	}
+}
+
+// pageRequest reads a PageRequest like pagination.FromQuery reads the query
+// string, with the same limits.
+func pageRequest(p *lmsv1.PageRequest) (pagination.Request, error) {
+	q := url.Values{}
+	for k, v := range p.GetFilters() {
+		q.Set(k, v)
+	}
+	q.Set("cursor", p.GetCursor())
+	q.Set("sort", p.GetSort())
+	if p.GetLimit() != 0 {
+		q.Set("limit", strconv.Itoa(int(p.GetLimit())))
+	}
+	return pagination.FromQuery(q)
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func timestampPtr(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamp(*t)
+}
@@ -0,0 +1,13 @@
+package dto
+
+type CreateRiskRuleReq struct {
+	Name       string  `json:"name" binding:"required"`
+	Kind       string  `json:"kind" binding:"required"` // absence_streak|rate_below
+	Threshold  float64 `json:"threshold" binding:"required"`
+	MinLessons int     `json:"min_lessons"`
+	CourseID   int     `json:"course_id"` // 0 = every course
+}
+
+type SetAdvisorReq struct {
+	AdvisorID int `json:"advisor_id"` // 0 removes the advisor
+}
@@ -8,3 +8,85 @@ type MarkAttendanceReq struct {
 	Status     string `json:"status" binding:"required"`
 	Note       string `json:"note"`
 }
+
+// RollCallItem is one row of a roll call. Version is the version of the mark
+// being changed; without it the row only creates a mark. Rows are checked by
+// the service, so a bad one is reported next to the others instead of failing
+// the whole request.
+type RollCallItem struct {
+	StudentID int    `json:"student_id"`
+	Status    string `json:"status"`
+	Note      string `json:"note"`
+	Version   int    `json:"version"`
+}
+
+// MarkLessonReq marks a whole lesson at once. With default_status set, every
+// enrolled student not listed in items and not marked yet gets that status.
+type MarkLessonReq struct {
+	LessonDate    time.Time      `json:"lesson_date" binding:"required"`
+	DefaultStatus string         `json:"default_status"`
+	Items         []RollCallItem `json:"items"`
+}
+
+type RequestCorrectionReq struct {
+	LessonDate      time.Time `json:"lesson_date" binding:"required"`
+	RequestedStatus string    `json:"requested_status"` // defaults to present
+	Reason          string    `json:"reason" binding:"required"`
+}
+
+type ReviewCorrectionReq struct {
+	Comment string `json:"comment"`
+}
+
+type CreateAttendanceStatusReq struct {
+	Code     string `json:"code" binding:"required"`
+	Label    string `json:"label" binding:"required"`
+	CountsAs string `json:"counts_as" binding:"required"` // present|absent|excused
+}
+
+// SubmitExcuseReq is sent as multipart/form-data with an optional "document" file.
+type SubmitExcuseReq struct {
+	CourseID int    `form:"course_id"` // 0 or empty = all courses
+	DateFrom string `form:"date_from" binding:"required"` // YYYY-MM-DD
+	DateTo   string `form:"date_to" binding:"required"`   // YYYY-MM-DD
+	Reason   string `form:"reason" binding:"required"`
+}
+
+type ReviewExcuseReq struct {
+	Comment string `json:"comment"`
+}
+
+// OpenCheckinReq: all fields are optional; lesson_date defaults to today.
+type OpenCheckinReq struct {
+	LessonDate       time.Time `json:"lesson_date"`
+	DurationMinutes  int       `json:"duration_minutes"`
+	LateAfterMinutes int       `json:"late_after_minutes"`
+}
+
+// CheckinReq: lat/lon and device_fingerprint are only required when the
+// course's check-in policy asks for them.
+type CheckinReq struct {
+	Code              string   `json:"code" binding:"required"`
+	Lat               *float64 `json:"lat"`
+	Lon               *float64 `json:"lon"`
+	DeviceFingerprint string   `json:"device_fingerprint"`
+}
+
+type CheckinPolicyReq struct {
+	RoomID          int      `json:"room_id"`
+	RequireLocation bool     `json:"require_location"`
+	RequireDevice   bool     `json:"require_device"`
+	AllowedNetworks []string `json:"allowed_networks"`
+}
+
+type CreateRoomReq struct {
+	Name    string  `json:"name" binding:"required"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+	RadiusM int     `json:"radius_m" binding:"required"`
+}
+
+type RegisterDeviceReq struct {
+	Fingerprint string `json:"fingerprint" binding:"required"`
+	Label       string `json:"label"`
+}
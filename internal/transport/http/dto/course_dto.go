@@ -1,10 +1,51 @@
 package dto
 
 type CreateCourseReq struct {
-	Title     string `json:"title" binding:"required"`
-	TeacherID int    `json:"teacher_id"`
+	Title     string  `json:"title" binding:"required"`
+	TeacherID int     `json:"teacher_id"`
+	Term      string  `json:"term"`
+	Credits   float64 `json:"credits"`
 }
 
 type EnrollReq struct {
-	StudentID int `json:"student_id" binding:"required"`
+	StudentID int  `json:"student_id" binding:"required"`
+	Override  bool `json:"override"` // admin only: skip requirement checks
+}
+
+type RequirementReq struct {
+	Kind             string   `json:"kind" binding:"required"` // prerequisite|corequisite|year|group
+	RequiredCourseID int      `json:"required_course_id"`
+	MinGrade         *float64 `json:"min_grade"`
+	MinYear          int      `json:"min_year"`
+	GroupID          int      `json:"group_id"`
+}
+
+type ScheduleSlotReq struct {
+	Weekday  int    `json:"weekday" binding:"required"` // 1 = Monday
+	StartsAt string `json:"starts_at" binding:"required"`
+	EndsAt   string `json:"ends_at" binding:"required"`
+}
+
+type ScheduleReq struct {
+	Slots []ScheduleSlotReq `json:"slots"`
+}
+
+type EnrollmentStatusReq struct {
+	Status string   `json:"status" binding:"required"` // dropped|withdrawn|completed|failed
+	Reason string   `json:"reason"`
+	Grade  *float64 `json:"grade"` // 0..100, required for completed
+}
+
+type UnenrollReq struct {
+	StudentID int    `json:"student_id" binding:"required"`
+	Reason    string `json:"reason"`
+}
+
+type RevokeCertificateReq struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type MaterialReq struct {
+	Title string `json:"title" binding:"required"`
+	Body  string `json:"body"`
 }
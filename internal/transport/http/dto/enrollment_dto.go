@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+type EnrollmentPolicyReq struct {
+	Policy   string     `json:"policy" binding:"required"` // closed|open|approval|key
+	Key      string     `json:"key"`                       // empty keeps the current key
+	Capacity int        `json:"capacity"`                  // 0 = unlimited
+	OpensAt  *time.Time `json:"opens_at"`
+	ClosesAt *time.Time `json:"closes_at"`
+}
+
+type EnrollmentJoinReq struct {
+	Key string `json:"key"`
+}
+
+type ReviewEnrollmentReq struct {
+	Comment string `json:"comment"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+// GraphQLReq documents the body of POST /graphql; the handler passes it on
+// to the GraphQL server as is.
+type GraphQLReq struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	Extensions    map[string]any `json:"extensions,omitempty"`
+}
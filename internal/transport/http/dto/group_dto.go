@@ -0,0 +1,17 @@
+package dto
+
+type GroupReq struct {
+	Name    string `json:"name" binding:"required"`
+	Faculty string `json:"faculty"`
+	Year    int    `json:"year"`
+}
+
+type GroupMembersReq struct {
+	StudentIDs []int `json:"student_ids" binding:"required"`
+	Override   bool  `json:"override"` // skip requirement checks
+}
+
+type EnrollGroupReq struct {
+	GroupID  int  `json:"group_id" binding:"required"`
+	Override bool `json:"override"` // admin only: skip requirement checks
+}
@@ -10,3 +10,34 @@ type CreateUserReq struct {
 type ChangeRoleReq struct {
 	Role string `json:"role" binding:"required"`
 }
+
+// UpdateUserReq is an admin edit; omitted fields are left unchanged.
+type UpdateUserReq struct {
+	Email    *string `json:"email" binding:"omitempty,email"`
+	FullName *string `json:"full_name"`
+	Role     *string `json:"role"`
+	Active   *bool   `json:"active"`
+	Password *string `json:"password"`
+}
+
+// UpdateMeReq changes the caller's own profile; current_password is needed
+// to change email or password.
+type UpdateMeReq struct {
+	Email           *string `json:"email" binding:"omitempty,email"`
+	FullName        *string `json:"full_name"`
+	Password        *string `json:"password"`
+	CurrentPassword string  `json:"current_password"`
+}
+
+// UpdateProfileReq edits profile fields; omitted fields are left unchanged
+// and "" clears a field. Users may only change phone and language themselves.
+type UpdateProfileReq struct {
+	StudentNumber *string `json:"student_number"`
+	Group         *string `json:"group"`
+	Year          *int    `json:"year"`
+	Faculty       *string `json:"faculty"`
+	Phone         *string `json:"phone"`
+	Language      *string `json:"language"`
+	Department    *string `json:"department"`
+	Title         *string `json:"title"`
+}
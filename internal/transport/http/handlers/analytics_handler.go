@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"strconv"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/http/dto"
+	"lms-backend/internal/transport/http/middleware"
+	"lms-backend/internal/transport/http/responder"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticsHandler struct {
+	svc *service.AnalyticsService
+}
+
+func NewAnalyticsHandler(svc *service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{svc: svc}
+}
+
+// Teacher/admin: rates per student, lesson and week plus absence streaks
+func (h *AnalyticsHandler) Course(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+
+	a, err := h.svc.CourseAnalytics(c.Request.Context(), courseID)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	students := make([]gin.H, 0, len(a.Students))
+	for _, x := range a.Students {
+		row := summaryJSON(x.AttendanceSummary)
+		row["current_absence_streak"] = x.CurrentAbsenceStreak
+		row["longest_absence_streak"] = x.LongestAbsenceStreak
+		students = append(students, row)
+	}
+	lessons := make([]gin.H, 0, len(a.Lessons))
+	for _, x := range a.Lessons {
+		row := summaryJSON(x.AttendanceSummary)
+		delete(row, "student_id")
+		row["lesson_date"] = x.LessonDate.Format("2006-01-02")
+		lessons = append(lessons, row)
+	}
+	trend := make([]gin.H, 0, len(a.Trend))
+	for _, x := range a.Trend {
+		row := summaryJSON(x.AttendanceSummary)
+		delete(row, "student_id")
+		row["week_start"] = x.WeekStart.Format("2006-01-02")
+		trend = append(trend, row)
+	}
+	overall := summaryJSON(a.Overall)
+	delete(overall, "student_id")
+
+	responder.OK(c, gin.H{
+		"course_id": a.CourseID,
+		"overall":   overall,
+		"students":  students,
+		"lessons":   lessons,
+		"trend":     trend,
+	})
+}
+
+func (h *AnalyticsHandler) ListRules(c *gin.Context) {
+	items, err := h.svc.ListRules(c.Request.Context())
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	out := make([]gin.H, 0, len(items))
+	for _, x := range items {
+		out = append(out, gin.H{
+			"id": x.ID, "name": x.Name, "kind": x.Kind, "threshold": x.Threshold,
+			"min_lessons": x.MinLessons, "course_id": x.CourseID, "active": x.Active,
+		})
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out)})
+}
+
+// Admin: add a rule, e.g. {"name":"3 in a row","kind":"absence_streak","threshold":3}
+func (h *AnalyticsHandler) CreateRule(c *gin.Context) {
+	var req dto.CreateRiskRuleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	id, err := h.svc.CreateRule(c.Request.Context(), model.RiskRule{
+		Name:       req.Name,
+		Kind:       req.Kind,
+		Threshold:  req.Threshold,
+		MinLessons: req.MinLessons,
+		CourseID:   req.CourseID,
+		Active:     true,
+	})
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.Created(c, gin.H{"id": id})
+}
+
+func (h *AnalyticsHandler) DeleteRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid rule id"))
+		return
+	}
+
+	if err := h.svc.DeleteRule(c.Request.Context(), id); err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.OK(c, gin.H{"status": "deleted"})
+}
+
+// Admin: re-evaluate rules now (optional ?course_id=, default all courses)
+func (h *AnalyticsHandler) Evaluate(c *gin.Context) {
+	courseID := 0
+	if v := c.Query("course_id"); v != "" {
+		x, err := strconv.Atoi(v)
+		if err != nil || x <= 0 {
+			responder.Fail(c, apperr.Invalid("invalid course_id"))
+			return
+		}
+		courseID = x
+	}
+
+	var (
+		n   int
+		err error
+	)
+	if courseID > 0 {
+		n, err = h.svc.Evaluate(c.Request.Context(), courseID)
+	} else {
+		n, err = h.svc.EvaluateAll(c.Request.Context())
+	}
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.OK(c, gin.H{"new_alerts": n})
+}
+
+// Teacher/admin: open at-risk alerts for my courses and advisees
+// (optional ?course_id= and ?include_resolved=true)
+func (h *AnalyticsHandler) ListAlerts(c *gin.Context) {
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	roleAny, _ := c.Get(middleware.CtxRoleKey)
+	role, _ := roleAny.(string)
+
+	courseID := 0
+	if v := c.Query("course_id"); v != "" {
+		x, err := strconv.Atoi(v)
+		if err != nil || x < 0 {
+			responder.Fail(c, apperr.Invalid("invalid course_id"))
+			return
+		}
+		courseID = x
+	}
+	includeResolved := c.Query("include_resolved") == "true"
+
+	items, err := h.svc.ListAlerts(c.Request.Context(), uid, role, courseID, includeResolved)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	out := make([]gin.H, 0, len(items))
+	for _, x := range items {
+		out = append(out, gin.H{
+			"id": x.ID, "rule_id": x.RuleID, "rule_name": x.RuleName,
+			"course_id": x.CourseID, "student_id": x.StudentID, "value": x.Value, "detail": x.Detail,
+			"created_at": x.CreatedAt, "notified_at": x.NotifiedAt,
+			"acknowledged_by": x.AcknowledgedBy, "acknowledged_at": x.AcknowledgedAt, "resolved_at": x.ResolvedAt,
+		})
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out)})
+}
+
+func (h *AnalyticsHandler) Acknowledge(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid alert id"))
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	roleAny, _ := c.Get(middleware.CtxRoleKey)
+	role, _ := roleAny.(string)
+
+	if err := h.svc.Acknowledge(c.Request.Context(), uid, role, id); err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.OK(c, gin.H{"status": "acknowledged"})
+}
+
+// Admin: assign an advisor to a student (body: { "advisor_id": 5 })
+func (h *AnalyticsHandler) SetAdvisor(c *gin.Context) {
+	studentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || studentID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid user id"))
+		return
+	}
+
+	var req dto.SetAdvisorReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	if err := h.svc.SetAdvisor(c.Request.Context(), studentID, req.AdvisorID); err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.OK(c, gin.H{"status": "updated"})
+}
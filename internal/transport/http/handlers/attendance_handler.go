@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"log"
-	"net/http"
 	"strconv"
 
+	"lms-backend/internal/apperr"
 	"lms-backend/internal/domain/model"
 	"lms-backend/internal/service"
 	"lms-backend/internal/transport/http/dto"
@@ -25,47 +27,116 @@ func NewAttendanceHandler(svc *service.AttendanceService) *AttendanceHandler {
 func (h *AttendanceHandler) Mark(c *gin.Context) {
 	courseID, err := strconv.Atoi(c.Param("id"))
 	if err != nil || courseID <= 0 {
-		responder.Fail(c, http.StatusBadRequest, "invalid course id")
+		responder.Fail(c, apperr.Invalid("invalid course id"))
 		return
 	}
 
 	var req dto.MarkAttendanceReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		responder.Fail(c, http.StatusBadRequest, err.Error())
+		responder.FailBinding(c, err)
 		return
 	}
 
-	err = h.svc.Mark(c.Request.Context(), model.Attendance{
+	// a new mark needs no If-Match; changing one needs the version it is based on
+	version, ok, err := ifMatch(c)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	if !ok {
+		version = service.NewOnly
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	version, err = h.svc.Mark(c.Request.Context(), model.Attendance{
 		CourseID:   courseID,
 		StudentID:  req.StudentID,
 		LessonDate: req.LessonDate,
 		Status:     req.Status,
 		Note:       req.Note,
-	})
+	}, uid, version)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	setVersion(c, version)
+	responder.OK(c, gin.H{"status": "saved", "version": version})
+}
+
+// Teacher/admin: mark a whole lesson in one request
+func (h *AttendanceHandler) MarkLesson(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+
+	var req dto.MarkLessonReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	items := make([]model.Attendance, 0, len(req.Items))
+	for _, x := range req.Items {
+		items = append(items, model.Attendance{StudentID: x.StudentID, Status: x.Status, Note: x.Note, Version: x.Version})
+	}
+
+	results, err := h.svc.MarkLesson(c.Request.Context(), courseID, req.LessonDate, req.DefaultStatus, items, uid)
+	out := make([]gin.H, 0, len(results))
+	saved := 0
+	for _, x := range results {
+		row := gin.H{"student_id": x.StudentID, "status": x.Status, "changed": x.Changed, "version": x.Version}
+		if x.Error != "" {
+			row["error"] = x.Error
+		}
+		if x.Code != "" {
+			row["code"] = x.Code
+		}
+		if x.Changed {
+			saved++
+		}
+		out = append(out, row)
+	}
+	if errors.Is(err, service.ErrRollCallRejected) || errors.Is(err, service.ErrRollCallConflict) {
+		responder.FailWithData(c, err, gin.H{"items": out, "count": len(out)})
+		return
+	}
 	if err != nil {
-		responder.Fail(c, http.StatusBadRequest, err.Error())
+		responder.Fail(c, err)
 		return
 	}
 
-	responder.OK(c, gin.H{"status": "saved"})
+	responder.OK(c, gin.H{"items": out, "count": len(out), "changed": saved})
 }
 
 func (h *AttendanceHandler) ListByCourse(c *gin.Context) {
 	courseID, err := strconv.Atoi(c.Param("id"))
 	if err != nil || courseID <= 0 {
-		responder.Fail(c, http.StatusBadRequest, "invalid course id")
+		responder.Fail(c, apperr.Invalid("invalid course id"))
 		return
 	}
 
 	if err != nil || courseID <= 0 {
-		responder.Fail(c, http.StatusBadRequest, "invalid course id")
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+
+	req, ok := pageRequest(c)
+	if !ok {
 		return
 	}
 
-	items, err := h.svc.ListByCourse(c.Request.Context(), courseID)
+	items, next, err := h.svc.ListByCourse(c.Request.Context(), courseID, req)
 	if err != nil {
 		log.Println("Error listing attendance by course:", err)
-		responder.Fail(c, http.StatusInternalServerError, err.Error())
+		responder.Fail(c, err)
 		return
 	}
 
@@ -73,11 +144,11 @@ func (h *AttendanceHandler) ListByCourse(c *gin.Context) {
 	for _, a := range items {
 		out = append(out, gin.H{
 			"id": a.ID, "course_id": a.CourseID, "student_id": a.StudentID,
-			"lesson_date": a.LessonDate, "status": a.Status, "note": a.Note,
+			"lesson_date": a.LessonDate, "status": a.Status, "note": a.Note, "version": a.Version,
 		})
 	}
 
-	responder.OK(c, gin.H{"items": out, "count": len(out)})
+	responder.OK(c, gin.H{"items": out, "count": len(out), "next_cursor": next})
 }
 
 // Student: my attendance (optional ?course_id=)
@@ -88,33 +159,298 @@ func (h *AttendanceHandler) MyAttendance(c *gin.Context) {
 	role, _ := roleAny.(string)
 
 	if role != "student" && role != "admin" && role != "teacher" {
-		responder.Fail(c, http.StatusForbidden, "forbidden")
+		responder.Fail(c, apperr.ErrForbidden)
+		return
+	}
+
+	req, ok := pageRequest(c)
+	if !ok {
+		return
+	}
+
+	// If admin/teacher calls this, it returns their own (usually empty). For FE, intended for students.
+	items, next, err := h.svc.ListByStudent(c.Request.Context(), uid, req)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	out := make([]gin.H, 0, len(items))
+	for _, a := range items {
+		out = append(out, gin.H{
+			"id": a.ID, "course_id": a.CourseID, "student_id": a.StudentID,
+			"lesson_date": a.LessonDate, "status": a.Status, "note": a.Note, "version": a.Version,
+		})
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out), "next_cursor": next})
+}
+
+// History of a student's marks in a course. Students may only see their own.
+func (h *AttendanceHandler) History(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	studentID, err := strconv.Atoi(c.Param("studentID"))
+	if err != nil || studentID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid student id"))
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	roleAny, _ := c.Get(middleware.CtxRoleKey)
+	role, _ := roleAny.(string)
+	if role == "student" && uid != studentID {
+		responder.Fail(c, apperr.ErrForbidden)
+		return
+	}
+
+	items, err := h.svc.History(c.Request.Context(), courseID, studentID)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	out := make([]gin.H, 0, len(items))
+	for _, x := range items {
+		out = append(out, gin.H{
+			"id": x.ID, "attendance_id": x.AttendanceID, "lesson_date": x.LessonDate,
+			"old_status": x.OldStatus, "old_note": x.OldNote,
+			"new_status": x.NewStatus, "new_note": x.NewNote,
+			"changed_by": x.ChangedBy, "changed_at": x.ChangedAt,
+		})
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out)})
+}
+
+// Student: ask the teacher to correct a mark ("I was there").
+func (h *AttendanceHandler) RequestCorrection(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+
+	var req dto.RequestCorrectionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	id, err := h.svc.RequestCorrection(c.Request.Context(), model.AttendanceCorrection{
+		CourseID:        courseID,
+		StudentID:       uid,
+		LessonDate:      req.LessonDate,
+		RequestedStatus: req.RequestedStatus,
+		Reason:          req.Reason,
+	})
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.Created(c, gin.H{"id": id})
+}
+
+// Teacher/admin: list correction requests of a course (optional ?status=)
+func (h *AttendanceHandler) ListCorrections(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
 		return
 	}
 
+	items, err := h.svc.ListCorrections(c.Request.Context(), courseID, c.Query("status"))
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	out := make([]gin.H, 0, len(items))
+	for _, x := range items {
+		out = append(out, correctionJSON(x))
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out)})
+}
+
+// Student: my correction requests (optional ?course_id=)
+func (h *AttendanceHandler) MyCorrections(c *gin.Context) {
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
 	courseID := 0
 	if v := c.Query("course_id"); v != "" {
 		x, err := strconv.Atoi(v)
 		if err != nil || x < 0 {
-			responder.Fail(c, http.StatusBadRequest, "invalid course_id")
+			responder.Fail(c, apperr.Invalid("invalid course_id"))
 			return
 		}
 		courseID = x
 	}
 
-	// If admin/teacher calls this, it returns their own (usually empty). For FE, intended for students.
-	items, err := h.svc.ListByStudent(c.Request.Context(), uid, courseID)
+	items, err := h.svc.MyCorrections(c.Request.Context(), uid, courseID)
 	if err != nil {
-		responder.Fail(c, http.StatusInternalServerError, err.Error())
+		responder.Fail(c, err)
 		return
 	}
 
 	out := make([]gin.H, 0, len(items))
-	for _, a := range items {
-		out = append(out, gin.H{
-			"id": a.ID, "course_id": a.CourseID, "student_id": a.StudentID,
-			"lesson_date": a.LessonDate, "status": a.Status, "note": a.Note,
-		})
+	for _, x := range items {
+		out = append(out, correctionJSON(x))
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out)})
+}
+
+func (h *AttendanceHandler) ApproveCorrection(c *gin.Context) { h.reviewCorrection(c, true) }
+
+func (h *AttendanceHandler) RejectCorrection(c *gin.Context) { h.reviewCorrection(c, false) }
+
+func (h *AttendanceHandler) reviewCorrection(c *gin.Context, approve bool) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	correctionID, err := strconv.Atoi(c.Param("correctionID"))
+	if err != nil || correctionID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid correction id"))
+		return
+	}
+
+	// body is optional
+	var req dto.ReviewCorrectionReq
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	x, err := h.svc.ReviewCorrection(c.Request.Context(), courseID, correctionID, uid, approve, req.Comment)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.OK(c, correctionJSON(x))
+}
+
+func correctionJSON(x model.AttendanceCorrection) gin.H {
+	return gin.H{
+		"id": x.ID, "course_id": x.CourseID, "student_id": x.StudentID,
+		"lesson_date": x.LessonDate, "requested_status": x.RequestedStatus, "reason": x.Reason,
+		"status": x.Status, "reviewed_by": x.ReviewedBy, "review_comment": x.ReviewComment,
+		"created_at": x.CreatedAt, "reviewed_at": x.ReviewedAt,
+	}
+}
+
+func (h *AttendanceHandler) ListStatuses(c *gin.Context) {
+	items, err := h.svc.ListStatuses(c.Request.Context())
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	out := make([]gin.H, 0, len(items))
+	for _, x := range items {
+		out = append(out, gin.H{"code": x.Code, "label": x.Label, "counts_as": x.CountsAs, "builtin": x.Builtin})
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out)})
+}
+
+// Admin: add an institution-specific status
+func (h *AttendanceHandler) CreateStatus(c *gin.Context) {
+	var req dto.CreateAttendanceStatusReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	err := h.svc.CreateStatus(c.Request.Context(), model.AttendanceStatus{
+		Code:     req.Code,
+		Label:    req.Label,
+		CountsAs: req.CountsAs,
+	})
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.Created(c, gin.H{"code": req.Code})
+}
+
+func (h *AttendanceHandler) DeleteStatus(c *gin.Context) {
+	if err := h.svc.DeleteStatus(c.Request.Context(), c.Param("code")); err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, gin.H{"status": "deleted"})
+}
+
+// Teacher/admin: per-student totals for a course, excused kept separate
+func (h *AttendanceHandler) CourseSummary(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+
+	items, err := h.svc.CourseSummary(c.Request.Context(), courseID)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	out := make([]gin.H, 0, len(items))
+	for _, x := range items {
+		out = append(out, summaryJSON(x))
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out)})
+}
+
+// Student: my totals per course (optional ?course_id=)
+func (h *AttendanceHandler) MySummary(c *gin.Context) {
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	courseID := 0
+	if v := c.Query("course_id"); v != "" {
+		x, err := strconv.Atoi(v)
+		if err != nil || x < 0 {
+			responder.Fail(c, apperr.Invalid("invalid course_id"))
+			return
+		}
+		courseID = x
+	}
+
+	items, err := h.svc.StudentSummary(c.Request.Context(), uid, courseID)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	out := make([]gin.H, 0, len(items))
+	for _, x := range items {
+		out = append(out, summaryJSON(x))
 	}
 	responder.OK(c, gin.H{"items": out, "count": len(out)})
 }
+
+// summaryJSON renders rate as null when every mark is excused.
+func summaryJSON(x model.AttendanceSummary) gin.H {
+	var rate any
+	if r, ok := x.Rate(); ok {
+		rate = r
+	}
+	return gin.H{
+		"course_id": x.CourseID, "student_id": x.StudentID,
+		"total": x.Total, "attended": x.Attended, "absent": x.Absent, "excused": x.Excused,
+		"by_status": x.ByStatus, "rate": rate,
+	}
+}
@@ -1,10 +1,9 @@
 package handlers
 
 import (
-	"net/http"
-
 	"lms-backend/internal/service"
 	"lms-backend/internal/transport/http/dto"
+	"lms-backend/internal/transport/http/middleware"
 	"lms-backend/internal/transport/http/responder"
 
 	"github.com/gin-gonic/gin"
@@ -21,13 +20,13 @@ func NewAuthHandler(auth *service.AuthService) *AuthHandler {
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req dto.RegisterReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		responder.Fail(c, http.StatusBadRequest, err.Error())
+		responder.FailBinding(c, err)
 		return
 	}
 
 	id, err := h.auth.RegisterStudent(c.Request.Context(), req.Email, req.Password, req.FullName)
 	if err != nil {
-		responder.Fail(c, http.StatusBadRequest, err.Error())
+		responder.Fail(c, err)
 		return
 	}
 
@@ -37,15 +36,28 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req dto.LoginReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		responder.Fail(c, http.StatusBadRequest, err.Error())
+		responder.FailBinding(c, err)
 		return
 	}
 
 	token, err := h.auth.Login(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
-		responder.Fail(c, http.StatusUnauthorized, err.Error())
+		responder.Fail(c, err)
 		return
 	}
 
 	responder.OK(c, gin.H{"access_token": token})
 }
+
+// Any logged-in user: a one-time ticket for GET /events or /ws?ticket=
+func (h *AuthHandler) StreamTicket(c *gin.Context) {
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	ticket, ttl, err := h.auth.StreamTicket(c.Request.Context(), uid)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, gin.H{"ticket": ticket, "expires_in": int(ttl.Seconds())})
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/http/dto"
+	"lms-backend/internal/transport/http/middleware"
+	"lms-backend/internal/transport/http/responder"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CertificateHandler struct {
+	svc *service.CertificateService
+}
+
+func NewCertificateHandler(svc *service.CertificateService) *CertificateHandler {
+	return &CertificateHandler{svc: svc}
+}
+
+// Student: certificates issued to me
+func (h *CertificateHandler) Mine(c *gin.Context) {
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	items, err := h.svc.ListByStudent(c.Request.Context(), uid)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	out := make([]gin.H, 0, len(items))
+	for _, x := range items {
+		out = append(out, h.certificateJSON(x))
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out)})
+}
+
+// Student: PDF certificate of a completed course
+func (h *CertificateHandler) MyPDF(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	h.pdf(c, courseID, uid)
+}
+
+// Teacher/admin: PDF certificate of a student
+func (h *CertificateHandler) StudentPDF(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	studentID, err := strconv.Atoi(c.Param("studentID"))
+	if err != nil || studentID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid student id"))
+		return
+	}
+	h.pdf(c, courseID, studentID)
+}
+
+func (h *CertificateHandler) pdf(c *gin.Context, courseID, studentID int) {
+	cert, err := h.svc.Issue(c.Request.Context(), courseID, studentID)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "application/pdf")
+	c.Header("Content-Disposition", `attachment; filename="certificate-`+cert.Code+`.pdf"`)
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+	if err := h.svc.WritePDF(c.Writer, cert); err != nil {
+		// headers are already sent; all we can do is log
+		log.Printf("certificate %s: %v", cert.Code, err)
+	}
+}
+
+// Public: check a certificate by its code
+func (h *CertificateHandler) Verify(c *gin.Context) {
+	cert, authentic, err := h.svc.Verify(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	if !authentic {
+		responder.OK(c, gin.H{"code": cert.Code, "valid": false, "status": "invalid"})
+		return
+	}
+
+	out := h.certificateJSON(cert)
+	delete(out, "id")
+	delete(out, "signature")
+	out["valid"] = cert.RevokedAt == nil
+	responder.OK(c, out)
+}
+
+// Admin: revoke a certificate, e.g. after academic misconduct
+func (h *CertificateHandler) Revoke(c *gin.Context) {
+	var req dto.RevokeCertificateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+	if err := h.svc.Revoke(c.Request.Context(), c.Param("code"), req.Reason); err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, gin.H{"status": "revoked"})
+}
+
+func (h *CertificateHandler) certificateJSON(x model.Certificate) gin.H {
+	status := "valid"
+	if x.RevokedAt != nil {
+		status = "revoked"
+	}
+	return gin.H{
+		"id": x.ID, "code": x.Code, "status": status,
+		"student_name": x.StudentName, "course_id": x.CourseID, "course_title": x.CourseTitle,
+		"teacher": x.TeacherName, "term": x.Term, "credits": x.Credits, "grade": x.Grade,
+		"attendance_rate": x.AttendanceRate, "completed_at": x.CompletedAt, "issued_at": x.IssuedAt,
+		"revoked_at": x.RevokedAt, "revoke_reason": x.RevokeReason,
+		"signature": x.Signature, "verify_url": h.svc.VerifyURL(x.Code),
+	}
+}
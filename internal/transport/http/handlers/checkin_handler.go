@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/http/dto"
+	"lms-backend/internal/transport/http/middleware"
+	"lms-backend/internal/transport/http/responder"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CheckinHandler struct {
+	svc *service.CheckinService
+}
+
+func NewCheckinHandler(svc *service.CheckinService) *CheckinHandler {
+	return &CheckinHandler{svc: svc}
+}
+
+// Teacher/admin: open a check-in window (body optional)
+func (h *CheckinHandler) Open(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+
+	var req dto.OpenCheckinReq
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	sess, err := h.svc.Open(c.Request.Context(), courseID, uid, req.LessonDate,
+		time.Duration(req.DurationMinutes)*time.Minute, time.Duration(req.LateAfterMinutes)*time.Minute)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.Created(c, gin.H{
+		"session_id": sess.ID, "lesson_date": sess.LessonDate.Format("2006-01-02"),
+		"late_after": sess.LateAfter, "closes_at": sess.ClosesAt,
+	})
+}
+
+// Teacher/admin: current rotating code of the open window
+func (h *CheckinHandler) Current(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+
+	cur, err := h.svc.Current(c.Request.Context(), courseID)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.OK(c, gin.H{
+		"session_id": cur.Session.ID, "lesson_date": cur.Session.LessonDate.Format("2006-01-02"),
+		"code": cur.Code, "expires_at": cur.ExpiresAt,
+		"late_after": cur.Session.LateAfter, "closes_at": cur.Session.ClosesAt,
+		"checked_in": cur.CheckedIn,
+	})
+}
+
+// Teacher/admin: current code as a QR PNG (optional ?size= in px)
+func (h *CheckinHandler) QR(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	size, _ := strconv.Atoi(c.Query("size"))
+
+	png, err := h.svc.QR(c.Request.Context(), courseID, size)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// Student: submit the code shown in class
+func (h *CheckinHandler) CheckIn(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+
+	var req dto.CheckinReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	status, err := h.svc.CheckIn(c.Request.Context(), courseID, uid, req.Code, service.CheckinProof{
+		IP:                c.ClientIP(),
+		Lat:               req.Lat,
+		Lon:               req.Lon,
+		DeviceFingerprint: req.DeviceFingerprint,
+	})
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.OK(c, gin.H{"status": status})
+}
+
+// Teacher/admin: close the window now; non-check-ins become absent
+func (h *CheckinHandler) Close(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	n, err := h.svc.Close(c.Request.Context(), courseID, uid)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.OK(c, gin.H{"status": "closed", "marked_absent": n})
+}
+
+func (h *CheckinHandler) GetPolicy(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+
+	p, err := h.svc.GetPolicy(c.Request.Context(), courseID)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	var room any
+	if p.Room != nil {
+		room = roomJSON(*p.Room)
+	}
+	responder.OK(c, gin.H{
+		"course_id": p.CourseID, "room_id": p.RoomID, "room": room,
+		"require_location": p.RequireLocation, "require_device": p.RequireDevice,
+		"allowed_networks": p.AllowedNetworks,
+	})
+}
+
+// Teacher/admin: replace the course's check-in policy
+func (h *CheckinHandler) SavePolicy(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+
+	var req dto.CheckinPolicyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	err = h.svc.SavePolicy(c.Request.Context(), model.CheckinPolicy{
+		CourseID:        courseID,
+		RoomID:          req.RoomID,
+		RequireLocation: req.RequireLocation,
+		RequireDevice:   req.RequireDevice,
+		AllowedNetworks: req.AllowedNetworks,
+	})
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.OK(c, gin.H{"status": "saved"})
+}
+
+// Teacher/admin: devices that checked in several students in one window
+func (h *CheckinHandler) FraudReport(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+
+	items, err := h.svc.FraudReport(c.Request.Context(), courseID)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	out := make([]gin.H, 0, len(items))
+	for _, x := range items {
+		out = append(out, gin.H{
+			"session_id": x.SessionID, "lesson_date": x.LessonDate.Format("2006-01-02"),
+			"device": x.DeviceHash[:12], "student_ids": x.StudentIDs,
+		})
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out)})
+}
+
+func (h *CheckinHandler) ListRooms(c *gin.Context) {
+	items, err := h.svc.ListRooms(c.Request.Context())
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	out := make([]gin.H, 0, len(items))
+	for _, x := range items {
+		out = append(out, roomJSON(x))
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out)})
+}
+
+// Admin: add a classroom with its geofence
+func (h *CheckinHandler) CreateRoom(c *gin.Context) {
+	var req dto.CreateRoomReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	id, err := h.svc.CreateRoom(c.Request.Context(), model.Room{
+		Name: req.Name, Lat: req.Lat, Lon: req.Lon, RadiusM: req.RadiusM,
+	})
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.Created(c, gin.H{"id": id})
+}
+
+// Student: bind the device used for check-in
+func (h *CheckinHandler) RegisterDevice(c *gin.Context) {
+	var req dto.RegisterDeviceReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	if err := h.svc.RegisterDevice(c.Request.Context(), uid, req.Fingerprint, req.Label); err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.Created(c, gin.H{"status": "registered"})
+}
+
+func (h *CheckinHandler) MyDevice(c *gin.Context) {
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	d, ok, err := h.svc.GetDevice(c.Request.Context(), uid)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	if !ok {
+		responder.OK(c, gin.H{"registered": false})
+		return
+	}
+
+	responder.OK(c, gin.H{"registered": true, "label": d.Label, "registered_at": d.RegisteredAt})
+}
+
+// Admin: unbind a student's device so they can register a new one
+func (h *CheckinHandler) ResetDevice(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || userID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid user id"))
+		return
+	}
+
+	if err := h.svc.ResetDevice(c.Request.Context(), userID); err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.OK(c, gin.H{"status": "reset"})
+}
+
+func roomJSON(x model.Room) gin.H {
+	return gin.H{"id": x.ID, "name": x.Name, "lat": x.Lat, "lon": x.Lon, "radius_m": x.RadiusM}
+}
@@ -1,9 +1,10 @@
 package handlers
 
 import (
-	"net/http"
+	"math"
 	"strconv"
 
+	"lms-backend/internal/apperr"
 	"lms-backend/internal/domain/model"
 	"lms-backend/internal/service"
 	"lms-backend/internal/transport/http/dto"
@@ -24,7 +25,7 @@ func NewCourseHandler(svc *service.CourseService) *CourseHandler {
 func (h *CourseHandler) Create(c *gin.Context) {
 	var req dto.CreateCourseReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		responder.Fail(c, http.StatusBadRequest, err.Error())
+		responder.FailBinding(c, err)
 		return
 	}
 
@@ -33,9 +34,9 @@ func (h *CourseHandler) Create(c *gin.Context) {
 		req.TeacherID, _ = uidAny.(int)
 	}
 
-	id, err := h.svc.Create(c.Request.Context(), model.Course{Title: req.Title, TeacherID: req.TeacherID})
+	id, err := h.svc.Create(c.Request.Context(), model.Course{Title: req.Title, TeacherID: req.TeacherID, Term: req.Term, Credits: req.Credits})
 	if err != nil {
-		responder.Fail(c, http.StatusBadRequest, err.Error())
+		responder.Fail(c, err)
 		return
 	}
 
@@ -43,35 +44,51 @@ func (h *CourseHandler) Create(c *gin.Context) {
 }
 
 func (h *CourseHandler) List(c *gin.Context) {
-	items, err := h.svc.List(c.Request.Context())
+	req, ok := pageRequest(c)
+	if !ok {
+		return
+	}
+
+	items, next, err := h.svc.List(c.Request.Context(), req)
 	if err != nil {
-		responder.Fail(c, http.StatusInternalServerError, err.Error())
+		responder.Fail(c, err)
 		return
 	}
 
 	out := make([]gin.H, 0, len(items))
 	for _, x := range items {
-		out = append(out, gin.H{"id": x.ID, "title": x.Title, "teacher_id": x.TeacherID, "created_at": x.CreatedAt})
+		out = append(out, gin.H{"id": x.ID, "title": x.Title, "teacher_id": x.TeacherID, "term": x.Term, "credits": x.Credits, "created_at": x.CreatedAt})
 	}
 
-	responder.OK(c, gin.H{"items": out, "count": len(out)})
+	responder.OK(c, gin.H{"items": out, "count": len(out), "next_cursor": next})
 }
 
 func (h *CourseHandler) Enroll(c *gin.Context) {
 	courseID, err := strconv.Atoi(c.Param("id"))
 	if err != nil || courseID <= 0 {
-		responder.Fail(c, http.StatusBadRequest, "invalid course id")
+		responder.Fail(c, apperr.Invalid("invalid course id"))
 		return
 	}
 
 	var req dto.EnrollReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		responder.Fail(c, http.StatusBadRequest, err.Error())
+		responder.FailBinding(c, err)
+		return
+	}
+
+	roleAny, _ := c.Get(middleware.CtxRoleKey)
+	if role, _ := roleAny.(string); req.Override && role != "admin" {
+		responder.Fail(c, service.ErrOverrideNotPermitted)
 		return
 	}
 
-	if err := h.svc.Enroll(c.Request.Context(), courseID, req.StudentID); err != nil {
-		responder.Fail(c, http.StatusBadRequest, err.Error())
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	if err := h.svc.Enroll(c.Request.Context(), courseID, req.StudentID, uid, req.Override); err != nil {
+		if failRequirements(c, err) {
+			return
+		}
+		responder.Fail(c, err)
 		return
 	}
 
@@ -85,65 +102,185 @@ func (h *CourseHandler) MyCourses(c *gin.Context) {
 	roleAny, _ := c.Get(middleware.CtxRoleKey)
 	role, _ := roleAny.(string)
 
+	req, ok := pageRequest(c)
+	if !ok {
+		return
+	}
+
 	var items []model.Course
+	var next string
 	var err error
 
 	switch role {
 	case "student":
-		items, err = h.svc.ListByStudent(c.Request.Context(), uid)
+		items, next, err = h.svc.ListByStudent(c.Request.Context(), uid, c.Query("status"), req)
 	case "teacher":
-		items, err = h.svc.ListByTeacher(c.Request.Context(), uid)
+		items, next, err = h.svc.ListByTeacher(c.Request.Context(), uid, req)
 	case "admin":
-		items, err = h.svc.List(c.Request.Context())
+		items, next, err = h.svc.List(c.Request.Context(), req)
 	default:
-		responder.Fail(c, http.StatusForbidden, "unknown role")
+		responder.Fail(c, apperr.Forbidden("forbidden", "unknown role"))
 		return
 	}
 	if err != nil {
-		responder.Fail(c, http.StatusInternalServerError, err.Error())
+		responder.Fail(c, err)
 		return
 	}
 
 	out := make([]gin.H, 0, len(items))
 	for _, x := range items {
-		out = append(out, gin.H{"id": x.ID, "title": x.Title, "teacher_id": x.TeacherID, "created_at": x.CreatedAt})
+		out = append(out, gin.H{"id": x.ID, "title": x.Title, "teacher_id": x.TeacherID, "term": x.Term, "credits": x.Credits, "created_at": x.CreatedAt})
 	}
 
-	responder.OK(c, gin.H{"items": out, "count": len(out)})
+	responder.OK(c, gin.H{"items": out, "count": len(out), "next_cursor": next})
 }
 
 
+// Teacher/admin: withdraw a student; the enrollment stays in their history
 func (h *CourseHandler) Unenroll(c *gin.Context) {
 	courseID, err := strconv.Atoi(c.Param("id"))
 	if err != nil || courseID <= 0 {
-		responder.Fail(c, http.StatusBadRequest, "invalid course id")
+		responder.Fail(c, apperr.Invalid("invalid course id"))
 		return
 	}
 
-	var req dto.EnrollReq
+	var req dto.UnenrollReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		responder.Fail(c, http.StatusBadRequest, err.Error())
+		responder.FailBinding(c, err)
 		return
 	}
 
-	if err := h.svc.Unenroll(c.Request.Context(), courseID, req.StudentID); err != nil {
-		responder.Fail(c, http.StatusBadRequest, err.Error())
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	promoted, err := h.svc.Unenroll(c.Request.Context(), courseID, req.StudentID, uid, req.Reason)
+	if err != nil {
+		responder.Fail(c, err)
 		return
 	}
 
-	responder.OK(c, gin.H{"status": "unenrolled"})
+	responder.OK(c, gin.H{"status": "withdrawn", "promoted": promoted})
+}
+
+// Teacher/admin: drop/withdraw a student or record the final result
+func (h *CourseHandler) SetEnrollmentStatus(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	studentID, err := strconv.Atoi(c.Param("studentID"))
+	if err != nil || studentID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid student id"))
+		return
+	}
+
+	var req dto.EnrollmentStatusReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	promoted, err := h.svc.SetEnrollmentStatus(c.Request.Context(), courseID, studentID, model.EnrollmentChange{
+		Status: req.Status, Reason: req.Reason, Grade: req.Grade, ChangedBy: uid,
+	})
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.OK(c, gin.H{"status": req.Status, "promoted": promoted})
+}
+
+// Student: own transcript
+func (h *CourseHandler) MyTranscript(c *gin.Context) {
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	h.transcript(c, uid)
+}
+
+// Teacher/admin: transcript of any student
+func (h *CourseHandler) Transcript(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid user id"))
+		return
+	}
+	h.transcript(c, id)
+}
+
+// transcript answers with the student's courses (?status=completed for the
+// official view) and totals over the finished ones.
+func (h *CourseHandler) transcript(c *gin.Context, studentID int) {
+	items, err := h.svc.Transcript(c.Request.Context(), studentID, c.Query("status"))
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	var (
+		completed, failed int
+		credits           float64
+		gradeSum          float64
+		graded            int
+	)
+	out := make([]gin.H, 0, len(items))
+	for _, x := range items {
+		switch x.Status {
+		case "completed":
+			completed++
+			credits += x.Credits
+		case "failed":
+			failed++
+		}
+		if x.Grade != nil && (x.Status == "completed" || x.Status == "failed") {
+			gradeSum += *x.Grade
+			graded++
+		}
+
+		history := make([]gin.H, 0, len(x.History))
+		for _, ev := range x.History {
+			history = append(history, gin.H{
+				"from": ev.OldStatus, "to": ev.Status, "reason": ev.Reason,
+				"changed_by": ev.ChangedBy, "changed_by_name": ev.ChangedByName, "changed_at": ev.ChangedAt,
+			})
+		}
+		out = append(out, gin.H{
+			"course_id": x.CourseID, "course_title": x.CourseTitle, "teacher": x.TeacherName,
+			"term": x.Term, "credits": x.Credits, "status": x.Status, "grade": x.Grade,
+			"attendance_rate": x.AttendanceRate, "reason": x.Reason, "group": x.GroupName,
+			"enrolled_at": x.EnrolledAt, "status_changed_at": x.StatusChangedAt, "history": history,
+		})
+	}
+
+	var average any
+	if graded > 0 {
+		average = math.Round(gradeSum/float64(graded)*100) / 100
+	}
+	responder.OK(c, gin.H{
+		"student_id": studentID, "items": out, "count": len(out),
+		"completed": completed, "failed": failed, "credits_earned": credits, "average_grade": average,
+	})
 }
 
 func (h *CourseHandler) GetStudents(c *gin.Context) {
 	courseID, err := strconv.Atoi(c.Param("id"))
 	if err != nil || courseID <= 0 {
-		responder.Fail(c, http.StatusBadRequest, "invalid course id")
+		responder.Fail(c, apperr.Invalid("invalid course id"))
 		return
 	}
 
-	items, err := h.svc.GetStudents(c.Request.Context(), courseID)
+	req, ok := pageRequest(c)
+	if !ok {
+		return
+	}
+
+	items, next, err := h.svc.GetStudents(c.Request.Context(), courseID, c.Query("status"), req)
 	if err != nil {
-		responder.Fail(c, http.StatusInternalServerError, err.Error())
+		responder.Fail(c, err)
 		return
 	}
 
@@ -152,19 +289,24 @@ func (h *CourseHandler) GetStudents(c *gin.Context) {
 		out = append(out, gin.H{"id": s.ID, "full_name": s.FullName, "email": s.Email})
 	}
 
-	responder.OK(c, gin.H{"items": out, "count": len(out)})
+	responder.OK(c, gin.H{"items": out, "count": len(out), "next_cursor": next})
 }
 
 func (h *CourseHandler) GetAvailableStudents(c *gin.Context) {
 	courseID, err := strconv.Atoi(c.Param("id"))
 	if err != nil || courseID <= 0 {
-		responder.Fail(c, http.StatusBadRequest, "invalid course id")
+		responder.Fail(c, apperr.Invalid("invalid course id"))
 		return
 	}
 
-	items, err := h.svc.GetAvailableStudents(c.Request.Context(), courseID)
+	req, ok := pageRequest(c)
+	if !ok {
+		return
+	}
+
+	items, next, err := h.svc.GetAvailableStudents(c.Request.Context(), courseID, req)
 	if err != nil {
-		responder.Fail(c, http.StatusInternalServerError, err.Error())
+		responder.Fail(c, err)
 		return
 	}
 
@@ -173,5 +315,6 @@ func (h *CourseHandler) GetAvailableStudents(c *gin.Context) {
 		out = append(out, gin.H{"id": s.ID, "full_name": s.FullName, "email": s.Email})
 	}
 
-	responder.OK(c, gin.H{"items": out, "count": len(out)})
+	responder.OK(c, gin.H{"items": out, "count": len(out), "next_cursor": next})
 }
+
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/http/dto"
+	"lms-backend/internal/transport/http/middleware"
+	"lms-backend/internal/transport/http/responder"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EnrollmentHandler struct {
+	svc *service.EnrollmentService
+}
+
+func NewEnrollmentHandler(svc *service.EnrollmentService) *EnrollmentHandler {
+	return &EnrollmentHandler{svc: svc}
+}
+
+// Any logged-in user: how the course can be joined and how many seats are left
+func (h *EnrollmentHandler) GetPolicy(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	p, err := h.svc.Policy(c.Request.Context(), courseID)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, policyJSON(p))
+}
+
+// Teacher/admin: set policy, key, capacity and window
+func (h *EnrollmentHandler) SavePolicy(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	var req dto.EnrollmentPolicyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	promoted, err := h.svc.SavePolicy(c.Request.Context(), model.EnrollmentPolicy{
+		CourseID: courseID,
+		Policy:   req.Policy,
+		Capacity: req.Capacity,
+		OpensAt:  req.OpensAt,
+		ClosesAt: req.ClosesAt,
+	}, req.Key)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, gin.H{"status": "saved", "promoted": promoted})
+}
+
+// Student: join the course (body: {"key":"..."} for key-protected courses)
+func (h *EnrollmentHandler) Join(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	var req dto.EnrollmentJoinReq
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	x, err := h.svc.Request(c.Request.Context(), courseID, uid, req.Key)
+	if err != nil {
+		if failRequirements(c, err) {
+			return
+		}
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, requestJSON(x))
+}
+
+// Student: leave the course or withdraw an open request
+func (h *EnrollmentHandler) Drop(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	status, promoted, err := h.svc.Drop(c.Request.Context(), courseID, uid)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, gin.H{"status": status, "promoted": promoted})
+}
+
+// Teacher/admin: requests of a course (?status=pending|waitlisted|...)
+func (h *EnrollmentHandler) ListRequests(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	items, err := h.svc.ListRequests(c.Request.Context(), courseID, c.Query("status"))
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	out := make([]gin.H, 0, len(items))
+	for _, x := range items {
+		out = append(out, requestJSON(x))
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out)})
+}
+
+// Student: own requests with waitlist positions
+func (h *EnrollmentHandler) MyRequests(c *gin.Context) {
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	items, err := h.svc.MyRequests(c.Request.Context(), uid)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	out := make([]gin.H, 0, len(items))
+	for _, x := range items {
+		out = append(out, requestJSON(x))
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out)})
+}
+
+func (h *EnrollmentHandler) Approve(c *gin.Context) { h.review(c, true) }
+
+func (h *EnrollmentHandler) Reject(c *gin.Context) { h.review(c, false) }
+
+func (h *EnrollmentHandler) review(c *gin.Context, approve bool) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	requestID, err := strconv.Atoi(c.Param("requestID"))
+	if err != nil || requestID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid request id"))
+		return
+	}
+	var req dto.ReviewEnrollmentReq
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	x, err := h.svc.Review(c.Request.Context(), courseID, requestID, uid, approve, req.Comment)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, requestJSON(x))
+}
+
+func policyJSON(p model.EnrollmentPolicy) gin.H {
+	seatsLeft := any(nil)
+	if p.Capacity > 0 {
+		seatsLeft = max(p.Capacity-p.Enrolled, 0)
+	}
+	return gin.H{
+		"course_id": p.CourseID, "policy": p.Policy, "capacity": p.Capacity,
+		"opens_at": p.OpensAt, "closes_at": p.ClosesAt,
+		"enrolled": p.Enrolled, "waitlisted": p.Waitlisted, "seats_left": seatsLeft,
+	}
+}
+
+func requestJSON(x model.EnrollmentRequest) gin.H {
+	return gin.H{
+		"id": x.ID, "course_id": x.CourseID, "student_id": x.StudentID, "student_name": x.StudentName,
+		"status": x.Status, "comment": x.Comment, "created_at": x.CreatedAt,
+		"queued_at": x.QueuedAt, "position": x.Position,
+		"decided_by": x.DecidedBy, "decided_at": x.DecidedAt,
+	}
+}
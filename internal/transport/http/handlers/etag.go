@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"lms-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setVersion sends the version of the returned row as its ETag.
+func setVersion(c *gin.Context, version int) {
+	c.Header("ETag", `"`+strconv.Itoa(version)+`"`)
+}
+
+// setContentETag sends a weak ETag of v, for responses that carry more than
+// v, e.g. a timestamp, and would otherwise never compare equal.
+func setContentETag(c *gin.Context, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	sum := sha256.Sum256(b)
+	c.Header("ETag", `W/"`+hex.EncodeToString(sum[:16])+`"`)
+}
+
+// ifMatch returns the version named by If-Match, service.AnyVersion for "*",
+// and ok false when the header is missing. An ETag that is not a version
+// matches nothing.
+func ifMatch(c *gin.Context) (version int, ok bool, err error) {
+	h := strings.TrimSpace(c.GetHeader("If-Match"))
+	switch h {
+	case "":
+		return 0, false, nil
+	case "*":
+		return service.AnyVersion, true, nil
+	}
+	v, err := strconv.Atoi(strings.Trim(h, `"`))
+	if err != nil || v <= 0 || !strings.HasPrefix(h, `"`) {
+		return 0, true, service.ErrVersionMismatch
+	}
+	return v, true, nil
+}
+
+// requireIfMatch is ifMatch for updates, which must name a version.
+func requireIfMatch(c *gin.Context) (int, error) {
+	v, ok, err := ifMatch(c)
+	if err == nil && !ok {
+		err = service.ErrVersionRequired
+	}
+	return v, err
+}
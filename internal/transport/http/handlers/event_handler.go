@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/realtime"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/http/middleware"
+	"lms-backend/internal/transport/http/responder"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	keepAlive  = 25 * time.Second // SSE comment / WebSocket ping interval
+	pongWait   = 60 * time.Second
+	writeWait  = 10 * time.Second
+	sseRetryMS = 3000
+)
+
+// a ticket or token, not a cookie, authenticates the socket, so other
+// origins cannot ride on a user's session
+var upgrader = websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+
+type EventHandler struct {
+	svc *service.EventService
+}
+
+func NewEventHandler(svc *service.EventService) *EventHandler {
+	return &EventHandler{svc: svc}
+}
+
+// Any logged-in user: ?course_id=1&course_id=2 (or 1,2), Server-Sent Events.
+func (h *EventHandler) Stream(c *gin.Context) {
+	ids, err := courseIDs(c)
+	if err == nil && len(ids) == 0 {
+		err = apperr.Field("course_id", "is required")
+	}
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	uid, role := caller(c)
+	sub, err := h.svc.Subscribe(c.Request.Context(), uid, role, ids)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no") // no proxy buffering
+	c.Status(http.StatusOK)
+	c.Writer.WriteString("retry: " + strconv.Itoa(sseRetryMS) + "\n\n")
+	c.Writer.Flush()
+
+	ping := time.NewTicker(keepAlive)
+	defer ping.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case e, ok := <-sub.C:
+			if !ok {
+				return // too slow; the client reconnects
+			}
+			c.SSEvent(e.Type, e)
+			c.Writer.Flush()
+		case <-ping.C:
+			c.Writer.WriteString(": ping\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// socketCommand is what WebSocket clients send:
+// {"action":"subscribe"|"unsubscribe","course_id":1}
+type socketCommand struct {
+	Action   string `json:"action"`
+	CourseID int    `json:"course_id"`
+}
+
+// Any logged-in user: WebSocket; ?course_id= as for Stream, more courses
+// can be joined and left with socketCommands
+func (h *EventHandler) Socket(c *gin.Context) {
+	ids, err := courseIDs(c)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	ctx := c.Request.Context()
+	uid, role := caller(c)
+	sub, err := h.svc.Subscribe(ctx, uid, role, ids)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	defer sub.Close()
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return // the upgrader has answered
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	cmds := make(chan socketCommand)
+	go func() {
+		defer close(cmds)
+		conn.SetReadLimit(1024)
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+		conn.SetPongHandler(func(string) error { return conn.SetReadDeadline(time.Now().Add(pongWait)) })
+		for {
+			var cmd socketCommand
+			if err := conn.ReadJSON(&cmd); err != nil {
+				return
+			}
+			select {
+			case cmds <- cmd:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(keepAlive)
+	defer ping.Stop()
+	for {
+		var msg any
+		select {
+		case e, ok := <-sub.C:
+			if !ok {
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too slow, reconnect"), time.Now().Add(writeWait))
+				return
+			}
+			msg = e
+		case cmd, ok := <-cmds:
+			if !ok {
+				return // closed by the client
+			}
+			msg = h.command(c, sub, cmd)
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
+			}
+			continue
+		}
+		conn.SetWriteDeadline(time.Now().Add(writeWait))
+		if err := conn.WriteJSON(msg); err != nil {
+			return
+		}
+	}
+}
+
+// command applies a socketCommand and returns the reply.
+func (h *EventHandler) command(c *gin.Context, sub *realtime.Subscription, cmd socketCommand) gin.H {
+	var err error
+	switch cmd.Action {
+	case "subscribe":
+		uid, role := caller(c)
+		err = h.svc.Join(c.Request.Context(), sub, uid, role, cmd.CourseID)
+	case "unsubscribe":
+		sub.Leave(cmd.CourseID)
+	default:
+		err = apperr.Field("action", "must be subscribe|unsubscribe")
+	}
+	if err != nil {
+		code, msg := "internal", "internal server error"
+		if e, ok := apperr.As(err); ok && e.Kind != apperr.KindInternal {
+			code, msg = e.Code, err.Error()
+		}
+		return gin.H{"type": "error", "course_id": cmd.CourseID, "error": gin.H{"code": code, "message": msg}}
+	}
+	return gin.H{"type": cmd.Action + "d", "course_id": cmd.CourseID, "courses": sub.Courses()}
+}
+
+func courseIDs(c *gin.Context) ([]int, error) {
+	var ids []int
+	for _, v := range c.QueryArray("course_id") {
+		for _, s := range strings.Split(v, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil || id <= 0 {
+				return nil, apperr.Field("course_id", "must be a list of course ids")
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func caller(c *gin.Context) (int, string) {
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	return uid, c.GetString(middleware.CtxRoleKey)
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/http/dto"
+	"lms-backend/internal/transport/http/middleware"
+	"lms-backend/internal/transport/http/responder"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExcuseHandler struct {
+	svc *service.ExcuseService
+}
+
+func NewExcuseHandler(svc *service.ExcuseService) *ExcuseHandler {
+	return &ExcuseHandler{svc: svc}
+}
+
+// Student: submit an excuse (multipart: course_id, date_from, date_to, reason, document)
+func (h *ExcuseHandler) Submit(c *gin.Context) {
+	var req dto.SubmitExcuseReq
+	if err := c.ShouldBind(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+	from, err := time.Parse("2006-01-02", req.DateFrom)
+	if err != nil {
+		responder.Fail(c, apperr.Invalid("invalid date_from"))
+		return
+	}
+	to, err := time.Parse("2006-01-02", req.DateTo)
+	if err != nil {
+		responder.Fail(c, apperr.Invalid("invalid date_to"))
+		return
+	}
+
+	var doc *service.Document
+	fh, err := c.FormFile("document")
+	switch {
+	case err == nil:
+		f, err := fh.Open()
+		if err != nil {
+			responder.Fail(c, err)
+			return
+		}
+		defer f.Close()
+		doc = &service.Document{Name: fh.Filename, Size: fh.Size, Content: f}
+	case errors.Is(err, http.ErrMissingFile):
+	default:
+		responder.FailBinding(c, err)
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	id, err := h.svc.Submit(c.Request.Context(), model.AttendanceExcuse{
+		StudentID: uid,
+		CourseID:  req.CourseID,
+		DateFrom:  from,
+		DateTo:    to,
+		Reason:    req.Reason,
+	}, doc)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.Created(c, gin.H{"id": id})
+}
+
+func (h *ExcuseHandler) MyExcuses(c *gin.Context) {
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	items, err := h.svc.ListMine(c.Request.Context(), uid)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	out := make([]gin.H, 0, len(items))
+	for _, x := range items {
+		out = append(out, excuseJSON(x))
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out)})
+}
+
+// Teacher/admin: list excuses (optional ?status= and ?course_id=)
+func (h *ExcuseHandler) List(c *gin.Context) {
+	courseID := 0
+	if v := c.Query("course_id"); v != "" {
+		x, err := strconv.Atoi(v)
+		if err != nil || x < 0 {
+			responder.Fail(c, apperr.Invalid("invalid course_id"))
+			return
+		}
+		courseID = x
+	}
+
+	items, err := h.svc.List(c.Request.Context(), c.Query("status"), courseID)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	out := make([]gin.H, 0, len(items))
+	for _, x := range items {
+		out = append(out, excuseJSON(x))
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out)})
+}
+
+// Download the attached document. Students may only download their own.
+func (h *ExcuseHandler) Document(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid excuse id"))
+		return
+	}
+
+	e, err := h.svc.Get(c.Request.Context(), id)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	roleAny, _ := c.Get(middleware.CtxRoleKey)
+	role, _ := roleAny.(string)
+	if role == "student" && e.StudentID != uid {
+		responder.Fail(c, apperr.ErrForbidden)
+		return
+	}
+
+	f, err := h.svc.OpenDocument(e)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	c.DataFromReader(http.StatusOK, st.Size(), e.DocumentType, f, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": e.DocumentName}),
+	})
+}
+
+func (h *ExcuseHandler) Approve(c *gin.Context) { h.review(c, true) }
+
+func (h *ExcuseHandler) Reject(c *gin.Context) { h.review(c, false) }
+
+func (h *ExcuseHandler) review(c *gin.Context, approve bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid excuse id"))
+		return
+	}
+
+	// body is optional
+	var req dto.ReviewExcuseReq
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	roleAny, _ := c.Get(middleware.CtxRoleKey)
+	role, _ := roleAny.(string)
+
+	e, converted, err := h.svc.Review(c.Request.Context(), id, uid, role, approve, req.Comment)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	out := excuseJSON(e)
+	out["converted"] = converted
+	responder.OK(c, out)
+}
+
+func excuseJSON(x model.AttendanceExcuse) gin.H {
+	return gin.H{
+		"id": x.ID, "student_id": x.StudentID, "course_id": x.CourseID,
+		"date_from": x.DateFrom.Format("2006-01-02"), "date_to": x.DateTo.Format("2006-01-02"),
+		"reason": x.Reason, "has_document": x.DocumentPath != "",
+		"document_name": x.DocumentName, "document_type": x.DocumentType,
+		"status": x.Status, "reviewed_by": x.ReviewedBy, "review_comment": x.ReviewComment,
+		"created_at": x.CreatedAt, "reviewed_at": x.ReviewedAt,
+	}
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	graphqlapi "lms-backend/internal/transport/graphql"
+	"lms-backend/internal/transport/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GraphQLHandler struct {
+	srv http.Handler
+}
+
+func NewGraphQLHandler(srv http.Handler) *GraphQLHandler {
+	return &GraphQLHandler{srv: srv}
+}
+
+// Any logged-in user: GET ?query=&variables= or POST {"query","variables"}
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	ctx := graphqlapi.WithCaller(c.Request.Context(), uid, c.GetString(middleware.CtxRoleKey))
+	h.srv.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+}
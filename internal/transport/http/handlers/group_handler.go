@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"strconv"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/http/dto"
+	"lms-backend/internal/transport/http/middleware"
+	"lms-backend/internal/transport/http/responder"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GroupHandler struct {
+	svc *service.GroupService
+}
+
+func NewGroupHandler(svc *service.GroupService) *GroupHandler {
+	return &GroupHandler{svc: svc}
+}
+
+func (h *GroupHandler) List(c *gin.Context) {
+	items, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	out := make([]gin.H, 0, len(items))
+	for _, g := range items {
+		out = append(out, groupJSON(g))
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out)})
+}
+
+// Group with its members
+func (h *GroupHandler) Get(c *gin.Context) {
+	id, ok := groupID(c, "id")
+	if !ok {
+		return
+	}
+	g, members, err := h.svc.Get(c.Request.Context(), id)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	out := make([]gin.H, 0, len(members))
+	for _, u := range members {
+		out = append(out, gin.H{"id": u.ID, "full_name": u.FullName, "email": u.Email})
+	}
+	res := groupJSON(g)
+	res["members"] = out
+	responder.OK(c, res)
+}
+
+func (h *GroupHandler) Create(c *gin.Context) {
+	var req dto.GroupReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+	id, err := h.svc.Create(c.Request.Context(), model.StudentGroup{Name: req.Name, Faculty: req.Faculty, Year: req.Year})
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.Created(c, gin.H{"id": id})
+}
+
+// Rename/edit; members' profiles follow the new name
+func (h *GroupHandler) Update(c *gin.Context) {
+	id, ok := groupID(c, "id")
+	if !ok {
+		return
+	}
+	var req dto.GroupReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+	err := h.svc.Update(c.Request.Context(), model.StudentGroup{ID: id, Name: req.Name, Faculty: req.Faculty, Year: req.Year})
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, gin.H{"status": "updated"})
+}
+
+func (h *GroupHandler) Delete(c *gin.Context) {
+	id, ok := groupID(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.svc.Delete(c.Request.Context(), id); err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, gin.H{"status": "deleted"})
+}
+
+// Move students into the group (body: {"student_ids":[...]})
+func (h *GroupHandler) AddMembers(c *gin.Context) {
+	id, ok := groupID(c, "id")
+	if !ok {
+		return
+	}
+	var req dto.GroupMembersReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	res, err := h.svc.AddMembers(c.Request.Context(), id, req.StudentIDs, uid, req.Override)
+	if failRequirements(c, err) {
+		return
+	}
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, syncJSON(res))
+}
+
+func (h *GroupHandler) RemoveMember(c *gin.Context) {
+	id, ok := groupID(c, "id")
+	if !ok {
+		return
+	}
+	studentID, err := strconv.Atoi(c.Param("studentID"))
+	if err != nil || studentID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid student id"))
+		return
+	}
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	res, err := h.svc.RemoveMember(c.Request.Context(), id, studentID, uid)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, syncJSON(res))
+}
+
+func (h *GroupHandler) CourseGroups(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	items, err := h.svc.CourseGroups(c.Request.Context(), courseID)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	out := make([]gin.H, 0, len(items))
+	for _, g := range items {
+		out = append(out, groupJSON(g))
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out)})
+}
+
+// Enroll a whole group in the course (body: {"group_id":1})
+func (h *GroupHandler) EnrollGroup(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	var req dto.EnrollGroupReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	roleAny, _ := c.Get(middleware.CtxRoleKey)
+	if role, _ := roleAny.(string); req.Override && role != "admin" {
+		responder.Fail(c, service.ErrOverrideNotPermitted)
+		return
+	}
+	res, err := h.svc.EnrollGroup(c.Request.Context(), courseID, req.GroupID, uid, req.Override)
+	if failRequirements(c, err) {
+		return
+	}
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, syncJSON(res))
+}
+
+func (h *GroupHandler) UnenrollGroup(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	id, ok := groupID(c, "groupID")
+	if !ok {
+		return
+	}
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	res, err := h.svc.UnenrollGroup(c.Request.Context(), courseID, id, uid)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, syncJSON(res))
+}
+
+func groupID(c *gin.Context, param string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(param))
+	if err != nil || id <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid group id"))
+		return 0, false
+	}
+	return id, true
+}
+
+func groupJSON(g model.StudentGroup) gin.H {
+	return gin.H{
+		"id": g.ID, "name": g.Name, "faculty": g.Faculty, "year": g.Year,
+		"member_count": g.Members, "created_at": g.CreatedAt,
+	}
+}
+
+func syncJSON(r model.GroupSync) gin.H {
+	return gin.H{"enrolled": r.Enrolled, "unenrolled": r.Unenrolled}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/http/middleware"
+	"lms-backend/internal/transport/http/responder"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImportHandler struct {
+	svc *service.ImportService
+}
+
+func NewImportHandler(svc *service.ImportService) *ImportHandler {
+	return &ImportHandler{svc: svc}
+}
+
+// Admin: import users and enrollments from a CSV/XLSX roster
+// (multipart "file"; ?dry_run=true previews, ?send_invites=true mails new users).
+func (h *ImportHandler) Users(c *gin.Context) {
+	fh, err := c.FormFile("file")
+	if err != nil {
+		responder.Fail(c, apperr.Invalid("file is required"))
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	defer f.Close()
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	report, err := h.svc.ImportRoster(c.Request.Context(), fh.Filename, f, service.ImportOptions{
+		DryRun:      c.Query("dry_run") == "true",
+		SendInvites: c.Query("send_invites") == "true",
+		Override:    c.Query("override") == "true",
+		ActorID:     uid,
+	})
+	if errors.Is(err, service.ErrImportRejected) {
+		responder.FailWithData(c, err, importReportJSON(report))
+		return
+	}
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.OK(c, importReportJSON(report))
+}
+
+func importReportJSON(r model.ImportReport) gin.H {
+	errs := make([]gin.H, 0, len(r.Errors))
+	for _, e := range r.Errors {
+		errs = append(errs, gin.H{"line": e.Line, "field": e.Field, "message": e.Message})
+	}
+	items := make([]gin.H, 0, len(r.Results))
+	for _, x := range r.Results {
+		items = append(items, gin.H{
+			"line": x.Line, "email": x.Email, "user_id": x.UserID,
+			"action": x.Action, "enrolled": x.Enrolled,
+		})
+	}
+	return gin.H{
+		"dry_run": r.DryRun, "rows": r.Rows,
+		"created": r.Created, "updated": r.Updated, "unchanged": r.Unchanged,
+		"enrollments": r.Enrollments, "invited": r.Invited,
+		"errors": errs, "items": items,
+	}
+}
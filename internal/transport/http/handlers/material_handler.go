@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"strconv"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/http/dto"
+	"lms-backend/internal/transport/http/middleware"
+	"lms-backend/internal/transport/http/responder"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MaterialHandler struct {
+	svc *service.MaterialService
+}
+
+func NewMaterialHandler(svc *service.MaterialService) *MaterialHandler {
+	return &MaterialHandler{svc: svc}
+}
+
+// Staff and enrolled students: materials of a course, without their text
+func (h *MaterialHandler) List(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	roleAny, _ := c.Get(middleware.CtxRoleKey)
+	role, _ := roleAny.(string)
+
+	items, err := h.svc.List(c.Request.Context(), courseID, uid, role)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	out := make([]gin.H, 0, len(items))
+	for _, m := range items {
+		out = append(out, gin.H{"id": m.ID, "course_id": m.CourseID, "title": m.Title, "created_by": m.CreatedBy, "created_at": m.CreatedAt})
+	}
+	responder.OK(c, gin.H{"items": out, "count": len(out)})
+}
+
+// Staff and enrolled students: one material with its text
+func (h *MaterialHandler) Get(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	id, err := strconv.Atoi(c.Param("materialID"))
+	if err != nil || id <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid material id"))
+		return
+	}
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	roleAny, _ := c.Get(middleware.CtxRoleKey)
+	role, _ := roleAny.(string)
+
+	m, err := h.svc.Get(c.Request.Context(), courseID, id, uid, role)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, gin.H{
+		"id": m.ID, "course_id": m.CourseID, "title": m.Title, "body": m.Body,
+		"created_by": m.CreatedBy, "created_at": m.CreatedAt,
+	})
+}
+
+// Teacher/admin: publish a material
+func (h *MaterialHandler) Create(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	var req dto.MaterialReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	id, err := h.svc.Create(c.Request.Context(), model.CourseMaterial{
+		CourseID: courseID, Title: req.Title, Body: req.Body, CreatedBy: &uid,
+	})
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.Created(c, gin.H{"id": id})
+}
+
+// Teacher/admin: remove a material
+func (h *MaterialHandler) Delete(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	id, err := strconv.Atoi(c.Param("materialID"))
+	if err != nil || id <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid material id"))
+		return
+	}
+	if err := h.svc.Delete(c.Request.Context(), courseID, id); err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, gin.H{"status": "deleted"})
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/http/dto"
+	"lms-backend/internal/transport/http/middleware"
+	"lms-backend/internal/transport/http/responder"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileHandler struct {
+	svc *service.ProfileService
+}
+
+func NewProfileHandler(svc *service.ProfileService) *ProfileHandler {
+	return &ProfileHandler{svc: svc}
+}
+
+// Any logged-in user: own profile
+func (h *ProfileHandler) Mine(c *gin.Context) {
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	h.get(c, uid)
+}
+
+// Admin/teacher: profile of any user
+func (h *ProfileHandler) Get(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || userID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid user id"))
+		return
+	}
+	h.get(c, userID)
+}
+
+func (h *ProfileHandler) get(c *gin.Context, userID int) {
+	p, err := h.svc.Get(c.Request.Context(), userID)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, profileJSON(p))
+}
+
+// Any logged-in user: change own phone and language
+func (h *ProfileHandler) UpdateMine(c *gin.Context) {
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	h.update(c, uid, false)
+}
+
+// Admin: change any profile field
+func (h *ProfileHandler) Update(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || userID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid user id"))
+		return
+	}
+	h.update(c, userID, true)
+}
+
+func (h *ProfileHandler) update(c *gin.Context, userID int, asAdmin bool) {
+	var req dto.UpdateProfileReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	p, err := h.svc.Update(c.Request.Context(), userID, uid, model.ProfilePatch{
+		StudentNumber: req.StudentNumber,
+		Group:         req.Group,
+		Year:          req.Year,
+		Faculty:       req.Faculty,
+		Phone:         req.Phone,
+		Language:      req.Language,
+		Department:    req.Department,
+		Title:         req.Title,
+	}, asAdmin)
+	if failRequirements(c, err) {
+		return
+	}
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, profileJSON(p))
+}
+
+// Any logged-in user: upload own avatar (multipart "avatar", JPEG or PNG)
+func (h *ProfileHandler) UploadAvatar(c *gin.Context) {
+	fh, err := c.FormFile("avatar")
+	if err != nil {
+		responder.Fail(c, apperr.Invalid("avatar is required"))
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	defer f.Close()
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	if err := h.svc.SetAvatar(c.Request.Context(), uid, f); err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, gin.H{"avatar_url": avatarURL(uid)})
+}
+
+func (h *ProfileHandler) DeleteAvatar(c *gin.Context) {
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	if err := h.svc.DeleteAvatar(c.Request.Context(), uid); err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, gin.H{"status": "deleted"})
+}
+
+// Any logged-in user: avatar image of a user
+func (h *ProfileHandler) Avatar(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || userID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid user id"))
+		return
+	}
+
+	f, err := h.svc.Avatar(c.Request.Context(), userID)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.DataFromReader(http.StatusOK, st.Size(), "image/jpeg", f, nil)
+}
+
+func profileJSON(p model.UserProfile) gin.H {
+	out := gin.H{
+		"user_id": p.UserID, "student_number": p.StudentNumber, "group": p.Group,
+		"year": p.Year, "faculty": p.Faculty, "phone": p.Phone, "language": p.Language,
+		"department": p.Department, "title": p.Title, "avatar_url": nil,
+	}
+	if p.AvatarPath != "" {
+		out["avatar_url"] = avatarURL(p.UserID)
+	}
+	return out
+}
+
+func avatarURL(userID int) string {
+	return fmt.Sprintf("/api/v1/users/%d/avatar", userID)
+}
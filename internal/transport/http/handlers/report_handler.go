@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/export"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/http/responder"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	svc *service.ReportService
+}
+
+func NewReportHandler(svc *service.ReportService) *ReportHandler {
+	return &ReportHandler{svc: svc}
+}
+
+// Teacher/admin: attendance register (students x lesson dates) as a file.
+// ?format=csv|xlsx|pdf (default csv), optional ?from= and ?to= (YYYY-MM-DD).
+func (h *ReportHandler) ExportAttendance(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+
+	name := c.DefaultQuery("format", "csv")
+	format, ok := export.Formats[name]
+	if !ok {
+		responder.Fail(c, apperr.Invalid("format must be csv, xlsx or pdf"))
+		return
+	}
+
+	var from, to time.Time
+	if v := c.Query("from"); v != "" {
+		if from, err = time.Parse("2006-01-02", v); err != nil {
+			responder.Fail(c, apperr.Invalid("from must be YYYY-MM-DD"))
+			return
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if to, err = time.Parse("2006-01-02", v); err != nil {
+			responder.Fail(c, apperr.Invalid("to must be YYYY-MM-DD"))
+			return
+		}
+	}
+
+	meta, err := h.svc.RegisterMeta(c.Request.Context(), courseID, from, to)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	if name == "pdf" && len(meta.Dates) > export.PDFMaxDates {
+		responder.Fail(c, apperr.Invalid(fmt.Sprintf("%d lesson dates do not fit a pdf page (max %d); narrow the range or use csv/xlsx", len(meta.Dates), export.PDFMaxDates)))
+		return
+	}
+
+	// From here on the body is streamed; a failure can only be logged.
+	filename := fmt.Sprintf("attendance-course-%d%s", courseID, format.Ext)
+	c.Header("Content-Type", format.ContentType)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+	if err := h.svc.WriteRegister(c.Request.Context(), meta, format.New(c.Writer)); err != nil {
+		log.Println("Error exporting attendance register:", err)
+	}
+}
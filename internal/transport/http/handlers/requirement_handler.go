@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/http/dto"
+	"lms-backend/internal/transport/http/middleware"
+	"lms-backend/internal/transport/http/responder"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RequirementHandler struct {
+	svc *service.RequirementService
+}
+
+func NewRequirementHandler(svc *service.RequirementService) *RequirementHandler {
+	return &RequirementHandler{svc: svc}
+}
+
+// Any logged-in user: requirements and weekly schedule of a course
+func (h *RequirementHandler) List(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	reqs, slots, err := h.svc.List(c.Request.Context(), courseID)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	items := make([]gin.H, 0, len(reqs))
+	for _, q := range reqs {
+		x := gin.H{"id": q.ID, "kind": q.Kind}
+		switch q.Kind {
+		case "prerequisite", "corequisite":
+			x["required_course_id"], x["required_course"] = q.RequiredCourseID, q.RequiredCourse
+			if q.MinGrade != nil {
+				x["min_grade"] = *q.MinGrade
+			}
+		case "year":
+			x["min_year"] = q.MinYear
+		case "group":
+			x["group_id"], x["group_name"] = q.GroupID, q.GroupName
+		}
+		items = append(items, x)
+	}
+	schedule := make([]gin.H, 0, len(slots))
+	for _, s := range slots {
+		schedule = append(schedule, gin.H{"weekday": s.Weekday, "starts_at": s.StartsAt, "ends_at": s.EndsAt})
+	}
+	responder.OK(c, gin.H{"items": items, "count": len(items), "schedule": schedule})
+}
+
+// Teacher/admin: add a rule
+func (h *RequirementHandler) Add(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	var req dto.RequirementReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	id, err := h.svc.Add(c.Request.Context(), model.CourseRequirement{
+		CourseID:         courseID,
+		Kind:             req.Kind,
+		RequiredCourseID: req.RequiredCourseID,
+		MinGrade:         req.MinGrade,
+		MinYear:          req.MinYear,
+		GroupID:          req.GroupID,
+	})
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.Created(c, gin.H{"id": id})
+}
+
+func (h *RequirementHandler) Delete(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	id, err := strconv.Atoi(c.Param("requirementID"))
+	if err != nil || id <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid requirement id"))
+		return
+	}
+	if err := h.svc.Delete(c.Request.Context(), courseID, id); err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, gin.H{"status": "deleted"})
+}
+
+// Teacher/admin: replace the weekly schedule
+func (h *RequirementHandler) SetSchedule(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	var req dto.ScheduleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	slots := make([]model.ScheduleSlot, 0, len(req.Slots))
+	for _, s := range req.Slots {
+		slots = append(slots, model.ScheduleSlot{CourseID: courseID, Weekday: s.Weekday, StartsAt: s.StartsAt, EndsAt: s.EndsAt})
+	}
+	if err := h.svc.SetSchedule(c.Request.Context(), courseID, slots); err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, gin.H{"status": "saved", "count": len(slots)})
+}
+
+// Students check themselves; teachers/admins pass ?student_id=
+func (h *RequirementHandler) Eligibility(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || courseID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid course id"))
+		return
+	}
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	studentID, _ := uidAny.(int)
+	roleAny, _ := c.Get(middleware.CtxRoleKey)
+	if role, _ := roleAny.(string); role != "student" {
+		studentID, err = strconv.Atoi(c.Query("student_id"))
+		if err != nil || studentID <= 0 {
+			responder.Fail(c, apperr.Invalid("student_id is required"))
+			return
+		}
+	}
+
+	unmet, err := h.svc.Check(c.Request.Context(), courseID, studentID)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+	responder.OK(c, gin.H{"eligible": len(unmet) == 0, "unmet": unmetJSON(unmet)})
+}
+
+// failRequirements answers 422 with the unmet rules, or with the students
+// that failed a group enrollment, when err is a *service.RequirementsError.
+func failRequirements(c *gin.Context, err error) bool {
+	var re *service.RequirementsError
+	if !errors.As(err, &re) {
+		return false
+	}
+	if re.Students != nil {
+		students := make([]gin.H, 0, len(re.Students))
+		for _, x := range re.Students {
+			students = append(students, gin.H{"student_id": x.StudentID, "course_id": x.CourseID, "unmet": unmetJSON(x.Unmet)})
+		}
+		responder.FailWithData(c, err, gin.H{"students": students})
+		return true
+	}
+	responder.FailWithData(c, err, gin.H{"unmet": unmetJSON(re.Unmet)})
+	return true
+}
+
+func unmetJSON(unmet []model.UnmetRule) []gin.H {
+	out := make([]gin.H, 0, len(unmet))
+	for _, u := range unmet {
+		x := gin.H{"rule": u.Rule, "message": u.Message}
+		if u.RequirementID > 0 {
+			x["requirement_id"] = u.RequirementID
+		}
+		if u.CourseID > 0 {
+			x["course_id"], x["course_title"] = u.CourseID, u.CourseTitle
+		}
+		switch u.Rule {
+		case "min_grade":
+			x["min_grade"], x["grade"] = u.MinGrade, u.Grade
+		case "year":
+			x["min_year"], x["year"] = u.MinYear, u.Year
+		case "group":
+			x["group_id"], x["group_name"] = u.GroupID, u.GroupName
+		case "schedule_conflict":
+			x["weekday"], x["starts_at"], x["ends_at"] = u.Weekday, u.StartsAt, u.EndsAt
+		}
+		out = append(out, x)
+	}
+	return out
+}
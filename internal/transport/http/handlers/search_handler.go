@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/http/middleware"
+	"lms-backend/internal/transport/http/responder"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	svc *service.SearchService
+}
+
+func NewSearchHandler(svc *service.SearchService) *SearchHandler {
+	return &SearchHandler{svc: svc}
+}
+
+// Any logged-in user: ?q=&type=users,courses,materials&limit=
+func (h *SearchHandler) Search(c *gin.Context) {
+	var kinds []string
+	if v := c.Query("type"); v != "" {
+		kinds = strings.Split(v, ",")
+	}
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			responder.Fail(c, apperr.Invalid("invalid limit"))
+			return
+		}
+		limit = n
+	}
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+	roleAny, _ := c.Get(middleware.CtxRoleKey)
+	role, _ := roleAny.(string)
+
+	res, err := h.svc.Search(c.Request.Context(), c.Query("q"), kinds, limit, uid, role)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	out := gin.H{"q": c.Query("q")}
+	if res.Users != nil {
+		out["users"] = hitsJSON(res.Users, func(x model.SearchHit) gin.H {
+			return gin.H{"id": x.ID, "full_name": x.Title, "email": x.Detail, "role": x.Role, "rank": x.Rank}
+		})
+	}
+	if res.Courses != nil {
+		out["courses"] = hitsJSON(res.Courses, func(x model.SearchHit) gin.H {
+			return gin.H{"id": x.ID, "title": x.Title, "term": x.Detail, "rank": x.Rank}
+		})
+	}
+	if res.Materials != nil {
+		out["materials"] = hitsJSON(res.Materials, func(x model.SearchHit) gin.H {
+			return gin.H{"id": x.ID, "title": x.Title, "course_id": x.CourseID, "course_title": x.Detail, "snippet": x.Snippet, "rank": x.Rank}
+		})
+	}
+	responder.OK(c, out)
+}
+
+func hitsJSON(hits []model.SearchHit, fn func(model.SearchHit) gin.H) gin.H {
+	items := make([]gin.H, 0, len(hits))
+	for _, x := range hits {
+		items = append(items, fn(x))
+	}
+	return gin.H{"items": items, "count": len(items)}
+}
@@ -1,11 +1,12 @@
 package handlers
 
 import (
-	"net/http"
 	"strconv"
 	"time"
 
+	"lms-backend/internal/apperr"
 	"lms-backend/internal/domain/model"
+	"lms-backend/internal/pagination"
 	"lms-backend/internal/service"
 	"lms-backend/internal/transport/http/dto"
 	"lms-backend/internal/transport/http/middleware"
@@ -25,7 +26,7 @@ func NewUserHandler(svc *service.UserService) *UserHandler {
 func (h *UserHandler) Create(c *gin.Context) {
 	var req dto.CreateUserReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		responder.Fail(c, http.StatusBadRequest, err.Error())
+		responder.FailBinding(c, err)
 		return
 	}
 
@@ -35,7 +36,7 @@ func (h *UserHandler) Create(c *gin.Context) {
 		RoleID:   req.RoleID,
 	}, req.Password)
 	if err != nil {
-		responder.Fail(c, http.StatusBadRequest, err.Error())
+		responder.Fail(c, err)
 		return
 	}
 
@@ -43,40 +44,54 @@ func (h *UserHandler) Create(c *gin.Context) {
 }
 
 func (h *UserHandler) List(c *gin.Context) {
-	users, err := h.svc.List(c.Request.Context())
+	req, ok := pageRequest(c)
+	if !ok {
+		return
+	}
+
+	users, next, err := h.svc.List(c.Request.Context(), req)
 	if err != nil {
-		responder.Fail(c, http.StatusInternalServerError, err.Error())
+		responder.Fail(c, err)
 		return
 	}
 
 	out := make([]gin.H, 0, len(users))
 	for _, u := range users {
-		out = append(out, gin.H{"id": u.ID, "email": u.Email, "full_name": u.FullName, "role_id": u.RoleID})
+		out = append(out, gin.H{"id": u.ID, "email": u.Email, "full_name": u.FullName, "role_id": u.RoleID, "active": u.Active, "created_at": u.CreatedAt, "version": u.Version})
 	}
 
-	responder.OK(c, gin.H{"items": out, "count": len(out), "ts": time.Now()})
+	setContentETag(c, gin.H{"items": out, "next_cursor": next}) // without ts
+	responder.OK(c, gin.H{"items": out, "count": len(out), "next_cursor": next, "ts": time.Now()})
 }
 
 // Admin: change role of user by id (body: { "role": "teacher" })
 func (h *UserHandler) ChangeRole(c *gin.Context) {
 	userID, err := strconv.Atoi(c.Param("id"))
 	if err != nil || userID <= 0 {
-		responder.Fail(c, http.StatusBadRequest, "invalid user id")
+		responder.Fail(c, apperr.Invalid("invalid user id"))
 		return
 	}
 
 	var req dto.ChangeRoleReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		responder.Fail(c, http.StatusBadRequest, err.Error())
+		responder.FailBinding(c, err)
 		return
 	}
 
-	if err := h.svc.ChangeRole(c.Request.Context(), userID, req.Role); err != nil {
-		responder.Fail(c, http.StatusBadRequest, err.Error())
+	version, err := requireIfMatch(c)
+	if err != nil {
+		responder.Fail(c, err)
 		return
 	}
 
-	responder.OK(c, gin.H{"status": "updated"})
+	version, err = h.svc.ChangeRole(c.Request.Context(), userID, req.Role, version)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	setVersion(c, version)
+	responder.OK(c, gin.H{"status": "updated", "version": version})
 }
 
 // Any logged-in user: returns own profile based on JWT
@@ -86,23 +101,144 @@ func (h *UserHandler) Me(c *gin.Context) {
 
 	u, role, err := h.svc.Me(c.Request.Context(), uid)
 	if err != nil {
-		responder.Fail(c, http.StatusInternalServerError, err.Error())
+		responder.Fail(c, err)
 		return
 	}
 
+	setVersion(c, u.Version)
 	responder.OK(c, gin.H{
 		"id": u.ID,
 		"email": u.Email,
 		"full_name": u.FullName,
 		"role": role,
 		"role_id": u.RoleID,
+		"active": u.Active,
+	})
+}
+
+// Any logged-in user: edit own profile (full_name; email/password need current_password)
+func (h *UserHandler) UpdateMe(c *gin.Context) {
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	var req dto.UpdateMeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	version, err := requireIfMatch(c)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	version, err = h.svc.UpdateMe(c.Request.Context(), uid, service.ProfileUpdate{
+		Email:           req.Email,
+		FullName:        req.FullName,
+		Password:        req.Password,
+		CurrentPassword: req.CurrentPassword,
+		Version:         version,
 	})
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	setVersion(c, version)
+	responder.OK(c, gin.H{"status": "updated", "version": version})
 }
 
+// Admin: one user by id (deleted users come back anonymized)
+func (h *UserHandler) Get(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || userID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid user id"))
+		return
+	}
+
+	u, role, err := h.svc.Get(c.Request.Context(), userID)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	setVersion(c, u.Version)
+	responder.OK(c, gin.H{
+		"id":         u.ID,
+		"email":      u.Email,
+		"full_name":  u.FullName,
+		"role":       role,
+		"role_id":    u.RoleID,
+		"active":     u.Active,
+		"deleted_at": u.DeletedAt,
+	})
+}
+
+// Admin: edit any of email, full_name, role, active, password
+func (h *UserHandler) Update(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || userID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid user id"))
+		return
+	}
+
+	var req dto.UpdateUserReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.FailBinding(c, err)
+		return
+	}
+
+	version, err := requireIfMatch(c)
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	version, err = h.svc.Update(c.Request.Context(), uid, userID, service.UserUpdate{
+		Email:    req.Email,
+		FullName: req.FullName,
+		Role:     req.Role,
+		Active:   req.Active,
+		Password: req.Password,
+		Version:  version,
+	})
+	if err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	setVersion(c, version)
+	responder.OK(c, gin.H{"status": "updated", "version": version})
+}
+
+// Admin: soft-delete; the account is anonymized, attendance history is kept
+func (h *UserHandler) Delete(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || userID <= 0 {
+		responder.Fail(c, apperr.Invalid("invalid user id"))
+		return
+	}
+
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	uid, _ := uidAny.(int)
+
+	if err := h.svc.Delete(c.Request.Context(), uid, userID); err != nil {
+		responder.Fail(c, err)
+		return
+	}
+
+	responder.OK(c, gin.H{"status": "deleted"})
+}
+
+
 func (h *UserHandler) Roles(c *gin.Context) {
 	roles, err := h.svc.ListRoles(c.Request.Context())
 	if err != nil {
-		responder.Fail(c, http.StatusInternalServerError, err.Error())
+		responder.Fail(c, err)
 		return
 	}
 	out := make([]gin.H, 0, len(roles))
@@ -111,3 +247,15 @@ func (h *UserHandler) Roles(c *gin.Context) {
 	}
 	responder.OK(c, gin.H{"items": out, "count": len(out)})
 }
+
+// pageRequest reads ?limit=&cursor=&sort= and the filters of a list
+// endpoint; it answers 400 and returns false when they are invalid.
+func pageRequest(c *gin.Context) (pagination.Request, bool) {
+	req, err := pagination.FromQuery(c.Request.URL.Query())
+	if err != nil {
+		responder.Fail(c, err)
+		return req, false
+	}
+	return req, true
+}
+
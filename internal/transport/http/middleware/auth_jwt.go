@@ -1,9 +1,10 @@
 package middleware
 
 import (
-	"net/http"
+	"errors"
 	"strings"
 
+	"lms-backend/internal/apperr"
 	"lms-backend/internal/service"
 	"lms-backend/internal/transport/http/responder"
 
@@ -17,14 +18,18 @@ func AuthJWT(auth *service.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		h := c.GetHeader("Authorization")
 		if h == "" || !strings.HasPrefix(h, "Bearer ") {
-			responder.Fail(c, http.StatusUnauthorized, "missing bearer token")
+			responder.Fail(c, apperr.Unauthorized("missing_token", "missing bearer token"))
 			return
 		}
 		tokenStr := strings.TrimPrefix(h, "Bearer ")
 
-		claims, err := auth.Parse(tokenStr)
+		claims, err := auth.Authenticate(c.Request.Context(), tokenStr)
+		if errors.Is(err, service.ErrAccountDisabled) {
+			responder.Fail(c, err)
+			return
+		}
 		if err != nil {
-			responder.Fail(c, http.StatusUnauthorized, "invalid token")
+			responder.Fail(c, apperr.Unauthorized("invalid_token", "invalid token"))
 			return
 		}
 
@@ -33,3 +38,27 @@ func AuthJWT(auth *service.AuthService) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// TokenFromQuery authenticates EventSource and browser WebSockets, which
+// cannot set headers, with a ticket from ?ticket= (POST /events/ticket)
+// and hands other requests to AuthJWT. URLs end up in reverse proxy access
+// logs, so the query never carries the JWT: a ticket opens one stream
+// within seconds and is worthless once logged.
+func TokenFromQuery(auth *service.AuthService) gin.HandlerFunc {
+	withJWT := AuthJWT(auth)
+	return func(c *gin.Context) {
+		t := c.Query("ticket")
+		if t == "" || c.GetHeader("Authorization") != "" {
+			withJWT(c)
+			return
+		}
+		claims, err := auth.RedeemStreamTicket(c.Request.Context(), t)
+		if err != nil {
+			responder.Fail(c, err)
+			return
+		}
+		c.Set(CtxUserIDKey, claims.UserID)
+		c.Set(CtxRoleKey, claims.Role)
+		c.Next()
+	}
+}
@@ -1,20 +1,19 @@
 package middleware
 
 import (
-	"net/http"
-
 	"lms-backend/internal/transport/http/responder"
 
 	"github.com/gin-gonic/gin"
 )
 
+// ErrorHandler answers errors attached with c.Error by handlers that did not
+// write a response themselves; they are translated like any other error.
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
-		if len(c.Errors) == 0 {
+		if len(c.Errors) == 0 || c.Writer.Written() {
 			return
 		}
-		err := c.Errors[0].Err
-		responder.Fail(c, http.StatusBadRequest, err.Error())
+		responder.Fail(c, c.Errors.Last().Err)
 	}
 }
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConditionalGET answers GET requests with If-None-Match 304 Not Modified
+// when the response has not changed. Handlers may set the ETag, e.g. a row
+// version; successful JSON responses without one get a weak ETag of their
+// body. Other responses (files, streams, errors) pass through untouched.
+func ConditionalGET() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+		w := &bufferingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		if !w.buffering {
+			return
+		}
+
+		etag := w.Header().Get("ETag")
+		if etag == "" {
+			sum := sha256.Sum256(w.body.Bytes())
+			etag = `W/"` + hex.EncodeToString(sum[:16]) + `"`
+			w.Header().Set("ETag", etag)
+		}
+		if noneMatch(c.GetHeader("If-None-Match"), etag) {
+			w.Header().Del("Content-Type")
+			w.ResponseWriter.WriteHeader(http.StatusNotModified)
+			w.ResponseWriter.WriteHeaderNow()
+			return
+		}
+		w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
+
+// noneMatch reports whether an If-None-Match header names etag, comparing
+// weakly as RFC 9110 asks for GET.
+func noneMatch(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferingWriter holds back a 200 JSON body until the handler is done, so
+// it can be hashed and possibly dropped; anything else is written through.
+type bufferingWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	decided   bool
+	buffering bool
+}
+
+func (w *bufferingWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		w.buffering = w.Status() == http.StatusOK &&
+			strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+	}
+	if w.buffering {
+		return w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bufferingWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+
+	"lms-backend/internal/domain/model"
+	"lms-backend/internal/service"
+	"lms-backend/internal/transport/http/responder"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// ReplayedHeader is set on responses replayed for a retried key.
+	ReplayedHeader = "Idempotent-Replayed"
+)
+
+// omittedBody is stored instead of a response larger than
+// service.MaxIdempotentBody.
+var omittedBody = []byte(`{"data":null,"body_omitted":true}`)
+
+// Idempotency makes POST and PATCH requests that carry an Idempotency-Key
+// safe to retry: the first response is stored and sent again for every
+// retry with the same key and payload. It runs after AuthJWT, keys are per
+// user. The body is read into memory to hash it, so it is capped at
+// svc.MaxRequestBody (413 above).
+func Idempotency(svc *service.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || (c.Request.Method != http.MethodPost && c.Request.Method != http.MethodPatch) {
+			c.Next()
+			return
+		}
+		uidAny, _ := c.Get(CtxUserIDKey)
+		uid, _ := uidAny.(int)
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, svc.MaxRequestBody()))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			responder.Fail(c, svc.ErrRequestTooLarge())
+			return
+		}
+		if err != nil {
+			responder.Fail(c, err)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		hash := requestHash(c.Request, body)
+
+		ctx := c.Request.Context()
+		stored, err := svc.Begin(ctx, uid, key, hash)
+		if err != nil {
+			responder.Fail(c, err)
+			return
+		}
+		if stored != nil {
+			c.Header(ReplayedHeader, "true")
+			c.Data(stored.Status, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		w := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		finished := false
+		defer func() {
+			if finished {
+				return
+			}
+			// a panic is on its way to gin.Recovery; free the key for a retry
+			if err := svc.Release(context.WithoutCancel(ctx), uid, key, hash); err != nil {
+				log.Printf("idempotency: release key of user %d: %v", uid, err)
+			}
+		}()
+
+		c.Next()
+
+		resp := model.IdempotentResponse{
+			UserID: uid, Key: key, RequestHash: hash,
+			Status: w.Status(), ContentType: w.Header().Get("Content-Type"), Body: w.body.Bytes(),
+		}
+		if w.overflow {
+			// the request did run: a retry gets its status, just not the body
+			resp.ContentType, resp.Body = gin.MIMEJSON, omittedBody
+		}
+		// stored even if the client went away: that is when it retries
+		if err := svc.Finish(context.WithoutCancel(ctx), resp); err != nil {
+			log.Printf("idempotency: store response for user %d: %v", uid, err)
+		}
+		finished = true
+	}
+}
+
+// requestHash identifies the payload of a request: method, URL and body. The
+// boundary of a multipart body is random per attempt, so it is left out.
+func requestHash(r *http.Request, body []byte) string {
+	if _, params, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil && params["boundary"] != "" {
+		body = bytes.ReplaceAll(body, []byte(params["boundary"]), nil)
+	}
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter keeps a copy of what the handler writes, up to
+// service.MaxIdempotentBody.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.record(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.record([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *recordingWriter) record(b []byte) {
+	if w.overflow || w.body.Len()+len(b) > service.MaxIdempotentBody {
+		w.overflow = true
+		return
+	}
+	w.body.Write(b)
+}
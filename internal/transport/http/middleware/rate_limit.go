@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"lms-backend/internal/apperr"
+	"lms-backend/internal/ratelimit"
+	"lms-backend/internal/transport/http/responder"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errRateLimited = apperr.RateLimited("rate_limited", "too many requests, retry later")
+
+// RateLimit counts requests against the policy of a route group. Requests
+// that passed AuthJWT are counted per user, others per client IP. Every
+// response carries the RateLimit-* headers; when the bucket is empty the
+// request is answered 429 with Retry-After. If the store fails the request
+// goes through.
+func RateLimit(l *ratelimit.Limiter, group string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client := "ip:" + c.ClientIP()
+		if uid, ok := c.Get(CtxUserIDKey); ok {
+			client = fmt.Sprintf("user:%v", uid)
+		}
+
+		res, p, limited, err := l.Take(c.Request.Context(), group, client)
+		if err != nil {
+			log.Printf("ratelimit: %s %s: %v", group, client, err)
+			c.Next()
+			return
+		}
+		if !limited {
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d;burst=%d", p.Limit, seconds(p.Period), res.Limit))
+		c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(seconds(res.Reset)))
+		if !res.Allowed {
+			c.Header("Retry-After", strconv.Itoa(seconds(res.RetryAfter)))
+			responder.Fail(c, errRateLimited)
+			return
+		}
+		c.Next()
+	}
+}
+
+// seconds rounds d up to whole seconds, as the headers want them.
+func seconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
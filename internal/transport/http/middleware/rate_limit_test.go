@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"lms-backend/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestRateLimitIgnoresSpoofedForwardedFor checks that a client cannot get a
+// fresh bucket per request by changing X-Forwarded-For when, as in main, no
+// proxy is trusted.
+func TestRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	if err := r.SetTrustedProxies(nil); err != nil {
+		t.Fatal(err)
+	}
+	limiter := ratelimit.New(ratelimit.NewMemoryStore(), map[string]ratelimit.Policy{
+		"auth": {Limit: 10, Period: time.Minute, Burst: 3},
+	})
+	r.POST("/login", RateLimit(limiter, "auth"), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 4; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/login", nil)
+		req.RemoteAddr = "203.0.113.7:4000"
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if i < 3 {
+			if w.Code != http.StatusOK {
+				t.Fatalf("request %d: status %d, want 200", i, w.Code)
+			}
+			continue
+		}
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("request %d with spoofed X-Forwarded-For: status %d, want 429", i, w.Code)
+		}
+		if w.Header().Get("Retry-After") == "" {
+			t.Fatal("429 without Retry-After")
+		}
+	}
+}
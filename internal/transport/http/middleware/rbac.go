@@ -1,8 +1,7 @@
 package middleware
 
 import (
-	"net/http"
-
+	"lms-backend/internal/apperr"
 	"lms-backend/internal/transport/http/responder"
 
 	"github.com/gin-gonic/gin"
@@ -17,13 +16,13 @@ func RequireRoles(allowed ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		roleAny, ok := c.Get(CtxRoleKey)
 		if !ok {
-			responder.Fail(c, http.StatusForbidden, "no role in context")
+			responder.Fail(c, apperr.Forbidden("forbidden", "no role in context"))
 			return
 		}
 		role, _ := roleAny.(string)
 
 		if _, ok := set[role]; !ok {
-			responder.Fail(c, http.StatusForbidden, "forbidden")
+			responder.Fail(c, apperr.ErrForbidden)
 			return
 		}
 		c.Next()
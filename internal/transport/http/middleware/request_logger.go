@@ -4,6 +4,8 @@ import (
 	"log"
 	"time"
 
+	"lms-backend/internal/transport/http/responder"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -12,6 +14,6 @@ func RequestLogger() gin.HandlerFunc {
 		start := time.Now()
 		c.Next()
 		lat := time.Since(start)
-		log.Printf("%d %s %s ip=%s latency=%s", c.Writer.Status(), c.Request.Method, c.Request.URL.Path, c.ClientIP(), lat)
+		log.Printf("%d %s %s ip=%s latency=%s request_id=%s", c.Writer.Status(), c.Request.Method, c.Request.URL.Path, c.ClientIP(), lat, c.GetString(responder.RequestIDKey))
 	}
 }
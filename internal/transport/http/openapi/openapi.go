@@ -0,0 +1,375 @@
+// Package openapi describes the HTTP API as an OpenAPI 3.1 document. Paths
+// come from the routes registered on the gin engine, request schemas from
+// the dto structs (json, form and binding tags) and list parameters from the
+// pagination specs; what cannot be read from those is given per route in a
+// Registry.
+//
+// The document is generated by the httpapi tests and committed as
+// openapi.json, so clients can use it without running the server.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"lms-backend/internal/pagination"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed openapi.json
+var Spec []byte
+
+//go:embed docs.html
+var docsHTML []byte
+
+// ServeSpec answers GET /api/v1/openapi.json.
+func ServeSpec(c *gin.Context) {
+	c.Data(200, "application/json", Spec)
+}
+
+// ServeDocs answers GET /api/v1/docs with a page that renders the spec and
+// lets the caller try requests with their token.
+func ServeDocs(c *gin.Context) {
+	c.Data(200, "text/html; charset=utf-8", docsHTML)
+}
+
+// Operation documents one route.
+type Operation struct {
+	ID       string // operationId; derived from the handler name when empty
+	Summary  string
+	Tag      string
+	Public   bool             // no bearer token needed
+	Body     any              // JSON body, a dto struct value
+	Optional bool             // Body may be omitted
+	Form     any              // multipart/form-data fields, a dto struct with form tags
+	Files    []Param          // multipart file fields
+	Query    []Param          // query parameters not covered by Page
+	Headers  []Param          // request headers
+	Page     *pagination.Spec // cursor, limit, sort and the spec's filters
+	Status   int              // success status; 200 when 0
+	Produces []string         // content types of a non-JSON success body
+}
+
+// Param is a query parameter, header or multipart file.
+type Param struct {
+	Name     string
+	Type     string // JSON schema type of a query parameter
+	Desc     string
+	Required bool
+}
+
+// Registry maps "METHOD /path", as registered with gin, to its Operation.
+type Registry map[string]Operation
+
+// Info is the document's info object.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type document struct {
+	OpenAPI    string                    `json:"openapi"`
+	Info       Info                      `json:"info"`
+	Security   []map[string][]string     `json:"security"`
+	Tags       []map[string]string       `json:"tags"`
+	Paths      map[string]map[string]any `json:"paths"`
+	Components map[string]any            `json:"components"`
+}
+
+type operation struct {
+	Tags        []string         `json:"tags"`
+	Summary     string           `json:"summary"`
+	OperationID string           `json:"operationId"`
+	Security    any              `json:"security,omitempty"` // [] for public routes
+	Parameters  []map[string]any `json:"parameters,omitempty"`
+	RequestBody map[string]any   `json:"requestBody,omitempty"`
+	Responses   map[string]any   `json:"responses"`
+}
+
+// Build returns the document for routes. Every route needs an Operation and
+// every Operation a route, so the document cannot silently fall behind the
+// router.
+func Build(info Info, routes gin.RoutesInfo, ops Registry) ([]byte, error) {
+	g := &generator{schemas: map[string]any{
+		"Envelope": map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"data": map[string]any{}},
+		},
+		"Error": errorSchema,
+	}}
+	doc := document{
+		OpenAPI:  "3.1.0",
+		Info:     info,
+		Security: []map[string][]string{{"bearer": {}}},
+		Paths:    map[string]map[string]any{},
+	}
+
+	seen := map[string]bool{}
+	ids := map[string]string{}
+	tags := map[string]bool{}
+	var problems []string
+	for _, rt := range routes {
+		key := rt.Method + " " + rt.Path
+		op, ok := ops[key]
+		if !ok {
+			problems = append(problems, "route without an operation: "+key)
+			continue
+		}
+		seen[key] = true
+
+		id := op.ID
+		if id == "" {
+			id = operationID(rt.Handler)
+		}
+		if id == "" {
+			problems = append(problems, "anonymous handler needs an operation ID: "+key)
+			continue
+		}
+		if other, dup := ids[id]; dup {
+			problems = append(problems, fmt.Sprintf("operation ID %s used by %s and %s", id, other, key))
+			continue
+		}
+		ids[id] = key
+		tags[op.Tag] = true
+
+		path, params := pathParams(rt.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]any{}
+		}
+		doc.Paths[path][strings.ToLower(rt.Method)] = g.operation(op, id, params)
+	}
+	for key := range ops {
+		if !seen[key] {
+			problems = append(problems, "operation without a route: "+key)
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("openapi: %s", strings.Join(problems, "; "))
+	}
+
+	names := make([]string, 0, len(tags))
+	for t := range tags {
+		names = append(names, t)
+	}
+	sort.Strings(names)
+	for _, t := range names {
+		doc.Tags = append(doc.Tags, map[string]string{"name": t})
+	}
+	doc.Components = map[string]any{
+		"schemas": g.schemas,
+		"responses": map[string]any{
+			"Error": map[string]any{
+				"description": "Error; see code for the reason",
+				"content":     jsonContent(ref("Error")),
+			},
+		},
+		"securitySchemes": map[string]any{
+			"bearer": map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+		},
+	}
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+var errorSchema = map[string]any{
+	"type":     "object",
+	"required": []string{"error"},
+	"properties": map[string]any{
+		"error": map[string]any{
+			"type":     "object",
+			"required": []string{"code", "message"},
+			"properties": map[string]any{
+				"code":       map[string]any{"type": "string"},
+				"message":    map[string]any{"type": "string"},
+				"fields":     map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+				"request_id": map[string]any{"type": "string"},
+			},
+		},
+		"data": map[string]any{"description": "details of a rejected batch"},
+	},
+}
+
+type generator struct {
+	schemas map[string]any
+}
+
+func (g *generator) operation(op Operation, id string, params []map[string]any) operation {
+	out := operation{
+		Tags:        []string{op.Tag},
+		Summary:     op.Summary,
+		OperationID: id,
+		Parameters:  params,
+	}
+	if op.Public {
+		out.Security = []map[string][]string{}
+	}
+	for _, q := range op.Query {
+		out.Parameters = append(out.Parameters, param(q, "query"))
+	}
+	for _, h := range op.Headers {
+		out.Parameters = append(out.Parameters, param(h, "header"))
+	}
+	if op.Page != nil {
+		out.Parameters = append(out.Parameters, pageParams(op.Page)...)
+	}
+
+	switch {
+	case op.Body != nil:
+		out.RequestBody = map[string]any{
+			"required": !op.Optional,
+			"content":  jsonContent(g.schemaOf(reflect.TypeOf(op.Body))),
+		}
+	case op.Form != nil || len(op.Files) > 0:
+		form := map[string]any{"type": "object", "properties": map[string]any{}}
+		if op.Form != nil {
+			form = g.object(reflect.TypeOf(op.Form), "form")
+		}
+		props := form["properties"].(map[string]any)
+		required, _ := form["required"].([]string)
+		for _, f := range op.Files {
+			file := map[string]any{"type": "string", "contentMediaType": "application/octet-stream"}
+			if f.Desc != "" {
+				file["description"] = f.Desc
+			}
+			props[f.Name] = file
+			if f.Required {
+				required = append(required, f.Name)
+			}
+		}
+		if len(required) > 0 {
+			form["required"] = required
+		}
+		out.RequestBody = map[string]any{
+			"required": true,
+			"content":  map[string]any{"multipart/form-data": map[string]any{"schema": form}},
+		}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = 200
+	}
+	ok := map[string]any{"description": "OK"}
+	if status == 201 {
+		ok["description"] = "Created"
+	}
+	if len(op.Produces) == 0 {
+		ok["content"] = jsonContent(ref("Envelope"))
+	} else {
+		content := map[string]any{}
+		for _, ct := range op.Produces {
+			content[ct] = map[string]any{"schema": map[string]any{"type": "string", "contentMediaType": ct}}
+		}
+		ok["content"] = content
+	}
+	out.Responses = map[string]any{
+		fmt.Sprint(status): ok,
+		"default":          map[string]any{"$ref": "#/components/responses/Error"},
+	}
+	return out
+}
+
+var ginParam = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// pathParams turns "/courses/:id" into "/courses/{id}" and its parameters.
+// Parameters named like ids are integers, any other is a string.
+func pathParams(path string) (string, []map[string]any) {
+	var params []map[string]any
+	for _, m := range ginParam.FindAllStringSubmatch(path, -1) {
+		typ := "string"
+		if strings.HasSuffix(strings.ToLower(m[1]), "id") {
+			typ = "integer"
+		}
+		params = append(params, map[string]any{
+			"name": m[1], "in": "path", "required": true, "schema": map[string]any{"type": typ},
+		})
+	}
+	return ginParam.ReplaceAllString(path, "{$1}"), params
+}
+
+func param(p Param, in string) map[string]any {
+	q := map[string]any{"name": p.Name, "in": in, "schema": map[string]any{"type": p.Type}}
+	if p.Desc != "" {
+		q["description"] = p.Desc
+	}
+	if p.Required {
+		q["required"] = true
+	}
+	return q
+}
+
+func pageParams(s *pagination.Spec) []map[string]any {
+	sorts := make([]string, 0, 2*len(s.Sorts))
+	for name := range s.Sorts {
+		sorts = append(sorts, name, "-"+name)
+	}
+	sort.Strings(sorts)
+	params := []map[string]any{
+		{"name": "cursor", "in": "query", "description": "next_cursor of the previous page", "schema": map[string]any{"type": "string"}},
+		{"name": "limit", "in": "query", "schema": map[string]any{"type": "integer", "minimum": 1, "maximum": pagination.MaxLimit, "default": pagination.DefaultLimit}},
+		{"name": "sort", "in": "query", "description": "field to sort by, - for descending", "schema": map[string]any{"type": "string", "enum": sorts, "default": s.Default}},
+	}
+
+	names := make([]string, 0, len(s.Filters))
+	for name := range s.Filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := s.Filters[name]
+		p := map[string]any{"name": name, "in": "query"}
+		switch f.Kind {
+		case pagination.Int:
+			p["schema"] = map[string]any{"type": "integer"}
+		case pagination.Bool:
+			p["schema"] = map[string]any{"type": "boolean"}
+		case pagination.Time:
+			p["schema"] = map[string]any{"type": "string"}
+			p["description"] = "RFC 3339 or YYYY-MM-DD"
+		case pagination.Date:
+			p["schema"] = map[string]any{"type": "string", "format": "date"}
+		default:
+			p["schema"] = map[string]any{"type": "string"}
+		}
+		if f.Like {
+			p["description"] = "substring, case-insensitive"
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+var handlerName = regexp.MustCompile(`\.(?:\(\*(\w+?)(?:Handler)?\)\.)?(\w+?)(?:-fm)?$`)
+
+// operationID derives "userList" from ".../handlers.(*UserHandler).List-fm"
+// and "serveSpec" from ".../openapi.ServeSpec". Closures have no usable name.
+func operationID(handler string) string {
+	m := handlerName.FindStringSubmatch(handler)
+	if m == nil || strings.HasPrefix(m[2], "func") {
+		return ""
+	}
+	id := []rune(m[1] + m[2])
+	id[0] = unicode.ToLower(id[0])
+	return string(id)
+}
+
+func ref(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
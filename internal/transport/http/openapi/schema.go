@@ -0,0 +1,141 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaOf returns the schema of t; named structs become components and are
+// referenced.
+func (g *generator) schemaOf(t reflect.Type) map[string]any {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Pointer:
+		s := g.schemaOf(t.Elem())
+		if typ, ok := s["type"].(string); ok {
+			s["type"] = []string{typ, "null"}
+			return s
+		}
+		return map[string]any{"anyOf": []any{s, map[string]any{"type": "null"}}}
+	case t.Kind() == reflect.Struct:
+		if _, done := g.schemas[t.Name()]; !done {
+			g.schemas[t.Name()] = nil // breaks cycles
+			g.schemas[t.Name()] = g.object(t, "json")
+		}
+		return ref(t.Name())
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return map[string]any{"type": "array", "items": g.schemaOf(t.Elem())}
+	case t.Kind() == reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schemaOf(t.Elem())}
+	case t.Kind() == reflect.Interface:
+		return map[string]any{} // any JSON value
+	}
+	return map[string]any{"type": jsonType(t.Kind())}
+}
+
+// object is the inline schema of struct t, with property names from tagKey
+// ("json" or "form") and constraints from the binding tags.
+func (g *generator) object(t reflect.Type, tagKey string) map[string]any {
+	props := map[string]any{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get(tagKey), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s := g.schemaOf(f.Type)
+		if bind(s, f.Type, f.Tag.Get("binding")) {
+			required = append(required, name)
+		}
+		props[name] = s
+	}
+	obj := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		obj["required"] = required
+	}
+	return obj
+}
+
+// bind adds the validator rules of a binding tag to s and reports whether
+// the field is required. Rules after "dive" apply to the elements.
+func bind(s map[string]any, t reflect.Type, tag string) (required bool) {
+	if tag == "" {
+		return false
+	}
+	nilable := t.Kind() == reflect.Pointer // required only means non-nil
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	rules := strings.Split(tag, ",")
+	for i, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			if items, ok := s["items"].(map[string]any); ok {
+				bind(items, t.Elem(), strings.Join(rules[i+1:], ","))
+			}
+			return required
+		case "required":
+			required = true
+			switch {
+			case nilable:
+			case t.Kind() == reflect.String:
+				s["minLength"] = 1
+			case jsonType(t.Kind()) == "integer" || jsonType(t.Kind()) == "number":
+				s["not"] = map[string]any{"const": 0}
+			}
+		case "email":
+			s["format"] = "email"
+		case "oneof":
+			s["enum"] = strings.Fields(param)
+		case "min", "gte", "max", "lte":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			s[limitKeyword(t.Kind(), name == "min" || name == "gte")] = n
+		}
+	}
+	return required
+}
+
+func limitKeyword(k reflect.Kind, lower bool) string {
+	switch {
+	case k == reflect.String && lower:
+		return "minLength"
+	case k == reflect.String:
+		return "maxLength"
+	case (k == reflect.Slice || k == reflect.Array) && lower:
+		return "minItems"
+	case k == reflect.Slice || k == reflect.Array:
+		return "maxItems"
+	case lower:
+		return "minimum"
+	default:
+		return "maximum"
+	}
+}
+
+func jsonType(k reflect.Kind) string {
+	switch k {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	}
+	return "object"
+}
@@ -0,0 +1,47 @@
+package httpapi
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"lms-backend/internal/transport/http/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+var update = flag.Bool("update", false, "rewrite openapi/openapi.json from the routes and dtos")
+
+// TestOpenAPIUpToDate fails when a route or dto changed without the spec.
+// Regenerate with: go test ./internal/transport/http -run OpenAPI -update
+func TestOpenAPIUpToDate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// handlers are never called, so the router can be built without them
+	r := NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	got, err := OpenAPI(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		if err := os.WriteFile("openapi/openapi.json", got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	if bytes.Equal(got, openapi.Spec) {
+		return
+	}
+
+	want := strings.Split(string(openapi.Spec), "\n")
+	lines := strings.Split(string(got), "\n")
+	for i := range lines {
+		if i >= len(want) || lines[i] != want[i] {
+			t.Fatalf("openapi/openapi.json is out of date (first difference at line %d: %q); "+
+				"run go test ./internal/transport/http -run OpenAPI -update and commit the result", i+1, lines[i])
+		}
+	}
+	t.Fatal("openapi/openapi.json is out of date; run go test ./internal/transport/http -run OpenAPI -update")
+}
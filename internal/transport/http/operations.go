@@ -0,0 +1,250 @@
+package httpapi
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"lms-backend/internal/repository"
+	"lms-backend/internal/transport/http/dto"
+	"lms-backend/internal/transport/http/middleware"
+	"lms-backend/internal/transport/http/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+var apiInfo = openapi.Info{
+	Title:   "LMS Backend API",
+	Version: "1.0.0",
+	Description: "Successful responses wrap their payload in {\"data\": ...}; failures answer " +
+		"{\"error\": {\"code\", \"message\", \"fields\", \"request_id\"}}.",
+}
+
+// OpenAPI returns the OpenAPI document of the routes registered on r.
+func OpenAPI(r *gin.Engine) ([]byte, error) {
+	return openapi.Build(apiInfo, r.Routes(), withMiddlewareHeaders(operations))
+}
+
+// withMiddlewareHeaders adds the headers the protected group's middleware
+// reads to the operations it serves.
+func withMiddlewareHeaders(ops openapi.Registry) openapi.Registry {
+	out := make(openapi.Registry, len(ops))
+	for key, op := range ops {
+		method, _, _ := strings.Cut(key, " ")
+		if !op.Public && (method == http.MethodPost || method == http.MethodPatch) {
+			op.Headers = append(slices.Clip(op.Headers), idempotencyKey)
+		}
+		out[key] = op
+	}
+	return out
+}
+
+func q(name, typ, desc string) openapi.Param {
+	return openapi.Param{Name: name, Type: typ, Desc: desc}
+}
+
+var (
+	producesPDF      = []string{"application/pdf"}
+	courseIDOpt      = q("course_id", "integer", "only this course")
+	statusFilter     = q("status", "string", "only requests with this status")
+	idempotencyKey   = openapi.Param{Name: middleware.IdempotencyKeyHeader, Type: "string", Desc: "retries with the same key get the first response again"}
+	ifMatch          = []openapi.Param{{Name: "If-Match", Type: "string", Desc: "ETag (version) the change is based on", Required: true}}
+	ifMatchMark      = []openapi.Param{{Name: "If-Match", Type: "string", Desc: "version of the mark being changed; omit to create a new mark"}}
+	streamTicket     = q("ticket", "string", "one-time ticket from POST /events/ticket, for clients that cannot send an Authorization header")
+	transcriptStatus = q("status", "string", "only enrollments with this status, e.g. completed")
+)
+
+// operations documents every route of NewRouter; the tests fail when the two
+// disagree or openapi/openapi.json is out of date.
+var operations = openapi.Registry{
+	"GET /health":              {ID: "health", Tag: "Meta", Summary: "Liveness check", Public: true, Produces: []string{"application/json"}},
+	"GET /api/v1/openapi.json": {Tag: "Meta", Summary: "This document", Public: true, Produces: []string{"application/json"}},
+	"GET /api/v1/docs":         {Tag: "Meta", Summary: "Interactive API documentation", Public: true, Produces: []string{"text/html"}},
+
+	// auth
+	"POST /api/v1/auth/register": {Tag: "Auth", Summary: "Register a student account", Public: true, Body: dto.RegisterReq{}, Status: 201},
+	"POST /api/v1/auth/login":    {Tag: "Auth", Summary: "Exchange email and password for a JWT", Public: true, Body: dto.LoginReq{}},
+	"GET /api/v1/verify/:code":   {Tag: "Certificates", Summary: "Verify a completion certificate", Public: true},
+
+	// profile
+	"GET /api/v1/me":               {Tag: "Profile", Summary: "Current user"},
+	"PATCH /api/v1/me":             {Tag: "Profile", Summary: "Change own name, email or password", Body: dto.UpdateMeReq{}, Headers: ifMatch},
+	"GET /api/v1/me/profile":       {Tag: "Profile", Summary: "Own profile"},
+	"PATCH /api/v1/me/profile":     {Tag: "Profile", Summary: "Change own phone and language", Body: dto.UpdateProfileReq{}},
+	"POST /api/v1/me/avatar":       {Tag: "Profile", Summary: "Upload own avatar (JPEG or PNG)", Files: []openapi.Param{{Name: "avatar", Required: true}}},
+	"DELETE /api/v1/me/avatar":     {Tag: "Profile", Summary: "Remove own avatar"},
+	"GET /api/v1/users/:id/avatar": {Tag: "Profile", Summary: "A user's avatar", Produces: []string{"image/jpeg"}},
+	"GET /api/v1/roles":            {Tag: "Users", Summary: "List roles (admin)"},
+
+	// users
+	"POST /api/v1/users":              {Tag: "Users", Summary: "Create a user (admin)", Body: dto.CreateUserReq{}, Status: 201},
+	"GET /api/v1/users":               {Tag: "Users", Summary: "List users (admin)", Page: repository.UserListSpec},
+	"GET /api/v1/users/:id":           {Tag: "Users", Summary: "Get a user (admin)"},
+	"PATCH /api/v1/users/:id":         {Tag: "Users", Summary: "Edit a user (admin)", Body: dto.UpdateUserReq{}, Headers: ifMatch},
+	"DELETE /api/v1/users/:id":        {Tag: "Users", Summary: "Delete and anonymize a user (admin)"},
+	"PATCH /api/v1/users/:id/role":    {Tag: "Users", Summary: "Change a user's role (admin)", Body: dto.ChangeRoleReq{}, Headers: ifMatch},
+	"GET /api/v1/users/:id/profile":   {Tag: "Profile", Summary: "A user's profile (admin, teacher)"},
+	"PATCH /api/v1/users/:id/profile": {Tag: "Profile", Summary: "Edit a user's profile (admin)", Body: dto.UpdateProfileReq{}},
+	"POST /api/v1/imports/users": {
+		Tag: "Users", Summary: "Import users from CSV or XLSX (admin)",
+		Query: []openapi.Param{
+			q("dry_run", "boolean", "validate only"),
+			q("send_invites", "boolean", "email an invitation with a password link"),
+			q("override", "boolean", "enroll even when course requirements are not met"),
+		},
+		Files: []openapi.Param{{Name: "file", Required: true}},
+	},
+
+	// courses
+	"POST /api/v1/courses":                                    {Tag: "Courses", Summary: "Create a course (admin, teacher)", Body: dto.CreateCourseReq{}, Status: 201},
+	"GET /api/v1/courses":                                     {Tag: "Courses", Summary: "List courses", Page: repository.CourseListSpec},
+	"GET /api/v1/my/courses":                                  {Tag: "Courses", Summary: "Courses taught or taken by the caller", Query: []openapi.Param{q("status", "string", "enrollment status, students only")}, Page: repository.CourseListSpec},
+	"GET /api/v1/my/transcript":                               {Tag: "Courses", Summary: "Own transcript (student)", Query: []openapi.Param{transcriptStatus}},
+	"GET /api/v1/users/:id/transcript":                        {Tag: "Courses", Summary: "A student's transcript (admin, teacher)", Query: []openapi.Param{transcriptStatus}},
+	"GET /api/v1/my/certificates":                             {Tag: "Certificates", Summary: "Own certificates (student)"},
+	"GET /api/v1/my/courses/:id/certificate":                  {Tag: "Certificates", Summary: "Own certificate as PDF (student)", Produces: producesPDF},
+	"GET /api/v1/courses/:id/students/:studentID/certificate": {Tag: "Certificates", Summary: "A student's certificate as PDF (admin, teacher)", Produces: producesPDF},
+	"POST /api/v1/certificates/:code/revoke":                  {Tag: "Certificates", Summary: "Revoke a certificate (admin)", Body: dto.RevokeCertificateReq{}},
+	"POST /api/v1/courses/:id/enroll":                         {Tag: "Courses", Summary: "Enroll a student (admin, teacher)", Body: dto.EnrollReq{}},
+	"POST /api/v1/courses/:id/unenroll":                       {Tag: "Courses", Summary: "Drop a student (admin, teacher)", Body: dto.UnenrollReq{}},
+	"PATCH /api/v1/courses/:id/enrollments/:studentID":        {Tag: "Courses", Summary: "Change an enrollment's status (admin, teacher)", Body: dto.EnrollmentStatusReq{}},
+	"GET /api/v1/courses/:id/students":                        {Tag: "Courses", Summary: "Course roster (admin, teacher)", Query: []openapi.Param{q("status", "string", "enrollment status, default active")}, Page: repository.RosterSpec},
+	"GET /api/v1/courses/:id/available-students":              {Tag: "Courses", Summary: "Students not enrolled yet (admin, teacher)", Page: repository.AvailableStudentsSpec},
+
+	// requirements, timetable and results
+	"GET /api/v1/courses/:id/requirements":                   {Tag: "Requirements", Summary: "List a course's requirements"},
+	"POST /api/v1/courses/:id/requirements":                  {Tag: "Requirements", Summary: "Add a requirement (admin, teacher)", Body: dto.RequirementReq{}, Status: 201},
+	"DELETE /api/v1/courses/:id/requirements/:requirementID": {Tag: "Requirements", Summary: "Remove a requirement (admin, teacher)"},
+	"PUT /api/v1/courses/:id/schedule":                       {Tag: "Requirements", Summary: "Replace a course's weekly timetable (admin, teacher)", Body: dto.ScheduleReq{}},
+	"GET /api/v1/courses/:id/eligibility":                    {Tag: "Requirements", Summary: "Check whether a student may enroll", Query: []openapi.Param{q("student_id", "integer", "staff only; students check themselves")}},
+
+	// enrollment self-service
+	"GET /api/v1/courses/:id/enrollment":                              {Tag: "Enrollment", Summary: "A course's enrollment policy"},
+	"PUT /api/v1/courses/:id/enrollment":                              {Tag: "Enrollment", Summary: "Set the enrollment policy (admin, teacher)", Body: dto.EnrollmentPolicyReq{}},
+	"POST /api/v1/courses/:id/enrollment/join":                        {Tag: "Enrollment", Summary: "Join or ask to join a course (student)", Body: dto.EnrollmentJoinReq{}, Optional: true},
+	"POST /api/v1/courses/:id/enrollment/drop":                        {Tag: "Enrollment", Summary: "Leave a course or withdraw a request (student)"},
+	"GET /api/v1/courses/:id/enrollment/requests":                     {Tag: "Enrollment", Summary: "A course's join requests (admin, teacher)", Query: []openapi.Param{statusFilter}},
+	"POST /api/v1/courses/:id/enrollment/requests/:requestID/approve": {Tag: "Enrollment", Summary: "Approve a join request (admin, teacher)", Body: dto.ReviewEnrollmentReq{}, Optional: true},
+	"POST /api/v1/courses/:id/enrollment/requests/:requestID/reject":  {Tag: "Enrollment", Summary: "Reject a join request (admin, teacher)", Body: dto.ReviewEnrollmentReq{}, Optional: true},
+	"GET /api/v1/my/enrollment-requests":                              {Tag: "Enrollment", Summary: "Own join requests (student)"},
+
+	// course materials
+	"GET /api/v1/courses/:id/materials":                {Tag: "Materials", Summary: "List a course's materials"},
+	"POST /api/v1/courses/:id/materials":               {Tag: "Materials", Summary: "Publish a material (admin, teacher)", Body: dto.MaterialReq{}, Status: 201},
+	"GET /api/v1/courses/:id/materials/:materialID":    {Tag: "Materials", Summary: "A material with its text"},
+	"DELETE /api/v1/courses/:id/materials/:materialID": {Tag: "Materials", Summary: "Remove a material (admin, teacher)"},
+
+	// search
+	"GET /api/v1/search": {
+		Tag: "Search", Summary: "Search users, courses and materials",
+		Query: []openapi.Param{
+			{Name: "q", Type: "string", Desc: "2-200 characters, web-search syntax", Required: true},
+			q("type", "string", "comma-separated: users, courses, materials"),
+			q("limit", "integer", "hits per type, 1-50, default 10"),
+		},
+	},
+
+	// graphql
+	"GET /api/v1/graphql": {
+		ID: "graphqlGet", Tag: "GraphQL", Summary: "Run a GraphQL query given in the query string, e.g. a persisted query by hash",
+		Query: []openapi.Param{
+			q("query", "string", "the query; may be omitted for a persisted query"),
+			q("operationName", "string", "operation to run when the query has several"),
+			q("variables", "string", "JSON object"),
+			q("extensions", "string", `JSON object, e.g. {"persistedQuery":{"version":1,"sha256Hash":"..."}}`),
+		},
+	},
+	"POST /api/v1/graphql": {ID: "graphql", Tag: "GraphQL", Summary: "Run a GraphQL query (see the schema in internal/transport/graphql)", Body: dto.GraphQLReq{}},
+
+	// real-time events
+	"POST /api/v1/events/ticket": {Tag: "Events", Summary: "One-time ticket that opens an event stream within 30 seconds"},
+	"GET /api/v1/events": {
+		Tag: "Events", Summary: "Server-Sent Events of attendance and enrollment changes in courses", Produces: []string{"text/event-stream"},
+		Query: []openapi.Param{
+			{Name: "course_id", Type: "integer", Desc: "course to follow; repeat or comma-separate for several", Required: true},
+			streamTicket,
+		},
+	},
+	"GET /api/v1/ws": {
+		Tag: "Events", Summary: `WebSocket of the same events; send {"action":"subscribe"|"unsubscribe","course_id":1} to change courses`,
+		Query: []openapi.Param{q("course_id", "integer", "courses to follow from the start; repeat or comma-separate"), streamTicket},
+	},
+
+	// student groups
+	"GET /api/v1/groups":                           {Tag: "Groups", Summary: "List groups (admin, teacher)"},
+	"POST /api/v1/groups":                          {Tag: "Groups", Summary: "Create a group (admin)", Body: dto.GroupReq{}, Status: 201},
+	"GET /api/v1/groups/:id":                       {Tag: "Groups", Summary: "A group with its members (admin, teacher)"},
+	"PATCH /api/v1/groups/:id":                     {Tag: "Groups", Summary: "Edit a group (admin)", Body: dto.GroupReq{}},
+	"DELETE /api/v1/groups/:id":                    {Tag: "Groups", Summary: "Delete a group (admin)"},
+	"POST /api/v1/groups/:id/members":              {Tag: "Groups", Summary: "Add students to a group (admin)", Body: dto.GroupMembersReq{}},
+	"DELETE /api/v1/groups/:id/members/:studentID": {Tag: "Groups", Summary: "Remove a student from a group (admin)"},
+	"GET /api/v1/courses/:id/groups":               {Tag: "Groups", Summary: "Groups enrolled in a course (admin, teacher)"},
+	"POST /api/v1/courses/:id/groups":              {Tag: "Groups", Summary: "Enroll a whole group (admin, teacher)", Body: dto.EnrollGroupReq{}},
+	"DELETE /api/v1/courses/:id/groups/:groupID":   {Tag: "Groups", Summary: "Unenroll a group (admin, teacher)"},
+
+	// attendance
+	"POST /api/v1/courses/:id/attendance":                   {Tag: "Attendance", Summary: "Mark one student (admin, teacher)", Body: dto.MarkAttendanceReq{}, Headers: ifMatchMark},
+	"POST /api/v1/courses/:id/attendance/bulk":              {Tag: "Attendance", Summary: "Roll call for a whole lesson (admin, teacher)", Body: dto.MarkLessonReq{}},
+	"GET /api/v1/courses/:id/attendance":                    {Tag: "Attendance", Summary: "A course's attendance records (admin, teacher)", Page: repository.AttendanceListSpec},
+	"GET /api/v1/my/attendance":                             {Tag: "Attendance", Summary: "Own attendance records", Page: repository.AttendanceListSpec},
+	"GET /api/v1/courses/:id/attendance/:studentID/history": {Tag: "Attendance", Summary: "Change history of a student's records"},
+
+	// self check-in
+	"POST /api/v1/courses/:id/checkin/open":  {Tag: "Check-in", Summary: "Open a check-in window (admin, teacher)", Body: dto.OpenCheckinReq{}, Optional: true, Status: 201},
+	"GET /api/v1/courses/:id/checkin":        {Tag: "Check-in", Summary: "The open window and its rotating code (admin, teacher)"},
+	"GET /api/v1/courses/:id/checkin/qr.png": {Tag: "Check-in", Summary: "QR code of the current code (admin, teacher)", Query: []openapi.Param{q("size", "integer", "pixels")}, Produces: []string{"image/png"}},
+	"POST /api/v1/courses/:id/checkin/close": {Tag: "Check-in", Summary: "Close the window and mark absentees (admin, teacher)"},
+	"POST /api/v1/courses/:id/checkin":       {Tag: "Check-in", Summary: "Check in with the current code (student)", Body: dto.CheckinReq{}},
+	"GET /api/v1/courses/:id/checkin/policy": {Tag: "Check-in", Summary: "A course's check-in policy (admin, teacher)"},
+	"PUT /api/v1/courses/:id/checkin/policy": {Tag: "Check-in", Summary: "Set the check-in policy (admin, teacher)", Body: dto.CheckinPolicyReq{}},
+	"GET /api/v1/courses/:id/checkin/fraud":  {Tag: "Check-in", Summary: "Devices that checked in several students (admin, teacher)"},
+	"GET /api/v1/rooms":                      {Tag: "Check-in", Summary: "List rooms (admin, teacher)"},
+	"POST /api/v1/rooms":                     {Tag: "Check-in", Summary: "Create a room (admin)", Body: dto.CreateRoomReq{}, Status: 201},
+	"GET /api/v1/my/device":                  {Tag: "Check-in", Summary: "Own registered device (student)"},
+	"POST /api/v1/my/device":                 {Tag: "Check-in", Summary: "Register own device (student)", Body: dto.RegisterDeviceReq{}, Status: 201},
+	"DELETE /api/v1/users/:id/device":        {Tag: "Check-in", Summary: "Reset a student's device (admin)"},
+
+	// attendance corrections
+	"POST /api/v1/courses/:id/attendance/corrections":                       {Tag: "Corrections", Summary: "Ask for a correction (student)", Body: dto.RequestCorrectionReq{}, Status: 201},
+	"GET /api/v1/courses/:id/attendance/corrections":                        {Tag: "Corrections", Summary: "A course's correction requests (admin, teacher)", Query: []openapi.Param{statusFilter}},
+	"POST /api/v1/courses/:id/attendance/corrections/:correctionID/approve": {Tag: "Corrections", Summary: "Apply a correction (admin, teacher)", Body: dto.ReviewCorrectionReq{}, Optional: true},
+	"POST /api/v1/courses/:id/attendance/corrections/:correctionID/reject":  {Tag: "Corrections", Summary: "Reject a correction (admin, teacher)", Body: dto.ReviewCorrectionReq{}, Optional: true},
+	"GET /api/v1/my/attendance/corrections":                                 {Tag: "Corrections", Summary: "Own correction requests (student)", Query: []openapi.Param{courseIDOpt}},
+
+	// attendance statuses and reports
+	"GET /api/v1/attendance/statuses":            {Tag: "Attendance", Summary: "List attendance statuses"},
+	"POST /api/v1/attendance/statuses":           {Tag: "Attendance", Summary: "Add a status (admin)", Body: dto.CreateAttendanceStatusReq{}, Status: 201},
+	"DELETE /api/v1/attendance/statuses/:code":   {Tag: "Attendance", Summary: "Remove an unused status (admin)"},
+	"GET /api/v1/courses/:id/attendance/summary": {Tag: "Attendance", Summary: "Per-student totals of a course (admin, teacher)"},
+	"GET /api/v1/my/attendance/summary":          {Tag: "Attendance", Summary: "Own totals per course", Query: []openapi.Param{courseIDOpt}},
+	"GET /api/v1/courses/:id/attendance/export": {
+		Tag: "Attendance", Summary: "Attendance register as a file (admin, teacher)",
+		Query: []openapi.Param{
+			q("format", "string", "csv (default), xlsx or pdf"),
+			q("from", "string", "YYYY-MM-DD"),
+			q("to", "string", "YYYY-MM-DD"),
+		},
+		Produces: []string{"text/csv", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "application/pdf"},
+	},
+
+	// analytics and at-risk alerts
+	"GET /api/v1/courses/:id/attendance/analytics": {Tag: "Analytics", Summary: "Attendance trends of a course (admin, teacher)"},
+	"GET /api/v1/risk/rules":                       {Tag: "Analytics", Summary: "List at-risk rules (admin, teacher)"},
+	"POST /api/v1/risk/rules":                      {Tag: "Analytics", Summary: "Add an at-risk rule (admin)", Body: dto.CreateRiskRuleReq{}, Status: 201},
+	"DELETE /api/v1/risk/rules/:id":                {Tag: "Analytics", Summary: "Remove an at-risk rule (admin)"},
+	"POST /api/v1/risk/evaluate":                   {Tag: "Analytics", Summary: "Evaluate the rules now (admin)", Query: []openapi.Param{courseIDOpt}},
+	"GET /api/v1/risk/alerts":                      {Tag: "Analytics", Summary: "List at-risk alerts (admin, teacher)", Query: []openapi.Param{courseIDOpt, q("include_resolved", "boolean", "")}},
+	"POST /api/v1/risk/alerts/:id/ack":             {Tag: "Analytics", Summary: "Acknowledge an alert (admin, teacher)"},
+	"PUT /api/v1/users/:id/advisor":                {Tag: "Analytics", Summary: "Set a student's advisor (admin)", Body: dto.SetAdvisorReq{}},
+
+	// excused absences
+	"POST /api/v1/my/excuses": {
+		Tag: "Excuses", Summary: "Submit an excuse (student)",
+		Form: dto.SubmitExcuseReq{}, Files: []openapi.Param{{Name: "document", Desc: "PDF, JPEG or PNG"}}, Status: 201,
+	},
+	"GET /api/v1/my/excuses":           {Tag: "Excuses", Summary: "Own excuses (student)"},
+	"GET /api/v1/excuses":              {Tag: "Excuses", Summary: "List excuses (admin, teacher)", Query: []openapi.Param{courseIDOpt, statusFilter}},
+	"GET /api/v1/excuses/:id/document": {Tag: "Excuses", Summary: "The attached document", Produces: []string{"application/pdf", "image/jpeg", "image/png"}},
+	"POST /api/v1/excuses/:id/approve": {Tag: "Excuses", Summary: "Approve and excuse the absences (admin, teacher)", Body: dto.ReviewExcuseReq{}, Optional: true},
+	"POST /api/v1/excuses/:id/reject":  {Tag: "Excuses", Summary: "Reject an excuse (admin, teacher)", Body: dto.ReviewExcuseReq{}, Optional: true},
+}
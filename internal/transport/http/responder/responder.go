@@ -1,9 +1,46 @@
 package responder
 
-import "github.com/gin-gonic/gin"
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
 
+	"lms-backend/internal/apperr"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+)
+
+// RequestIDKey is where middleware.RequestID stores the id of the request.
+const RequestIDKey = "request_id"
+
+// APIError is the body of every failed response:
+// {"error":{"code","message","fields","request_id"}}.
 type APIError struct {
-	Message string `json:"message"`
+	Code      string            `json:"code"`
+	Message   string            `json:"message"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+}
+
+var kindStatus = map[apperr.Kind]int{
+	apperr.KindInvalid:              http.StatusBadRequest,
+	apperr.KindUnauthorized:         http.StatusUnauthorized,
+	apperr.KindForbidden:            http.StatusForbidden,
+	apperr.KindNotFound:             http.StatusNotFound,
+	apperr.KindConflict:             http.StatusConflict,
+	apperr.KindUnprocessable:        http.StatusUnprocessableEntity,
+	apperr.KindPrecondition:         http.StatusPreconditionFailed,
+	apperr.KindPreconditionRequired: http.StatusPreconditionRequired,
+	apperr.KindRateLimited:          http.StatusTooManyRequests,
+	apperr.KindTooLarge:             http.StatusRequestEntityTooLarge,
 }
 
 func OK(c *gin.Context, data any) {
@@ -14,6 +51,123 @@ func Created(c *gin.Context, data any) {
 	c.JSON(201, gin.H{"data": data})
 }
 
-func Fail(c *gin.Context, code int, msg string) {
-	c.AbortWithStatusJSON(code, gin.H{"error": APIError{Message: msg}})
+// Fail aborts with err translated into a status and an APIError. Errors that
+// are not an *apperr.Error are logged and answered with a generic 500, so
+// database and I/O details never reach the client.
+func Fail(c *gin.Context, err error) {
+	status, body := translate(c, err)
+	c.AbortWithStatusJSON(status, gin.H{"error": body})
+}
+
+// FailWithData aborts like Fail and still returns a data payload, e.g.
+// per-row validation results of a rejected batch.
+func FailWithData(c *gin.Context, err error, data any) {
+	status, body := translate(c, err)
+	c.AbortWithStatusJSON(status, gin.H{"error": body, "data": data})
+}
+
+// FailBinding answers 400 for an error of c.ShouldBind*, naming the invalid
+// fields.
+func FailBinding(c *gin.Context, err error) {
+	Fail(c, bindingError(err))
+}
+
+func translate(c *gin.Context, err error) (int, APIError) {
+	body := APIError{RequestID: c.GetString(RequestIDKey)}
+	if e, ok := apperr.As(err); ok {
+		if status, known := kindStatus[e.Kind]; known {
+			// a wrapping error may add context to the message, e.g. a row number
+			body.Code, body.Message, body.Fields = e.Code, err.Error(), e.Fields
+			return status, body
+		}
+	}
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, os.ErrNotExist) {
+		// a lookup the service did not translate
+		body.Code, body.Message = "not_found", "not found"
+		return http.StatusNotFound, body
+	}
+
+	log.Printf("internal error request_id=%s %s %s: %v", body.RequestID, c.Request.Method, c.Request.URL.Path, err)
+	body.Code, body.Message = "internal", "internal server error"
+	return http.StatusInternalServerError, body
+}
+
+// UseJSONFieldNames makes binding errors name fields by their json tag, as
+// clients know them.
+func UseJSONFieldNames() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			return f.Name
+		}
+		return name
+	})
+}
+
+func bindingError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return apperr.Field(typeErr.Field, "must be "+jsonType(typeErr.Type.Kind().String()))
+	}
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		e := &apperr.Error{Kind: apperr.KindInvalid, Code: "validation_failed", Fields: map[string]string{}}
+		msgs := make([]string, 0, len(verrs))
+		for _, fe := range verrs {
+			name := fieldPath(fe.Namespace())
+			e.Fields[name] = ruleMessage(fe)
+			msgs = append(msgs, name+" "+e.Fields[name])
+		}
+		e.Message = strings.Join(msgs, "; ")
+		return e
+	}
+	if errors.Is(err, io.EOF) {
+		return apperr.New(apperr.KindInvalid, "invalid_body", "request body is empty")
+	}
+	// JSON syntax and time format errors describe the input, not the server
+	return apperr.New(apperr.KindInvalid, "invalid_body", "invalid request body: "+err.Error())
+}
+
+// fieldPath drops the struct name from a namespace like "EnrollReq.student_id".
+func fieldPath(ns string) string {
+	if i := strings.IndexByte(ns, '.'); i >= 0 {
+		return ns[i+1:]
+	}
+	return ns
+}
+
+func ruleMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be an email address"
+	case "min", "gte":
+		return "must be at least " + fe.Param()
+	case "max", "lte":
+		return "must be at most " + fe.Param()
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", "|")
+	default:
+		return "is invalid (" + fe.Tag() + ")"
+	}
+}
+
+func jsonType(goKind string) string {
+	switch {
+	case strings.HasPrefix(goKind, "int"), strings.HasPrefix(goKind, "uint"), strings.HasPrefix(goKind, "float"):
+		return "a number"
+	case goKind == "bool":
+		return "true or false"
+	case goKind == "string":
+		return "a string"
+	case goKind == "slice", goKind == "array":
+		return "an array"
+	default:
+		return "an object"
+	}
 }
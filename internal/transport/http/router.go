@@ -1,9 +1,12 @@
 package httpapi
 
 import (
+	"lms-backend/internal/ratelimit"
 	"lms-backend/internal/service"
 	"lms-backend/internal/transport/http/handlers"
 	"lms-backend/internal/transport/http/middleware"
+	"lms-backend/internal/transport/http/openapi"
+	"lms-backend/internal/transport/http/responder"
 
 	"github.com/gin-contrib/cors"
 
@@ -12,18 +15,37 @@ import (
 
 func NewRouter(
 	authSvc *service.AuthService,
+	idemSvc *service.IdempotencyService,
+	limiter *ratelimit.Limiter,
 	authH *handlers.AuthHandler,
 	userH *handlers.UserHandler,
 	courseH *handlers.CourseHandler,
 	attH *handlers.AttendanceHandler,
+	excuseH *handlers.ExcuseHandler,
+	checkinH *handlers.CheckinHandler,
+	analyticsH *handlers.AnalyticsHandler,
+	reportH *handlers.ReportHandler,
+	importH *handlers.ImportHandler,
+	profileH *handlers.ProfileHandler,
+	groupH *handlers.GroupHandler,
+	enrollH *handlers.EnrollmentHandler,
+	reqH *handlers.RequirementHandler,
+	certH *handlers.CertificateHandler,
+	materialH *handlers.MaterialHandler,
+	searchH *handlers.SearchHandler,
+	graphqlH *handlers.GraphQLHandler,
+	eventH *handlers.EventHandler,
 ) *gin.Engine {
+	responder.UseJSONFieldNames()
+
 	r := gin.New()
-	r.Use(middleware.RequestLogger(), gin.Recovery(), middleware.ErrorHandler())
+	r.Use(middleware.RequestID(), middleware.RequestLogger(), gin.Recovery(), middleware.ErrorHandler())
 
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Request-ID", middleware.IdempotencyKeyHeader, "If-Match", "If-None-Match"},
+		ExposeHeaders:    []string{"X-Request-ID", middleware.ReplayedHeader, "ETag", "Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy"},
 		AllowCredentials: true,
 	}))
 
@@ -31,36 +53,159 @@ func NewRouter(
 
 	api := r.Group("/api/v1")
 
-	// public
-	api.POST("/auth/register", authH.Register) // creates student
-	api.POST("/auth/login", authH.Login)
+	// public, limited per IP
+	auth := api.Group("/auth", middleware.RateLimit(limiter, "auth"))
+	auth.POST("/register", authH.Register) // creates student
+	auth.POST("/login", authH.Login)
+	public := api.Group("/", middleware.RateLimit(limiter, "public"))
+	public.GET("/verify/:code", certH.Verify) // certificate check for employers
+	public.GET("/openapi.json", openapi.ServeSpec)
+	public.GET("/docs", openapi.ServeDocs)
+
+	// live course events
+	stream := api.Group("/", middleware.TokenFromQuery(authSvc), middleware.RateLimit(limiter, "api"), middleware.RequireRoles("admin", "teacher", "student"))
+	stream.GET("/events", eventH.Stream)
+	stream.GET("/ws", eventH.Socket)
 
 	// protected
 	protected := api.Group("/")
-	protected.Use(middleware.AuthJWT(authSvc))
+	protected.Use(middleware.AuthJWT(authSvc), middleware.RateLimit(limiter, "api"), middleware.Idempotency(idemSvc), middleware.ConditionalGET())
 	{
 		// profile
 		protected.GET("/me", userH.Me)
+		protected.PATCH("/me", userH.UpdateMe)
+		protected.GET("/me/profile", profileH.Mine)
+		protected.PATCH("/me/profile", profileH.UpdateMine)
+		protected.POST("/me/avatar", profileH.UploadAvatar)
+		protected.DELETE("/me/avatar", profileH.DeleteAvatar)
+		protected.GET("/users/:id/avatar", profileH.Avatar)
 		protected.GET("/roles", middleware.RequireRoles("admin"), userH.Roles)
+		protected.POST("/events/ticket", middleware.RequireRoles("admin", "teacher", "student"), authH.StreamTicket)
 
 		// users (admin)
 		protected.POST("/users", middleware.RequireRoles("admin"), userH.Create)
 		protected.GET("/users", middleware.RequireRoles("admin"), userH.List)
+		protected.GET("/users/:id", middleware.RequireRoles("admin"), userH.Get)
+		protected.PATCH("/users/:id", middleware.RequireRoles("admin"), userH.Update)
+		protected.DELETE("/users/:id", middleware.RequireRoles("admin"), userH.Delete)
 		protected.PATCH("/users/:id/role", middleware.RequireRoles("admin"), userH.ChangeRole)
+		protected.GET("/users/:id/profile", middleware.RequireRoles("admin", "teacher"), profileH.Get)
+		protected.PATCH("/users/:id/profile", middleware.RequireRoles("admin"), profileH.Update)
+		protected.POST("/imports/users", middleware.RequireRoles("admin"), importH.Users)
 
 		// courses
 		protected.POST("/courses", middleware.RequireRoles("admin", "teacher"), courseH.Create)
 		protected.GET("/courses", middleware.RequireRoles("admin", "teacher", "student"), courseH.List)
 		protected.GET("/my/courses", middleware.RequireRoles("admin", "teacher", "student"), courseH.MyCourses)
+		protected.GET("/my/transcript", middleware.RequireRoles("student"), courseH.MyTranscript)
+		protected.GET("/users/:id/transcript", middleware.RequireRoles("admin", "teacher"), courseH.Transcript)
+		protected.GET("/my/certificates", middleware.RequireRoles("student"), certH.Mine)
+		protected.GET("/my/courses/:id/certificate", middleware.RequireRoles("student"), certH.MyPDF)
+		protected.GET("/courses/:id/students/:studentID/certificate", middleware.RequireRoles("admin", "teacher"), certH.StudentPDF)
+		protected.POST("/certificates/:code/revoke", middleware.RequireRoles("admin"), certH.Revoke)
 		protected.POST("/courses/:id/enroll", middleware.RequireRoles("admin", "teacher"), courseH.Enroll)
+		protected.POST("/courses/:id/unenroll", middleware.RequireRoles("admin", "teacher"), courseH.Unenroll)
+		protected.PATCH("/courses/:id/enrollments/:studentID", middleware.RequireRoles("admin", "teacher"), courseH.SetEnrollmentStatus)
+
+		// requirements, timetable and results
+		protected.GET("/courses/:id/requirements", middleware.RequireRoles("admin", "teacher", "student"), reqH.List)
+		protected.POST("/courses/:id/requirements", middleware.RequireRoles("admin", "teacher"), reqH.Add)
+		protected.DELETE("/courses/:id/requirements/:requirementID", middleware.RequireRoles("admin", "teacher"), reqH.Delete)
+		protected.PUT("/courses/:id/schedule", middleware.RequireRoles("admin", "teacher"), reqH.SetSchedule)
+		protected.GET("/courses/:id/eligibility", middleware.RequireRoles("admin", "teacher", "student"), reqH.Eligibility)
+
+		// enrollment self-service
+		protected.GET("/courses/:id/enrollment", middleware.RequireRoles("admin", "teacher", "student"), enrollH.GetPolicy)
+		protected.PUT("/courses/:id/enrollment", middleware.RequireRoles("admin", "teacher"), enrollH.SavePolicy)
+		protected.POST("/courses/:id/enrollment/join", middleware.RequireRoles("student"), enrollH.Join)
+		protected.POST("/courses/:id/enrollment/drop", middleware.RequireRoles("student"), enrollH.Drop)
+		protected.GET("/courses/:id/enrollment/requests", middleware.RequireRoles("admin", "teacher"), enrollH.ListRequests)
+		protected.POST("/courses/:id/enrollment/requests/:requestID/approve", middleware.RequireRoles("admin", "teacher"), enrollH.Approve)
+		protected.POST("/courses/:id/enrollment/requests/:requestID/reject", middleware.RequireRoles("admin", "teacher"), enrollH.Reject)
+		protected.GET("/my/enrollment-requests", middleware.RequireRoles("student"), enrollH.MyRequests)
+
+		// course materials
+		protected.GET("/courses/:id/materials", middleware.RequireRoles("admin", "teacher", "student"), materialH.List)
+		protected.POST("/courses/:id/materials", middleware.RequireRoles("admin", "teacher"), materialH.Create)
+		protected.GET("/courses/:id/materials/:materialID", middleware.RequireRoles("admin", "teacher", "student"), materialH.Get)
+		protected.DELETE("/courses/:id/materials/:materialID", middleware.RequireRoles("admin", "teacher"), materialH.Delete)
+
+		// search
+		protected.GET("/search", middleware.RequireRoles("admin", "teacher", "student"), searchH.Search)
+
+		// graphql (dashboard)
+		protected.GET("/graphql", middleware.RequireRoles("admin", "teacher", "student"), graphqlH.Query)
+		protected.POST("/graphql", middleware.RequireRoles("admin", "teacher", "student"), graphqlH.Query)
 
 		protected.GET("/courses/:id/students", middleware.RequireRoles("admin", "teacher"), courseH.GetStudents)
 		protected.GET("/courses/:id/available-students", middleware.RequireRoles("admin", "teacher"), courseH.GetAvailableStudents)
 
+		// student groups
+		protected.GET("/groups", middleware.RequireRoles("admin", "teacher"), groupH.List)
+		protected.POST("/groups", middleware.RequireRoles("admin"), groupH.Create)
+		protected.GET("/groups/:id", middleware.RequireRoles("admin", "teacher"), groupH.Get)
+		protected.PATCH("/groups/:id", middleware.RequireRoles("admin"), groupH.Update)
+		protected.DELETE("/groups/:id", middleware.RequireRoles("admin"), groupH.Delete)
+		protected.POST("/groups/:id/members", middleware.RequireRoles("admin"), groupH.AddMembers)
+		protected.DELETE("/groups/:id/members/:studentID", middleware.RequireRoles("admin"), groupH.RemoveMember)
+		protected.GET("/courses/:id/groups", middleware.RequireRoles("admin", "teacher"), groupH.CourseGroups)
+		protected.POST("/courses/:id/groups", middleware.RequireRoles("admin", "teacher"), groupH.EnrollGroup)
+		protected.DELETE("/courses/:id/groups/:groupID", middleware.RequireRoles("admin", "teacher"), groupH.UnenrollGroup)
+
 		// attendance
 		protected.POST("/courses/:id/attendance", middleware.RequireRoles("admin", "teacher"), attH.Mark)
+		protected.POST("/courses/:id/attendance/bulk", middleware.RequireRoles("admin", "teacher"), attH.MarkLesson)
 		protected.GET("/courses/:id/attendance", middleware.RequireRoles("admin", "teacher"), attH.ListByCourse)
 		protected.GET("/my/attendance", middleware.RequireRoles("admin", "teacher", "student"), attH.MyAttendance)
+		protected.GET("/courses/:id/attendance/:studentID/history", middleware.RequireRoles("admin", "teacher", "student"), attH.History)
+
+		// self check-in
+		protected.POST("/courses/:id/checkin/open", middleware.RequireRoles("admin", "teacher"), checkinH.Open)
+		protected.GET("/courses/:id/checkin", middleware.RequireRoles("admin", "teacher"), checkinH.Current)
+		protected.GET("/courses/:id/checkin/qr.png", middleware.RequireRoles("admin", "teacher"), checkinH.QR)
+		protected.POST("/courses/:id/checkin/close", middleware.RequireRoles("admin", "teacher"), checkinH.Close)
+		protected.POST("/courses/:id/checkin", middleware.RequireRoles("student"), checkinH.CheckIn)
+		protected.GET("/courses/:id/checkin/policy", middleware.RequireRoles("admin", "teacher"), checkinH.GetPolicy)
+		protected.PUT("/courses/:id/checkin/policy", middleware.RequireRoles("admin", "teacher"), checkinH.SavePolicy)
+		protected.GET("/courses/:id/checkin/fraud", middleware.RequireRoles("admin", "teacher"), checkinH.FraudReport)
+		protected.GET("/rooms", middleware.RequireRoles("admin", "teacher"), checkinH.ListRooms)
+		protected.POST("/rooms", middleware.RequireRoles("admin"), checkinH.CreateRoom)
+		protected.GET("/my/device", middleware.RequireRoles("student"), checkinH.MyDevice)
+		protected.POST("/my/device", middleware.RequireRoles("student"), checkinH.RegisterDevice)
+		protected.DELETE("/users/:id/device", middleware.RequireRoles("admin"), checkinH.ResetDevice)
+
+		// attendance corrections
+		protected.POST("/courses/:id/attendance/corrections", middleware.RequireRoles("student"), attH.RequestCorrection)
+		protected.GET("/courses/:id/attendance/corrections", middleware.RequireRoles("admin", "teacher"), attH.ListCorrections)
+		protected.POST("/courses/:id/attendance/corrections/:correctionID/approve", middleware.RequireRoles("admin", "teacher"), attH.ApproveCorrection)
+		protected.POST("/courses/:id/attendance/corrections/:correctionID/reject", middleware.RequireRoles("admin", "teacher"), attH.RejectCorrection)
+		protected.GET("/my/attendance/corrections", middleware.RequireRoles("student"), attH.MyCorrections)
+
+		// attendance statuses and reports
+		protected.GET("/attendance/statuses", middleware.RequireRoles("admin", "teacher", "student"), attH.ListStatuses)
+		protected.POST("/attendance/statuses", middleware.RequireRoles("admin"), attH.CreateStatus)
+		protected.DELETE("/attendance/statuses/:code", middleware.RequireRoles("admin"), attH.DeleteStatus)
+		protected.GET("/courses/:id/attendance/summary", middleware.RequireRoles("admin", "teacher"), attH.CourseSummary)
+		protected.GET("/my/attendance/summary", middleware.RequireRoles("admin", "teacher", "student"), attH.MySummary)
+		protected.GET("/courses/:id/attendance/export", middleware.RequireRoles("admin", "teacher"), reportH.ExportAttendance)
+
+		// analytics and at-risk alerts
+		protected.GET("/courses/:id/attendance/analytics", middleware.RequireRoles("admin", "teacher"), analyticsH.Course)
+		protected.GET("/risk/rules", middleware.RequireRoles("admin", "teacher"), analyticsH.ListRules)
+		protected.POST("/risk/rules", middleware.RequireRoles("admin"), analyticsH.CreateRule)
+		protected.DELETE("/risk/rules/:id", middleware.RequireRoles("admin"), analyticsH.DeleteRule)
+		protected.POST("/risk/evaluate", middleware.RequireRoles("admin"), analyticsH.Evaluate)
+		protected.GET("/risk/alerts", middleware.RequireRoles("admin", "teacher"), analyticsH.ListAlerts)
+		protected.POST("/risk/alerts/:id/ack", middleware.RequireRoles("admin", "teacher"), analyticsH.Acknowledge)
+		protected.PUT("/users/:id/advisor", middleware.RequireRoles("admin"), analyticsH.SetAdvisor)
+
+		// excused absences
+		protected.POST("/my/excuses", middleware.RequireRoles("student"), excuseH.Submit)
+		protected.GET("/my/excuses", middleware.RequireRoles("student"), excuseH.MyExcuses)
+		protected.GET("/excuses", middleware.RequireRoles("admin", "teacher"), excuseH.List)
+		protected.GET("/excuses/:id/document", middleware.RequireRoles("admin", "teacher", "student"), excuseH.Document)
+		protected.POST("/excuses/:id/approve", middleware.RequireRoles("admin", "teacher"), excuseH.Approve)
+		protected.POST("/excuses/:id/reject", middleware.RequireRoles("admin", "teacher"), excuseH.Reject)
 
 	}
 
@@ -0,0 +1,37 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS attendance_history (
+  id            SERIAL PRIMARY KEY,
+  attendance_id INT NOT NULL REFERENCES attendance(id) ON DELETE CASCADE,
+  old_status    TEXT,
+  old_note      TEXT,
+  new_status    TEXT NOT NULL,
+  new_note      TEXT,
+  changed_by    INT REFERENCES users(id) ON DELETE SET NULL,
+  changed_at    TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_history_attendance ON attendance_history(attendance_id, changed_at);
+
+CREATE TABLE IF NOT EXISTS attendance_corrections (
+  id               SERIAL PRIMARY KEY,
+  course_id        INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+  student_id       INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  lesson_date      DATE NOT NULL,
+  requested_status TEXT NOT NULL,
+  reason           TEXT NOT NULL,
+  status           TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','approved','rejected')),
+  reviewed_by      INT REFERENCES users(id) ON DELETE SET NULL,
+  review_comment   TEXT,
+  created_at       TIMESTAMP NOT NULL DEFAULT now(),
+  reviewed_at      TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_corrections_course ON attendance_corrections(course_id, status);
+
+-- one open request per student and lesson
+CREATE UNIQUE INDEX IF NOT EXISTS uq_attendance_corrections_pending
+  ON attendance_corrections(course_id, student_id, lesson_date) WHERE status = 'pending';
+
+-- +goose Down
+DROP TABLE IF EXISTS attendance_corrections;
+DROP TABLE IF EXISTS attendance_history;
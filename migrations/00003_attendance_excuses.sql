@@ -0,0 +1,48 @@
+-- +goose Up
+-- Attendance statuses are data, not a CHECK constraint, so an institution can
+-- add its own (e.g. "sick", "field_trip"). counts_as tells reports how to treat them.
+CREATE TABLE IF NOT EXISTS attendance_statuses (
+  code      TEXT PRIMARY KEY,
+  label     TEXT NOT NULL,
+  counts_as TEXT NOT NULL CHECK (counts_as IN ('present','absent','excused')),
+  builtin   BOOLEAN NOT NULL DEFAULT false
+);
+
+INSERT INTO attendance_statuses(code, label, counts_as, builtin) VALUES
+  ('present', 'Present', 'present', true),
+  ('late',    'Late',    'present', true),
+  ('absent',  'Absent',  'absent',  true),
+  ('excused', 'Excused', 'excused', true)
+ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE attendance DROP CONSTRAINT IF EXISTS attendance_status_check;
+ALTER TABLE attendance ADD CONSTRAINT attendance_status_fkey
+  FOREIGN KEY (status) REFERENCES attendance_statuses(code);
+
+CREATE TABLE IF NOT EXISTS attendance_excuses (
+  id             SERIAL PRIMARY KEY,
+  student_id     INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  course_id      INT REFERENCES courses(id) ON DELETE CASCADE, -- NULL = all courses
+  date_from      DATE NOT NULL,
+  date_to        DATE NOT NULL,
+  reason         TEXT NOT NULL,
+  document_path  TEXT,
+  document_name  TEXT,
+  document_type  TEXT,
+  status         TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','approved','rejected')),
+  reviewed_by    INT REFERENCES users(id) ON DELETE SET NULL,
+  review_comment TEXT,
+  created_at     TIMESTAMP NOT NULL DEFAULT now(),
+  reviewed_at    TIMESTAMP,
+  CHECK (date_from <= date_to)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_excuses_student ON attendance_excuses(student_id, date_from, date_to);
+CREATE INDEX IF NOT EXISTS idx_attendance_excuses_status ON attendance_excuses(status);
+
+-- +goose Down
+DROP TABLE IF EXISTS attendance_excuses;
+ALTER TABLE attendance DROP CONSTRAINT IF EXISTS attendance_status_fkey;
+UPDATE attendance SET status = 'absent' WHERE status NOT IN ('present','absent','late');
+ALTER TABLE attendance ADD CONSTRAINT attendance_status_check CHECK (status IN ('present','absent','late'));
+DROP TABLE IF EXISTS attendance_statuses;
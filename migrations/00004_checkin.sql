@@ -0,0 +1,37 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS checkin_sessions (
+  id          SERIAL PRIMARY KEY,
+  course_id   INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+  lesson_date DATE NOT NULL,
+  secret      BYTEA NOT NULL,
+  opened_by   INT REFERENCES users(id) ON DELETE SET NULL,
+  opened_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+  late_after  TIMESTAMPTZ NOT NULL,
+  closes_at   TIMESTAMPTZ NOT NULL,
+  closed_at   TIMESTAMPTZ
+);
+
+-- at most one open window per course
+CREATE UNIQUE INDEX IF NOT EXISTS uq_checkin_sessions_open
+  ON checkin_sessions(course_id) WHERE closed_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS checkins (
+  session_id    INT NOT NULL REFERENCES checkin_sessions(id) ON DELETE CASCADE,
+  student_id    INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  code_step     BIGINT NOT NULL,
+  status        TEXT NOT NULL,
+  checked_in_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (session_id, student_id)
+);
+
+CREATE TABLE IF NOT EXISTS checkin_failures (
+  session_id INT NOT NULL REFERENCES checkin_sessions(id) ON DELETE CASCADE,
+  student_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  attempts   INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (session_id, student_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS checkin_failures;
+DROP TABLE IF EXISTS checkins;
+DROP TABLE IF EXISTS checkin_sessions;
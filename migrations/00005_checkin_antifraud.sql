@@ -0,0 +1,45 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS rooms (
+  id       SERIAL PRIMARY KEY,
+  name     TEXT NOT NULL UNIQUE,
+  lat      DOUBLE PRECISION NOT NULL,
+  lon      DOUBLE PRECISION NOT NULL,
+  radius_m INT NOT NULL CHECK (radius_m > 0)
+);
+
+-- per-course check-in rules; a course without a row has no extra checks
+CREATE TABLE IF NOT EXISTS checkin_policies (
+  course_id        INT PRIMARY KEY REFERENCES courses(id) ON DELETE CASCADE,
+  room_id          INT REFERENCES rooms(id) ON DELETE SET NULL,
+  require_location BOOLEAN NOT NULL DEFAULT false,
+  require_device   BOOLEAN NOT NULL DEFAULT false,
+  allowed_networks TEXT[] NOT NULL DEFAULT '{}',
+  updated_at       TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- one registered device per student, and a device belongs to one student
+CREATE TABLE IF NOT EXISTS student_devices (
+  student_id       INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  fingerprint_hash TEXT NOT NULL UNIQUE,
+  label            TEXT,
+  registered_at    TIMESTAMP NOT NULL DEFAULT now()
+);
+
+ALTER TABLE checkins
+  ADD COLUMN IF NOT EXISTS device_hash TEXT,
+  ADD COLUMN IF NOT EXISTS ip          TEXT,
+  ADD COLUMN IF NOT EXISTS lat         DOUBLE PRECISION,
+  ADD COLUMN IF NOT EXISTS lon         DOUBLE PRECISION;
+
+CREATE INDEX IF NOT EXISTS idx_checkins_device ON checkins(session_id, device_hash);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_checkins_device;
+ALTER TABLE checkins
+  DROP COLUMN IF EXISTS device_hash,
+  DROP COLUMN IF EXISTS ip,
+  DROP COLUMN IF EXISTS lat,
+  DROP COLUMN IF EXISTS lon;
+DROP TABLE IF EXISTS student_devices;
+DROP TABLE IF EXISTS checkin_policies;
+DROP TABLE IF EXISTS rooms;
@@ -0,0 +1,49 @@
+-- +goose Up
+-- academic advisor of a student; receives the student's at-risk alerts
+CREATE TABLE IF NOT EXISTS student_advisors (
+  student_id  INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  advisor_id  INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  assigned_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_student_advisors_advisor ON student_advisors(advisor_id);
+
+-- kind = absence_streak: threshold is the number of absences in a row
+-- kind = rate_below:     threshold is a rate in [0,1], checked once min_lessons are counted
+CREATE TABLE IF NOT EXISTS risk_rules (
+  id          SERIAL PRIMARY KEY,
+  name        TEXT NOT NULL,
+  kind        TEXT NOT NULL CHECK (kind IN ('absence_streak','rate_below')),
+  threshold   DOUBLE PRECISION NOT NULL,
+  min_lessons INT NOT NULL DEFAULT 0,
+  course_id   INT REFERENCES courses(id) ON DELETE CASCADE, -- NULL = every course
+  active      BOOLEAN NOT NULL DEFAULT true,
+  created_at  TIMESTAMP NOT NULL DEFAULT now()
+);
+
+INSERT INTO risk_rules(name, kind, threshold, min_lessons) VALUES
+  ('3 absences in a row', 'absence_streak', 3, 0),
+  ('Attendance below 70%', 'rate_below', 0.7, 5);
+
+CREATE TABLE IF NOT EXISTS risk_alerts (
+  id              SERIAL PRIMARY KEY,
+  rule_id         INT NOT NULL REFERENCES risk_rules(id) ON DELETE CASCADE,
+  course_id       INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+  student_id      INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  value           DOUBLE PRECISION NOT NULL,
+  detail          TEXT NOT NULL,
+  created_at      TIMESTAMP NOT NULL DEFAULT now(),
+  notified_at     TIMESTAMP,
+  acknowledged_by INT REFERENCES users(id) ON DELETE SET NULL,
+  acknowledged_at TIMESTAMP,
+  resolved_at     TIMESTAMP
+);
+
+-- one open alert per rule, course and student
+CREATE UNIQUE INDEX IF NOT EXISTS uq_risk_alerts_open
+  ON risk_alerts(rule_id, course_id, student_id) WHERE resolved_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS risk_alerts;
+DROP TABLE IF EXISTS risk_rules;
+DROP TABLE IF EXISTS student_advisors;
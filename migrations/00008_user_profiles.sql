@@ -0,0 +1,23 @@
+-- +goose Up
+-- registrar data for students (student_number .. year_of_study) and staff
+-- (department, title); empty values are stored as NULL
+CREATE TABLE IF NOT EXISTS user_profiles (
+  user_id        INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  student_number TEXT UNIQUE,
+  group_name     TEXT,
+  year_of_study  SMALLINT CHECK (year_of_study BETWEEN 1 AND 10),
+  faculty        TEXT,
+  phone          TEXT,
+  language       TEXT,
+  department     TEXT,
+  title          TEXT,
+  avatar_path    TEXT,
+  updated_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_profiles_group ON user_profiles(group_name);
+CREATE INDEX IF NOT EXISTS idx_user_profiles_faculty ON user_profiles(faculty);
+CREATE INDEX IF NOT EXISTS idx_user_profiles_department ON user_profiles(department);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_profiles;
@@ -0,0 +1,47 @@
+-- +goose Up
+-- academic groups/cohorts; a student belongs to at most one group
+CREATE TABLE IF NOT EXISTS student_groups (
+  id            SERIAL PRIMARY KEY,
+  name          TEXT NOT NULL UNIQUE,
+  faculty       TEXT,
+  year_of_study SMALLINT CHECK (year_of_study BETWEEN 1 AND 10),
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS group_members (
+  student_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  group_id   INT NOT NULL REFERENCES student_groups(id) ON DELETE CASCADE,
+  joined_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_members_group ON group_members(group_id);
+
+-- groups taking a course as a whole
+CREATE TABLE IF NOT EXISTS course_groups (
+  course_id   INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+  group_id    INT NOT NULL REFERENCES student_groups(id) ON DELETE CASCADE,
+  enrolled_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (course_id, group_id)
+);
+
+-- the group an enrollment came from; NULL = enrolled individually.
+-- Deleting a group keeps its students enrolled as individuals.
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS group_id INT REFERENCES student_groups(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_enrollments_group ON enrollments(group_id) WHERE group_id IS NOT NULL;
+
+-- turn the free-text profile groups into real groups
+INSERT INTO student_groups(name)
+SELECT DISTINCT group_name FROM user_profiles WHERE group_name IS NOT NULL
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO group_members(student_id, group_id)
+SELECT p.user_id, g.id
+FROM user_profiles p
+JOIN student_groups g ON g.name = p.group_name
+ON CONFLICT (student_id) DO NOTHING;
+
+-- +goose Down
+ALTER TABLE enrollments DROP COLUMN IF EXISTS group_id;
+DROP TABLE IF EXISTS course_groups;
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS student_groups;
@@ -0,0 +1,36 @@
+-- +goose Up
+-- how students may join a course; no row = closed, unlimited
+CREATE TABLE IF NOT EXISTS enrollment_policies (
+  course_id  INT PRIMARY KEY REFERENCES courses(id) ON DELETE CASCADE,
+  policy     TEXT NOT NULL DEFAULT 'closed' CHECK (policy IN ('closed','open','approval','key')),
+  key_hash   TEXT,                       -- bcrypt hash of the enrollment key (policy = key)
+  capacity   INT CHECK (capacity > 0),   -- NULL = unlimited
+  opens_at   TIMESTAMPTZ,
+  closes_at  TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- pending:    waiting for a teacher (policy = approval)
+-- waitlisted: course was full; promoted first come, first served (queued_at)
+-- enrolled:   turned into an enrollment (directly, after approval or from the waitlist)
+CREATE TABLE IF NOT EXISTS enrollment_requests (
+  id         SERIAL PRIMARY KEY,
+  course_id  INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+  student_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  status     TEXT NOT NULL CHECK (status IN ('pending','waitlisted','enrolled','rejected','cancelled')),
+  comment    TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  queued_at  TIMESTAMPTZ,
+  decided_by INT REFERENCES users(id) ON DELETE SET NULL,
+  decided_at TIMESTAMPTZ
+);
+
+-- one open request per student and course
+CREATE UNIQUE INDEX IF NOT EXISTS uq_enrollment_requests_open
+  ON enrollment_requests(course_id, student_id) WHERE status IN ('pending','waitlisted');
+CREATE INDEX IF NOT EXISTS idx_enrollment_requests_waitlist
+  ON enrollment_requests(course_id, queued_at) WHERE status = 'waitlisted';
+
+-- +goose Down
+DROP TABLE IF EXISTS enrollment_requests;
+DROP TABLE IF EXISTS enrollment_policies;
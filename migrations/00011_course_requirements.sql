@@ -0,0 +1,46 @@
+-- +goose Up
+-- final result of a course; completed_at set = the student finished the course
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS grade        NUMERIC(5,2) CHECK (grade BETWEEN 0 AND 100);
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ;
+
+-- rules a student must satisfy to be enrolled in course_id
+-- prerequisite: completed required_course_id (with at least min_grade if set)
+-- corequisite:  enrolled in or completed required_course_id
+-- year:         year of study >= min_year
+-- group:        member of group_id
+CREATE TABLE IF NOT EXISTS course_requirements (
+  id                 SERIAL PRIMARY KEY,
+  course_id          INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+  kind               TEXT NOT NULL CHECK (kind IN ('prerequisite','corequisite','year','group')),
+  required_course_id INT REFERENCES courses(id) ON DELETE CASCADE,
+  min_grade          NUMERIC(5,2) CHECK (min_grade BETWEEN 0 AND 100),
+  min_year           SMALLINT CHECK (min_year BETWEEN 1 AND 10),
+  group_id           INT REFERENCES student_groups(id) ON DELETE CASCADE,
+  created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CHECK (required_course_id IS DISTINCT FROM course_id),
+  CHECK (
+    (kind IN ('prerequisite','corequisite') AND required_course_id IS NOT NULL) OR
+    (kind = 'year' AND min_year IS NOT NULL) OR
+    (kind = 'group' AND group_id IS NOT NULL)
+  )
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_requirements_course ON course_requirements(course_id);
+
+-- weekly timetable of a course, used for schedule-conflict checks
+CREATE TABLE IF NOT EXISTS course_schedule (
+  id        SERIAL PRIMARY KEY,
+  course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+  weekday   SMALLINT NOT NULL CHECK (weekday BETWEEN 1 AND 7), -- 1 = Monday
+  starts_at TIME NOT NULL,
+  ends_at   TIME NOT NULL,
+  CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_schedule_course ON course_schedule(course_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS course_schedule;
+DROP TABLE IF EXISTS course_requirements;
+ALTER TABLE enrollments DROP COLUMN IF EXISTS completed_at;
+ALTER TABLE enrollments DROP COLUMN IF EXISTS grade;
@@ -0,0 +1,69 @@
+-- +goose Up
+-- enrollments are never deleted any more; they change status instead.
+-- active:    attends the course
+-- dropped:   left the course (self-service or moved to another group)
+-- withdrawn: removed by staff, group unenrolled or account deleted
+-- completed: finished with a final grade
+-- failed:    finished without passing
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active'
+  CHECK (status IN ('active','dropped','withdrawn','completed','failed'));
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS status_reason     TEXT NOT NULL DEFAULT '';
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS status_changed_by INT REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+UPDATE enrollments SET status = 'completed', status_changed_at = completed_at WHERE completed_at IS NOT NULL;
+ALTER TABLE enrollments DROP COLUMN IF EXISTS completed_at;
+
+CREATE INDEX IF NOT EXISTS idx_enrollments_student_status ON enrollments(student_id, status);
+CREATE INDEX IF NOT EXISTS idx_enrollments_course_active ON enrollments(course_id) WHERE status = 'active';
+
+-- every status an enrollment went through
+CREATE TABLE IF NOT EXISTS enrollment_history (
+  id            SERIAL PRIMARY KEY,
+  enrollment_id INT NOT NULL REFERENCES enrollments(id) ON DELETE CASCADE,
+  old_status    TEXT,
+  status        TEXT NOT NULL,
+  reason        TEXT NOT NULL DEFAULT '',
+  changed_by    INT REFERENCES users(id) ON DELETE SET NULL,
+  changed_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_enrollment_history_enrollment ON enrollment_history(enrollment_id, changed_at);
+
+INSERT INTO enrollment_history(enrollment_id, status, changed_at)
+SELECT id, 'active', enrolled_at FROM enrollments;
+INSERT INTO enrollment_history(enrollment_id, old_status, status, changed_at)
+SELECT id, 'active', status, status_changed_at FROM enrollments WHERE status <> 'active';
+
+-- enrollments are written from many places (manual, self-service, groups,
+-- imports); the trigger keeps the history complete for all of them
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION log_enrollment_status() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'INSERT' OR NEW.status IS DISTINCT FROM OLD.status THEN
+    INSERT INTO enrollment_history(enrollment_id, old_status, status, reason, changed_by)
+    VALUES (NEW.id, CASE WHEN TG_OP = 'UPDATE' THEN OLD.status END, NEW.status, NEW.status_reason, NEW.status_changed_by);
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS enrollments_status_history ON enrollments;
+CREATE TRIGGER enrollments_status_history
+  AFTER INSERT OR UPDATE OF status ON enrollments
+  FOR EACH ROW EXECUTE FUNCTION log_enrollment_status();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS enrollments_status_history ON enrollments;
+DROP FUNCTION IF EXISTS log_enrollment_status();
+DROP TABLE IF EXISTS enrollment_history;
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ;
+UPDATE enrollments SET completed_at = status_changed_at WHERE status IN ('completed','failed');
+DELETE FROM enrollments WHERE status IN ('dropped','withdrawn');
+DROP INDEX IF EXISTS idx_enrollments_course_active;
+DROP INDEX IF EXISTS idx_enrollments_student_status;
+ALTER TABLE enrollments DROP COLUMN IF EXISTS status_changed_at;
+ALTER TABLE enrollments DROP COLUMN IF EXISTS status_changed_by;
+ALTER TABLE enrollments DROP COLUMN IF EXISTS status_reason;
+ALTER TABLE enrollments DROP COLUMN IF EXISTS status;
@@ -0,0 +1,36 @@
+-- +goose Up
+-- shown on transcripts and certificates
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS term    TEXT NOT NULL DEFAULT '';            -- e.g. "Fall 2026"
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS credits NUMERIC(4,1) NOT NULL DEFAULT 0 CHECK (credits >= 0);
+
+-- completion certificates. Names, titles and results are copied at issue
+-- time and covered by the signature, so a certificate keeps verifying the
+-- same way even if the course is renamed later.
+CREATE TABLE IF NOT EXISTS certificates (
+  id              SERIAL PRIMARY KEY,
+  code            TEXT NOT NULL UNIQUE,
+  enrollment_id   INT NOT NULL REFERENCES enrollments(id) ON DELETE CASCADE,
+  student_id      INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  course_id       INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+  student_name    TEXT NOT NULL,
+  course_title    TEXT NOT NULL,
+  teacher_name    TEXT NOT NULL DEFAULT '',
+  term            TEXT NOT NULL DEFAULT '',
+  credits         NUMERIC(4,1) NOT NULL DEFAULT 0,
+  grade           NUMERIC(5,2) NOT NULL,
+  attendance_rate NUMERIC(5,4),               -- 0..1, NULL when no lessons were marked
+  completed_at    TIMESTAMPTZ NOT NULL,
+  issued_at       TIMESTAMPTZ NOT NULL,
+  signature       TEXT NOT NULL,              -- hex HMAC-SHA256 of the fields above
+  revoked_at      TIMESTAMPTZ,
+  revoke_reason   TEXT NOT NULL DEFAULT ''
+);
+
+-- one valid certificate per enrollment; revoked ones stay verifiable as revoked
+CREATE UNIQUE INDEX IF NOT EXISTS uq_certificates_valid ON certificates(enrollment_id) WHERE revoked_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_certificates_student ON certificates(student_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS certificates;
+ALTER TABLE courses DROP COLUMN IF EXISTS credits;
+ALTER TABLE courses DROP COLUMN IF EXISTS term;
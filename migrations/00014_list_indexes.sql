@@ -0,0 +1,18 @@
+-- +goose Up
+-- users had no creation time; existing accounts get the migration time
+ALTER TABLE users ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- keyset pagination walks (sort column, id); index the common sorts
+CREATE INDEX IF NOT EXISTS idx_users_full_name ON users(full_name, id);
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_courses_title ON courses(title, id);
+CREATE INDEX IF NOT EXISTS idx_attendance_course_date ON attendance(course_id, lesson_date, id);
+CREATE INDEX IF NOT EXISTS idx_attendance_student_date ON attendance(student_id, lesson_date, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_attendance_student_date;
+DROP INDEX IF EXISTS idx_attendance_course_date;
+DROP INDEX IF EXISTS idx_courses_title;
+DROP INDEX IF EXISTS idx_users_created_at;
+DROP INDEX IF EXISTS idx_users_full_name;
+ALTER TABLE users DROP COLUMN IF EXISTS created_at;
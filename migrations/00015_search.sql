@@ -0,0 +1,53 @@
+-- +goose Up
+-- trigram matching for typos and partial names/emails
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- texts teachers publish in a course (lecture notes, reading lists)
+CREATE TABLE IF NOT EXISTS course_materials (
+  id          SERIAL PRIMARY KEY,
+  course_id   INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+  title       TEXT NOT NULL,
+  body        TEXT NOT NULL DEFAULT '',
+  created_by  INT REFERENCES users(id) ON DELETE SET NULL,
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_materials_course ON course_materials(course_id);
+
+-- full-text vectors, kept up to date by Postgres. Names and titles use the
+-- 'simple' configuration (no stemming, any language); emails are split at
+-- punctuation so "ivan" finds ivan.petrov@uni.edu. Materials are prose and
+-- get English stemming.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS search_tsv tsvector
+  GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', full_name), 'A') ||
+    setweight(to_tsvector('simple', translate(email, '@._-+', '     ')), 'B')
+  ) STORED;
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS search_tsv tsvector
+  GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', title), 'A') ||
+    setweight(to_tsvector('simple', term), 'B')
+  ) STORED;
+ALTER TABLE course_materials ADD COLUMN IF NOT EXISTS search_tsv tsvector
+  GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', title), 'A') ||
+    setweight(to_tsvector('english', body), 'B')
+  ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_users_search ON users USING GIN (search_tsv);
+CREATE INDEX IF NOT EXISTS idx_courses_search ON courses USING GIN (search_tsv);
+CREATE INDEX IF NOT EXISTS idx_course_materials_search ON course_materials USING GIN (search_tsv);
+
+CREATE INDEX IF NOT EXISTS idx_users_full_name_trgm ON users USING GIN (full_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON users USING GIN (email gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_courses_title_trgm ON courses USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_course_materials_title_trgm ON course_materials USING GIN (title gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_course_materials_title_trgm;
+DROP INDEX IF EXISTS idx_courses_title_trgm;
+DROP INDEX IF EXISTS idx_users_email_trgm;
+DROP INDEX IF EXISTS idx_users_full_name_trgm;
+ALTER TABLE courses DROP COLUMN IF EXISTS search_tsv;
+ALTER TABLE users DROP COLUMN IF EXISTS search_tsv;
+DROP TABLE IF EXISTS course_materials;
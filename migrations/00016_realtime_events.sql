@@ -0,0 +1,75 @@
+-- +goose Up
+-- attendance and enrollment changes are announced on the lms_events channel
+-- for the real-time API. Every API instance LISTENs, so a change made through
+-- one of them reaches the clients of all. Being triggers, these fire for every
+-- writer (roll calls, check-ins, groups, imports), not only the statements in
+-- the repositories. Payloads stay small, without notes: clients refetch what
+-- they need.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_attendance() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('lms_events', json_build_object(
+    'type', 'attendance',
+    'course_id', NEW.course_id,
+    'student_id', NEW.student_id,
+    'data', json_build_object(
+      'id', NEW.id,
+      'lesson_date', NEW.lesson_date,
+      'status', NEW.status,
+      'old_status', CASE WHEN TG_OP = 'UPDATE' THEN OLD.status END
+    )
+  )::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS attendance_notify_insert ON attendance;
+CREATE TRIGGER attendance_notify_insert
+  AFTER INSERT ON attendance
+  FOR EACH ROW EXECUTE FUNCTION notify_attendance();
+
+DROP TRIGGER IF EXISTS attendance_notify_update ON attendance;
+CREATE TRIGGER attendance_notify_update
+  AFTER UPDATE ON attendance
+  FOR EACH ROW
+  WHEN (OLD.status IS DISTINCT FROM NEW.status OR OLD.note IS DISTINCT FROM NEW.note)
+  EXECUTE FUNCTION notify_attendance();
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_enrollment() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('lms_events', json_build_object(
+    'type', 'enrollment',
+    'course_id', NEW.course_id,
+    'student_id', NEW.student_id,
+    'data', json_build_object(
+      'status', NEW.status,
+      'old_status', CASE WHEN TG_OP = 'UPDATE' THEN OLD.status END,
+      'reason', NEW.status_reason
+    )
+  )::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS enrollments_notify_insert ON enrollments;
+CREATE TRIGGER enrollments_notify_insert
+  AFTER INSERT ON enrollments
+  FOR EACH ROW EXECUTE FUNCTION notify_enrollment();
+
+DROP TRIGGER IF EXISTS enrollments_notify_update ON enrollments;
+CREATE TRIGGER enrollments_notify_update
+  AFTER UPDATE OF status ON enrollments
+  FOR EACH ROW
+  WHEN (OLD.status IS DISTINCT FROM NEW.status)
+  EXECUTE FUNCTION notify_enrollment();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS enrollments_notify_update ON enrollments;
+DROP TRIGGER IF EXISTS enrollments_notify_insert ON enrollments;
+DROP FUNCTION IF EXISTS notify_enrollment();
+DROP TRIGGER IF EXISTS attendance_notify_update ON attendance;
+DROP TRIGGER IF EXISTS attendance_notify_insert ON attendance;
+DROP FUNCTION IF EXISTS notify_attendance();
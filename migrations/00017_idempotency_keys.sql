@@ -0,0 +1,20 @@
+-- +goose Up
+-- responses of POST/PATCH requests sent with an Idempotency-Key header, so a
+-- retry gets the first answer instead of doing the work twice. A row without
+-- status is a request still running. Rows are kept until expires_at.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+  user_id      INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  key          TEXT NOT NULL,
+  request_hash TEXT NOT NULL,                 -- hex sha256 of method, path and body
+  status       INT,
+  content_type TEXT NOT NULL DEFAULT '',
+  body         BYTEA,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at   TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires ON idempotency_keys(expires_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS idempotency_keys;
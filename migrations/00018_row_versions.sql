@@ -0,0 +1,40 @@
+-- +goose Up
+-- row versions for optimistic concurrency: reads return the version as an
+-- ETag and updates name the version they are based on (If-Match), so a
+-- teacher cannot silently overwrite a mark another teacher just changed.
+-- The triggers bump it on every real change, whoever writes the row. They
+-- list the columns: users has generated search columns, which a BEFORE
+-- trigger may not look at.
+ALTER TABLE users      ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+ALTER TABLE attendance ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION bump_version() RETURNS trigger AS $$
+BEGIN
+  NEW.version := OLD.version + 1;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS users_bump_version ON users;
+CREATE TRIGGER users_bump_version
+  BEFORE UPDATE ON users
+  FOR EACH ROW
+  WHEN ((OLD.email, OLD.password_hash, OLD.full_name, OLD.role_id, OLD.active, OLD.deleted_at)
+        IS DISTINCT FROM (NEW.email, NEW.password_hash, NEW.full_name, NEW.role_id, NEW.active, NEW.deleted_at))
+  EXECUTE FUNCTION bump_version();
+
+DROP TRIGGER IF EXISTS attendance_bump_version ON attendance;
+CREATE TRIGGER attendance_bump_version
+  BEFORE UPDATE ON attendance
+  FOR EACH ROW
+  WHEN ((OLD.lesson_date, OLD.status, OLD.note) IS DISTINCT FROM (NEW.lesson_date, NEW.status, NEW.note))
+  EXECUTE FUNCTION bump_version();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS attendance_bump_version ON attendance;
+DROP TRIGGER IF EXISTS users_bump_version ON users;
+DROP FUNCTION IF EXISTS bump_version();
+ALTER TABLE attendance DROP COLUMN IF EXISTS version;
+ALTER TABLE users      DROP COLUMN IF EXISTS version;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- token buckets of the Postgres rate limit store, one row per route group
+-- and client. tat is when the bucket will be full again; rows in the past are
+-- purged. Losing them in a crash only resets the limits, so skip the WAL.
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limits (
+  key TEXT PRIMARY KEY,          -- "<group>:ip:<addr>" or "<group>:user:<id>"
+  tat TIMESTAMPTZ NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS rate_limits;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- a certificate only stands for the result it was issued for: revoke it
+-- whenever the enrollment's status or grade changes, whoever changes it
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION revoke_enrollment_certificates() RETURNS trigger AS $$
+BEGIN
+  UPDATE certificates
+  SET revoked_at = now(),
+      revoke_reason = CASE WHEN NEW.status IS DISTINCT FROM OLD.status THEN 'enrollment changed to ' || NEW.status
+                           ELSE 'grade changed' END
+  WHERE enrollment_id = NEW.id AND revoked_at IS NULL;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS enrollments_revoke_certificates ON enrollments;
+CREATE TRIGGER enrollments_revoke_certificates
+  AFTER UPDATE OF status, grade ON enrollments
+  FOR EACH ROW
+  WHEN (NEW.status IS DISTINCT FROM OLD.status OR NEW.grade IS DISTINCT FROM OLD.grade)
+  EXECUTE FUNCTION revoke_enrollment_certificates();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS enrollments_revoke_certificates ON enrollments;
+DROP FUNCTION IF EXISTS revoke_enrollment_certificates();
@@ -0,0 +1,7 @@
+-- +goose Up
+-- deleting an account now also ends its group membership; clear the
+-- memberships of accounts deleted before, so group enrollments skip them
+DELETE FROM group_members m USING users u WHERE u.id = m.student_id AND u.deleted_at IS NOT NULL;
+
+-- +goose Down
+-- the memberships are gone with the accounts' data
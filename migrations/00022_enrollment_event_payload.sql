@@ -0,0 +1,39 @@
+-- +goose Up
+-- enrollment events no longer carry status_reason: it is free text that can
+-- be long (and push the payload past the NOTIFY limit) and is not meant for
+-- every subscriber of the course. Clients read it from the transcript.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_enrollment() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('lms_events', json_build_object(
+    'type', 'enrollment',
+    'course_id', NEW.course_id,
+    'student_id', NEW.student_id,
+    'data', json_build_object(
+      'status', NEW.status,
+      'old_status', CASE WHEN TG_OP = 'UPDATE' THEN OLD.status END
+    )
+  )::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_enrollment() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('lms_events', json_build_object(
+    'type', 'enrollment',
+    'course_id', NEW.course_id,
+    'student_id', NEW.student_id,
+    'data', json_build_object(
+      'status', NEW.status,
+      'old_status', CASE WHEN TG_OP = 'UPDATE' THEN OLD.status END,
+      'reason', NEW.status_reason
+    )
+  )::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- one-time tickets that open an event stream (SSE or WebSocket) for browsers,
+-- which cannot send an Authorization header there. Only the SHA-256 of a
+-- ticket is stored; a ticket is deleted when used and worthless after
+-- expires_at. Losing them in a crash only means asking for a new one.
+CREATE UNLOGGED TABLE IF NOT EXISTS stream_tickets (
+  ticket_hash TEXT PRIMARY KEY,    -- hex sha256
+  user_id     INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  expires_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_stream_tickets_expires ON stream_tickets(expires_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS stream_tickets;